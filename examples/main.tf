@@ -20,11 +20,12 @@ resource "xsoar_ha_group" "ha1" {
 }
 
 resource "xsoar_host" "host1" {
-  name          = "host.example.com"
-  ha_group_name = xsoar_ha_group.ha1.name
-  server_url    = "host.example.com:22"
-  ssh_user      = "vagrant"
-  ssh_key       = file("/path/to/file")
+  name             = "host.example.com"
+  ha_group_name    = xsoar_ha_group.ha1.name
+  server_url       = "host.example.com:22"
+  ssh_user         = "vagrant"
+  ssh_key          = file("/path/to/file")
+  known_hosts_file = "~/.ssh/known_hosts"
 }
 
 resource "xsoar_account" "acc1" {
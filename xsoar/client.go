@@ -0,0 +1,116 @@
+package xsoar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// apiError is returned for responses outside the 2xx range
+type apiError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+func (e apiError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Status, strings.TrimSpace(string(e.Body)))
+}
+
+// isNotFound reports whether err is an API response saying the object does not exist
+func isNotFound(err error) bool {
+	e, ok := err.(apiError)
+	return ok && e.StatusCode == http.StatusNotFound
+}
+
+// accountPath routes path to the tenant when an account is set
+func accountPath(account types.String, path string) string {
+	if account.Null || account.Unknown || len(account.Value) == 0 {
+		return path
+	}
+	return "/acc_" + account.Value + path
+}
+
+// doRequest calls an endpoint the generated client does not cover, reusing its server, headers and
+// HTTP client. The body is sent as JSON and the response is decoded into result, or copied as-is
+// when result is a *[]byte.
+func (p provider) doRequest(ctx context.Context, method string, path string, body interface{}, result interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(b)
+	}
+	return p.send(ctx, method, path, "application/json", reader, result)
+}
+
+// doUpload posts content as the multipart file field the XSOAR import endpoints expect
+func (p provider) doUpload(ctx context.Context, path string, field string, filename string, content []byte, result interface{}) (*http.Response, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile(field, filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = part.Write(content); err != nil {
+		return nil, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+	return p.send(ctx, http.MethodPost, path, w.FormDataContentType(), &buf, result)
+}
+
+func (p provider) send(ctx context.Context, method string, path string, contentType string, body io.Reader, result interface{}) (*http.Response, error) {
+	cfg := p.client.GetConfig()
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(cfg.Servers[0].URL, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range cfg.DefaultHeader {
+		req.Header.Set(key, value)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if cfg.UserAgent != "" {
+		req.Header.Set("User-Agent", cfg.UserAgent)
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	httpResponse, err := httpClient.Do(req)
+	if err != nil {
+		return httpResponse, err
+	}
+	b, err := io.ReadAll(httpResponse.Body)
+	_ = httpResponse.Body.Close()
+	httpResponse.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return httpResponse, err
+	}
+	if httpResponse.StatusCode >= 300 {
+		return httpResponse, apiError{StatusCode: httpResponse.StatusCode, Status: httpResponse.Status, Body: b}
+	}
+	switch out := result.(type) {
+	case nil:
+	case *[]byte:
+		*out = b
+	default:
+		if err = json.Unmarshal(b, out); err != nil {
+			return httpResponse, fmt.Errorf("could not decode response from %s: %w", path, err)
+		}
+	}
+	return httpResponse, nil
+}
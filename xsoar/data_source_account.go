@@ -41,6 +41,16 @@ func (r dataSourceAccountType) GetSchema(_ context.Context) (tfsdk.Schema, diag.
 				Type:     types.StringType,
 				Computed: true,
 			},
+			"timeout": {
+				Type:     types.Int64Type,
+				Computed: false,
+				Optional: true,
+			},
+			"concurrency_limit": {
+				Type:     types.Int64Type,
+				Computed: false,
+				Optional: true,
+			},
 		},
 	}, nil
 }
@@ -56,8 +66,11 @@ type dataSourceAccount struct {
 }
 
 func (r dataSourceAccount) Read(ctx context.Context, req tfsdk.ReadDataSourceRequest, resp *tfsdk.ReadDataSourceResponse) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
 	// Declare struct that this function will set to this data source's config
-	var config Account
+	var config AccountDataSource
 	diags := req.Config.Get(ctx, &config)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -130,7 +143,7 @@ func (r dataSourceAccount) Read(ctx context.Context, req tfsdk.ReadDataSourceReq
 	}
 
 	// Map response body to resource schema attribute
-	config = Account{
+	config = AccountDataSource{
 		Name:          types.String{Value: account["displayName"].(string)},
 		HostGroupName: types.String{Value: hostGroupName},
 		HostGroupId:   types.String{Value: account["hostGroupId"].(string)},
@@ -143,10 +156,12 @@ func (r dataSourceAccount) Read(ctx context.Context, req tfsdk.ReadDataSourceReq
 		AccountRoles: types.Set{
 			Unknown:  false,
 			Null:     false,
-			Elems:    propagationLabels,
+			Elems:    roles,
 			ElemType: types.StringType,
 		},
-		Id: types.String{Value: account["id"].(string)},
+		Id:          types.String{Value: account["id"].(string)},
+		Timeout:     config.Timeout,
+		Concurrency: config.Concurrency,
 	}
 
 	// Set state
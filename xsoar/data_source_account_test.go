@@ -3,7 +3,7 @@ package xsoar
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -18,7 +18,7 @@ func TestAccAccountDataSource_basic(t *testing.T) {
 		PreCheck: func() { testAccAccountDataSourcePreCheck(t) },
 		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
 			"xsoar": func() (tfprotov6.ProviderServer, error) {
-				return tfsdk.NewProtocol6Server(New()), nil
+				return providerserver.NewProtocol6WithError(New()())()
 			},
 		},
 		CheckDestroy: testAccCheckAccountDataSourceDestroy(rName),
@@ -31,6 +31,25 @@ func TestAccAccountDataSource_basic(t *testing.T) {
 	})
 }
 
+func TestAccountDataSource_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	groupId := m.addHAGroup("mockgroup")
+	m.addAccount("mockacc", groupId)
+	tf := newMockTerraform(t, m)
+
+	state := tf.mustReadDataSource("xsoar_account", map[string]interface{}{"name": "mockacc"})
+	if state["id"] != m.account("mockacc")["id"] {
+		t.Fatalf("expected account ID %v, got %v", m.account("mockacc")["id"], state["id"])
+	}
+	if state["host_group_name"] != "mockgroup" || state["host_group_id"] != groupId {
+		t.Fatalf("unexpected host group %v (%v)", state["host_group_name"], state["host_group_id"])
+	}
+	if diffs := mockDiff("", []interface{}{"Administrator"}, state["account_roles"]); len(diffs) > 0 {
+		t.Fatalf("unexpected roles: %s", diffs)
+	}
+}
+
 func testAccAccountDataSourcePreCheck(t *testing.T) {}
 
 func testAccCheckAccountDataSourceDestroy(r string) resource.TestCheckFunc {
@@ -0,0 +1,29 @@
+package xsoar
+
+import (
+	"testing"
+)
+
+func TestAccountsDataSource_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	groupId := m.addHAGroup("mockgroup")
+	m.addAccount("mockacc1", groupId)
+	m.addAccount("mockacc2", groupId)
+	tf := newMockTerraform(t, m)
+
+	state := tf.mustReadDataSource("xsoar_accounts", map[string]interface{}{})
+	accounts := mockSlice(state["accounts"])
+	if len(accounts) != 2 {
+		t.Fatalf("expected 2 accounts, got %v", state["accounts"])
+	}
+	for i, name := range []string{"mockacc1", "mockacc2"} {
+		account := mockObject(accounts[i])
+		if account["name"] != name || account["host_group_name"] != "mockgroup" {
+			t.Fatalf("unexpected account %v", account)
+		}
+		if diffs := mockDiff("", []interface{}{"Administrator"}, account["account_roles"]); len(diffs) > 0 {
+			t.Fatalf("unexpected roles: %s", diffs)
+		}
+	}
+}
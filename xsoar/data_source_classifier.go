@@ -67,8 +67,11 @@ type dataSourceClassifier struct {
 }
 
 func (r dataSourceClassifier) Read(ctx context.Context, req tfsdk.ReadDataSourceRequest, resp *tfsdk.ReadDataSourceResponse) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
 	// Declare struct that this function will set to this data source's config
-	var config Classifier
+	var config ClassifierDataSource
 	diags := req.Config.Get(ctx, &config)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -104,14 +107,6 @@ func (r dataSourceClassifier) Read(ctx context.Context, req tfsdk.ReadDataSource
 	}
 
 	// Map response body to resource schema attribute
-	defaultIncidentType, err := json.Marshal(classifier.GetDefaultIncidentType())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error marshalling json",
-			"Could not marshal json: "+err.Error(),
-		)
-		return
-	}
 	keyTypeMap, err := json.Marshal(classifier.GetKeyTypeMap())
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -128,16 +123,16 @@ func (r dataSourceClassifier) Read(ctx context.Context, req tfsdk.ReadDataSource
 		)
 		return
 	}
-	result := Classifier{
+	result := ClassifierDataSource{
 		Name:              types.String{Value: classifier.GetName()},
 		Id:                types.String{Value: classifier.GetId()},
 		PropagationLabels: types.Set{Elems: propLabels, ElemType: types.StringType},
 		Account:           config.Account,
 	}
-	if v := string(defaultIncidentType); v == "null" {
-		result.DefaultIncidentType = types.String{Null: true}
+	if v, ok := classifier.GetDefaultIncidentTypeOk(); ok {
+		result.DefaultIncidentType = types.String{Value: *v}
 	} else {
-		result.DefaultIncidentType = types.String{Value: v}
+		result.DefaultIncidentType = types.String{Null: true}
 	}
 	if v := string(keyTypeMap); v == "null" {
 		result.KeyTypeMap = types.String{Null: true}
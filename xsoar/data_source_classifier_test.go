@@ -3,7 +3,7 @@ package xsoar
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -18,7 +18,7 @@ func TestAccClassifierDataSource_basic(t *testing.T) {
 		PreCheck: func() { testAccClassifierDataSourcePreCheck(t) },
 		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
 			"xsoar": func() (tfprotov6.ProviderServer, error) {
-				return tfsdk.NewProtocol6Server(New()), nil
+				return providerserver.NewProtocol6WithError(New()())()
 			},
 		},
 		CheckDestroy: testAccCheckClassifierDataSourceDestroy(rName),
@@ -31,6 +31,26 @@ func TestAccClassifierDataSource_basic(t *testing.T) {
 	})
 }
 
+func TestClassifierDataSource_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	id := m.putObject("", "classifiers", map[string]interface{}{
+		"name":                "mockclassifier",
+		"type":                "classification",
+		"defaultIncidentType": "Phishing",
+		"keyTypeMap":          map[string]interface{}{"phishing": "Phishing"},
+	})
+	tf := newMockTerraform(t, m)
+
+	state := tf.mustReadDataSource("xsoar_classifier", map[string]interface{}{"name": "mockclassifier"})
+	if state["id"] != id {
+		t.Fatalf("expected classifier ID %s, got %v", id, state["id"])
+	}
+	if state["default_incident_type"] != "Phishing" || state["key_type_map"] != `{"phishing":"Phishing"}` {
+		t.Fatalf("unexpected classifier %v", state)
+	}
+}
+
 func testAccClassifierDataSourcePreCheck(t *testing.T) {}
 
 func testAccCheckClassifierDataSourceDestroy(r string) resource.TestCheckFunc {
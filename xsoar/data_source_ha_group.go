@@ -2,6 +2,7 @@ package xsoar
 
 import (
 	"context"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -51,8 +52,11 @@ type dataSourceHAGroup struct {
 }
 
 func (r dataSourceHAGroup) Read(ctx context.Context, req tfsdk.ReadDataSourceRequest, resp *tfsdk.ReadDataSourceResponse) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
 	// Declare struct that this function will set to this data source's config
-	var config HAGroup
+	var config HAGroupDataSource
 	diags := req.Config.Get(ctx, &config)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -84,8 +88,17 @@ func (r dataSourceHAGroup) Read(ctx context.Context, req tfsdk.ReadDataSourceReq
 		return
 	}
 
+	var accountIds []attr.Value
+	for _, a := range haGroup.GetAccountIds() {
+		accountIds = append(accountIds, types.String{Value: a})
+	}
+	var hostIds []attr.Value
+	for _, h := range haGroup.GetHostIds() {
+		hostIds = append(hostIds, types.String{Value: h})
+	}
+
 	// Map response body to resource schema attribute
-	config = HAGroup{
+	config = HAGroupDataSource{
 		Name:               types.String{Value: haGroup.GetName()},
 		Id:                 types.String{Value: haGroup.GetId()},
 		ElasticsearchUrl:   types.String{Value: haGroup.GetElasticsearchAddress()},
@@ -93,13 +106,13 @@ func (r dataSourceHAGroup) Read(ctx context.Context, req tfsdk.ReadDataSourceReq
 		AccountIds: types.Set{
 			Unknown:  false,
 			Null:     false,
-			Elems:    nil,
+			Elems:    accountIds,
 			ElemType: types.StringType,
 		},
 		HostIds: types.Set{
 			Unknown:  false,
 			Null:     false,
-			Elems:    nil,
+			Elems:    hostIds,
 			ElemType: types.StringType,
 		},
 	}
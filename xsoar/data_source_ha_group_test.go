@@ -3,7 +3,7 @@ package xsoar
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -18,7 +18,7 @@ func TestAccHAGroupDataSource_basic(t *testing.T) {
 		PreCheck: func() { testAccHAGroupDataSourcePreCheck(t) },
 		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
 			"xsoar": func() (tfprotov6.ProviderServer, error) {
-				return tfsdk.NewProtocol6Server(New()), nil
+				return providerserver.NewProtocol6WithError(New()())()
 			},
 		},
 		CheckDestroy: testAccCheckHAGroupDataSourceDestroy(rName),
@@ -31,6 +31,25 @@ func TestAccHAGroupDataSource_basic(t *testing.T) {
 	})
 }
 
+func TestHAGroupDataSource_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	groupId := m.addHAGroup("mockgroup")
+	m.addAccount("mockacc", groupId)
+	tf := newMockTerraform(t, m)
+
+	state := tf.mustReadDataSource("xsoar_ha_group", map[string]interface{}{"name": "mockgroup"})
+	if state["id"] != groupId {
+		t.Fatalf("expected HA group ID %s, got %v", groupId, state["id"])
+	}
+	if state["elastic_index_prefix"] != "mockgroup_" {
+		t.Fatalf("unexpected index prefix %v", state["elastic_index_prefix"])
+	}
+	if diffs := mockDiff("", []interface{}{m.account("mockacc")["id"]}, state["account_ids"]); len(diffs) > 0 {
+		t.Fatalf("unexpected account IDs: %s", diffs)
+	}
+}
+
 func testAccHAGroupDataSourcePreCheck(t *testing.T) {}
 
 func testAccCheckHAGroupDataSourceDestroy(r string) resource.TestCheckFunc {
@@ -0,0 +1,26 @@
+package xsoar
+
+import (
+	"testing"
+)
+
+func TestHAGroupsDataSource_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addHAGroup("prod-1")
+	fullGroupId := m.addHAGroup("prod-2")
+	m.addHAGroup("dev-1")
+	m.addAccount("mockacc", fullGroupId)
+	tf := newMockTerraform(t, m)
+
+	state := tf.mustReadDataSource("xsoar_ha_groups", map[string]interface{}{"name": "prod-*"})
+	if groups := mockSlice(state["groups"]); len(groups) != 2 {
+		t.Fatalf("expected 2 groups matching prod-*, got %v", state["groups"])
+	}
+
+	state = tf.mustReadDataSource("xsoar_ha_groups", map[string]interface{}{"name": "prod-*", "max_accounts": 0})
+	groups := mockSlice(state["groups"])
+	if len(groups) != 1 || mockObject(groups[0])["name"] != "prod-1" {
+		t.Fatalf("expected only the empty prod-1 group, got %v", state["groups"])
+	}
+}
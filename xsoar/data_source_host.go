@@ -5,7 +5,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"time"
 )
 
 type dataSourceHostType struct{}
@@ -47,6 +46,142 @@ func (r dataSourceHostType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Dia
 				Computed: false,
 				Optional: true,
 			},
+			"ssh_key_passphrase": {
+				Type:     types.StringType,
+				Computed: false,
+				Optional: true,
+			},
+			"ssh_certificate": {
+				Type:     types.StringType,
+				Computed: false,
+				Optional: true,
+			},
+			"ssh_agent": {
+				Type:     types.BoolType,
+				Computed: false,
+				Optional: true,
+			},
+			"ssh_agent_forwarding": {
+				Type:     types.BoolType,
+				Computed: false,
+				Optional: true,
+			},
+			"host_key": {
+				Type:     types.StringType,
+				Computed: false,
+				Optional: true,
+			},
+			"host_key_fingerprint": {
+				Type:     types.StringType,
+				Computed: false,
+				Optional: true,
+			},
+			"known_hosts_file": {
+				Type:     types.StringType,
+				Computed: false,
+				Optional: true,
+			},
+			"host_key_checking": {
+				Type:     types.StringType,
+				Computed: false,
+				Optional: true,
+			},
+			"observed_host_key": {
+				Type:     types.StringType,
+				Computed: true,
+			},
+			"nfs_mount": {
+				Type:     types.StringType,
+				Computed: false,
+				Optional: true,
+			},
+			"nfs_lock_ttl": {
+				Type:     types.StringType,
+				Computed: false,
+				Optional: true,
+			},
+			"installation_timeout": {
+				Type:     types.Int64Type,
+				Computed: false,
+				Optional: true,
+			},
+			"extra_flags": {
+				Type:     types.ListType{ElemType: types.StringType},
+				Computed: false,
+				Optional: true,
+			},
+			"elasticsearch_username": {
+				Type:     types.StringType,
+				Computed: false,
+				Optional: true,
+			},
+			"elasticsearch_password": {
+				Type:     types.StringType,
+				Computed: false,
+				Optional: true,
+			},
+			"temp_folder": {
+				Type:     types.StringType,
+				Computed: false,
+				Optional: true,
+			},
+			"installer_transfer": {
+				Type:     types.StringType,
+				Computed: false,
+				Optional: true,
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"bastion": {
+				NestingMode: tfsdk.BlockNestingModeList,
+				MaxItems:    1,
+				Attributes: map[string]tfsdk.Attribute{
+					"host": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"user": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"ssh_key": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"ssh_key_passphrase": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"ssh_certificate": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"ssh_agent": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"host_key": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"host_key_fingerprint": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"known_hosts_file": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"host_key_checking": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"observed_host_key": {
+						Type:     types.StringType,
+						Computed: true,
+					},
+				},
+			},
 		},
 	}, nil
 }
@@ -62,38 +197,29 @@ type dataSourceHost struct {
 }
 
 func (r dataSourceHost) Read(ctx context.Context, req tfsdk.ReadDataSourceRequest, resp *tfsdk.ReadDataSourceResponse) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
 	// Declare struct that this function will set to this data source's config
-	var config Host
+	var config HostDataSource
 	diags := req.Config.Get(ctx, &config)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	var host map[string]interface{}
-	var err error
-	c1 := make(chan map[string]interface{}, 1)
-	go func() {
-		for host == nil {
-			host, _, err = r.p.client.DefaultApi.GetHost(ctx, config.Name.Value).Execute()
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"Error listing HA groups",
-					"Could not list HA groups: "+err.Error(),
-				)
-				return
-			}
-			time.Sleep(time.Second)
-		}
-		c1 <- host
-	}()
-	select {
-	case _ = <-c1:
-		break
-	case <-time.After(300 * time.Second):
+	host, _, err := r.p.client.DefaultApi.GetHost(ctx, config.Name.Value).Execute()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting host",
+			"Could not get host: "+err.Error(),
+		)
+		return
+	}
+	if host == nil {
 		resp.Diagnostics.AddError(
 			"Error getting host",
-			"Could not get host before timeout",
+			"No host is named "+config.Name.Value+".",
 		)
 		return
 	}
@@ -112,8 +238,8 @@ func (r dataSourceHost) Read(ctx context.Context, req tfsdk.ReadDataSourceReques
 		return
 	}
 
-	var result Host
-	result = Host{
+	var result HostDataSource
+	result = HostDataSource{
 		Name: types.String{Value: hostName},
 		Id:   types.String{Value: hostId},
 	}
@@ -138,6 +264,24 @@ func (r dataSourceHost) Read(ctx context.Context, req tfsdk.ReadDataSourceReques
 	result.ServerUrl = config.ServerUrl
 	result.SSHUser = config.SSHUser
 	result.SSHKey = config.SSHKey
+	result.SSHKeyPassphrase = config.SSHKeyPassphrase
+	result.SSHCertificate = config.SSHCertificate
+	result.SSHAgent = config.SSHAgent
+	result.SSHAgentForwarding = config.SSHAgentForwarding
+	result.Bastion = config.Bastion
+	result.HostKey = config.HostKey
+	result.HostKeyFingerprint = config.HostKeyFingerprint
+	result.KnownHostsFile = config.KnownHostsFile
+	result.HostKeyChecking = config.HostKeyChecking
+	result.ObservedHostKey = types.String{Null: true}
+	result.NFSMount = config.NFSMount
+	result.NFSLockTTL = config.NFSLockTTL
+	result.InstallationTimeout = config.InstallationTimeout
+	result.ExtraFlags = config.ExtraFlags
+	result.InstallerTransfer = config.InstallerTransfer
+	result.ElasticsearchUsername = config.ElasticsearchUsername
+	result.ElasticsearchPassword = config.ElasticsearchPassword
+	result.TempFolder = config.TempFolder
 
 	// Set state
 	diags = resp.State.Set(ctx, result)
@@ -3,7 +3,7 @@ package xsoar
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -19,7 +19,7 @@ func TestAccHostDataSource_basic(t *testing.T) {
 		PreCheck: func() { testAccHostDataSourcePreCheck(t) },
 		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
 			"xsoar": func() (tfprotov6.ProviderServer, error) {
-				return tfsdk.NewProtocol6Server(New()), nil
+				return providerserver.NewProtocol6WithError(New()())()
 			},
 		},
 		CheckDestroy: testAccCheckHostDataSourceDestroy(rName),
@@ -32,6 +32,33 @@ func TestAccHostDataSource_basic(t *testing.T) {
 	})
 }
 
+func TestHostDataSource_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.installHost("mockhost", "http://elastic.xsoar.local:9200")
+	m.installerGroup = m.addHAGroup("mockgroup")
+	m.installHost("mockhahost", "")
+	tf := newMockTerraform(t, m)
+
+	state := tf.mustReadDataSource("xsoar_host", map[string]interface{}{"name": "mockhost"})
+	if state["id"] != m.host("mockhost")["id"] {
+		t.Fatalf("expected host ID %v, got %v", m.host("mockhost")["id"], state["id"])
+	}
+	if state["ha_group_name"] != nil || state["elasticsearch_url"] != "http://elastic.xsoar.local:9200" {
+		t.Fatalf("unexpected standalone host %v", state)
+	}
+
+	state = tf.mustReadDataSource("xsoar_host", map[string]interface{}{"name": "mockhahost"})
+	if state["ha_group_name"] != "mockgroup" {
+		t.Fatalf("expected HA group mockgroup, got %v", state["ha_group_name"])
+	}
+
+	_, err := tf.readDataSource("xsoar_host", map[string]interface{}{"name": "mockmissinghost"})
+	if err == nil || !strings.Contains(err.Error(), "No host is named mockmissinghost") {
+		t.Fatalf("expected a missing host error, got %v", err)
+	}
+}
+
 func testAccHostDataSourcePreCheck(t *testing.T) {}
 
 func testAccCheckHostDataSourceExists(r string) resource.TestCheckFunc {
@@ -87,6 +114,8 @@ resource "xsoar_host" "{name}" {
   server_url = "{host}:22"
   ssh_user   = "vagrant"
   ssh_key    = file("{keyfile}")
+
+  host_key_checking = "tofu"
 }
 
 data "xsoar_host" "{name}" {
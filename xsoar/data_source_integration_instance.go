@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"reflect"
+	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -42,26 +43,120 @@ func (r dataSourceIntegrationInstanceType) GetSchema(_ context.Context) (tfsdk.S
 				Type:     types.StringType,
 				Optional: true,
 			},
+			"enabled": {
+				Type:     types.BoolType,
+				Computed: true,
+				Optional: false,
+			},
+			"config_json": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"secret_config_json": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
 			"config": {
 				Type:     types.MapType{ElemType: types.StringType},
-				Optional: true,
 				Computed: true,
+				Optional: false,
+			},
+			"integration_log_level": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"is_long_running": {
+				Type:     types.BoolType,
+				Computed: true,
+				Optional: false,
+			},
+			"engine_group": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"reset_context": {
+				Type:     types.BoolType,
+				Computed: true,
+				Optional: false,
+			},
+			"password_protected": {
+				Type:     types.BoolType,
+				Computed: true,
+				Optional: false,
+			},
+			"byoi": {
+				Type:     types.BoolType,
+				Computed: true,
+				Optional: false,
+			},
+			"default_ignore": {
+				Type:     types.BoolType,
+				Computed: true,
+				Optional: false,
+			},
+			"is_fetch": {
+				Type:     types.BoolType,
+				Computed: true,
+				Optional: false,
+			},
+			"incident_type": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"fetch_interval": {
+				Type:     types.Int64Type,
+				Computed: true,
+				Optional: false,
+			},
+			"mirroring_direction": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"test_on_apply": {
+				Type:     types.BoolType,
+				Computed: true,
+				Optional: false,
+			},
+			"test_mode": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"last_test_result": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
 			},
 			"incoming_mapper_id": {
 				Type:     types.StringType,
-				Required: true,
+				Computed: true,
+				Optional: false,
 			},
 			"mapping_id": {
 				Type:     types.StringType,
-				Required: true,
+				Computed: true,
+				Optional: false,
 			},
 			"outgoing_mapper_id": {
 				Type:     types.StringType,
-				Required: true,
+				Computed: true,
+				Optional: false,
 			},
 			"engine_id": {
 				Type:     types.StringType,
-				Required: true,
+				Computed: true,
+				Optional: false,
+			},
+			"credentials": {
+				Type:     types.MapType{ElemType: types.StringType},
+				Computed: true,
+				Optional: false,
 			},
 		},
 	}, nil
@@ -78,8 +173,11 @@ type dataSourceIntegrationInstance struct {
 }
 
 func (r dataSourceIntegrationInstance) Read(ctx context.Context, req tfsdk.ReadDataSourceRequest, resp *tfsdk.ReadDataSourceResponse) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
 	// Get current config
-	var config IntegrationInstance
+	var config IntegrationInstanceDataSource
 	diags := req.Config.Get(ctx, &config)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -136,7 +234,10 @@ func (r dataSourceIntegrationInstance) Read(ctx context.Context, req tfsdk.ReadD
 				log.Println(integrationConfig)
 				nameconf, ok := integrationConfig["name"].(string)
 				if ok {
-					integrationConfigs[nameconf] = integrationConfig["value"]
+					// parameters referencing a stored credential are held in credentials
+					if parameterCredential(integrationConfig["value"]) == "" {
+						integrationConfigs[nameconf] = integrationConfig["value"]
+					}
 				} else {
 					break
 				}
@@ -160,6 +261,21 @@ func (r dataSourceIntegrationInstance) Read(ctx context.Context, req tfsdk.ReadD
 		Account:           config.Account,
 		PropagationLabels: types.Set{Elems: propagationLabels, ElemType: types.StringType},
 		ConfigJson:        types.String{Value: string(integrationConfigsJson)},
+		// secret values are never returned by the API
+		SecretConfigJson: types.String{Null: true},
+		Config:           getAllConfigFromAPIResponse(integration),
+		// instance tests are only run by the resource
+		TestOnApply:    types.Bool{Null: true},
+		TestMode:       types.String{Null: true},
+		LastTestResult: types.String{Null: true},
+		Credentials:    getCredentialsFromAPIResponse(integration, types.Map{ElemType: types.StringType}),
+	}
+
+	Enabled, err := strconv.ParseBool(integration["enabled"].(string))
+	if err == nil {
+		result.Enabled = types.Bool{Value: Enabled}
+	} else {
+		result.Enabled = types.Bool{Null: true}
 	}
 
 	IncomingMapperId, ok := integration["incomingMapperId"].(string)
@@ -189,10 +305,47 @@ func (r dataSourceIntegrationInstance) Read(ctx context.Context, req tfsdk.ReadD
 		result.EngineId = types.String{Null: true}
 	}
 
+	// an empty prior reads every common parameter the instance has
+	getInstanceSettings(integration, IntegrationInstance{}, &result)
+
 	// Generate resource state struct
-	diags = resp.State.Set(ctx, result)
+	diags = resp.State.Set(ctx, integrationInstanceDataSource(result))
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 }
+
+// integrationInstanceDataSource returns the attributes of an integration instance the data source has
+func integrationInstanceDataSource(instance IntegrationInstance) IntegrationInstanceDataSource {
+	return IntegrationInstanceDataSource{
+		Name:                instance.Name,
+		Id:                  instance.Id,
+		IntegrationName:     instance.IntegrationName,
+		Account:             instance.Account,
+		Enabled:             instance.Enabled,
+		PropagationLabels:   instance.PropagationLabels,
+		ConfigJson:          instance.ConfigJson,
+		SecretConfigJson:    instance.SecretConfigJson,
+		Config:              instance.Config,
+		IncomingMapperId:    instance.IncomingMapperId,
+		OutgoingMapperId:    instance.OutgoingMapperId,
+		MappingId:           instance.MappingId,
+		EngineId:            instance.EngineId,
+		Credentials:         instance.Credentials,
+		TestOnApply:         instance.TestOnApply,
+		TestMode:            instance.TestMode,
+		LastTestResult:      instance.LastTestResult,
+		IntegrationLogLevel: instance.IntegrationLogLevel,
+		IsLongRunning:       instance.IsLongRunning,
+		EngineGroup:         instance.EngineGroup,
+		ResetContext:        instance.ResetContext,
+		PasswordProtected:   instance.PasswordProtected,
+		Byoi:                instance.Byoi,
+		DefaultIgnore:       instance.DefaultIgnore,
+		IsFetch:             instance.IsFetch,
+		IncidentType:        instance.IncidentType,
+		FetchInterval:       instance.FetchInterval,
+		MirroringDirection:  instance.MirroringDirection,
+	}
+}
@@ -3,7 +3,7 @@ package xsoar
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -18,7 +18,7 @@ func TestAccIntegrationInstanceDataSource_basic(t *testing.T) {
 		PreCheck: func() { testAccIntegrationInstanceDataSourcePreCheck(t) },
 		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
 			"xsoar": func() (tfprotov6.ProviderServer, error) {
-				return tfsdk.NewProtocol6Server(New()), nil
+				return providerserver.NewProtocol6WithError(New()())()
 			},
 		},
 		CheckDestroy: testAccCheckIntegrationInstanceDataSourceDestroy(rName),
@@ -31,6 +31,36 @@ func TestAccIntegrationInstanceDataSource_basic(t *testing.T) {
 	})
 }
 
+func TestIntegrationInstanceDataSource_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		id := m.putObject(acc, "instances", map[string]interface{}{
+			"name":              "mockinstance",
+			"brand":             "MockIntegration",
+			"enabled":           "true",
+			"propagationLabels": []interface{}{"all"},
+			"data": []interface{}{
+				map[string]interface{}{"name": "url", "value": "https://mock.local/api"},
+			},
+		})
+		config := map[string]interface{}{"name": "mockinstance"}
+		if acc != "" {
+			config["account"] = acc
+		}
+		state := tf.mustReadDataSource("xsoar_integration_instance", config)
+		if state["id"] != id {
+			t.Fatalf("expected integration instance ID %s in account %q, got %v", id, acc, state["id"])
+		}
+		if state["integration_name"] != "MockIntegration" || state["config_json"] != `{"url":"https://mock.local/api"}` {
+			t.Fatalf("unexpected integration instance %v", state)
+		}
+	}
+}
+
 func testAccIntegrationInstanceDataSourcePreCheck(t *testing.T) {}
 
 func testAccCheckIntegrationInstanceDataSourceDestroy(r string) resource.TestCheckFunc {
@@ -0,0 +1,106 @@
+package xsoar
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type dataSourceListType struct{}
+
+func (r dataSourceListType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"id": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"type": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"data": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"tags": {
+				Type:     types.SetType{ElemType: types.StringType},
+				Computed: true,
+				Optional: false,
+			},
+			"account": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+		},
+	}, nil
+}
+
+func (r dataSourceListType) NewDataSource(_ context.Context, p tfsdk.Provider) (tfsdk.DataSource, diag.Diagnostics) {
+	return dataSourceList{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type dataSourceList struct {
+	p provider
+}
+
+func (r dataSourceList) Read(ctx context.Context, req tfsdk.ReadDataSourceRequest, resp *tfsdk.ReadDataSourceResponse) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
+	// Declare struct that this function will set to this data source's config
+	var config ListDataSource
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	list, err := getList(ctx, r.p, config.Account, config.Name.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting list",
+			"Could not get list: "+err.Error(),
+		)
+		return
+	}
+	if list == nil {
+		resp.Diagnostics.AddError(
+			"Error getting list",
+			"Could not find list "+config.Name.Value,
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	read := listFromAPI(list, List{Data: types.String{Null: true}, Account: config.Account})
+	result := ListDataSource{
+		Name:    read.Name,
+		Id:      read.Id,
+		Type:    read.Type,
+		Data:    read.Data,
+		Tags:    read.Tags,
+		Account: read.Account,
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
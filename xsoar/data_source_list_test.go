@@ -0,0 +1,28 @@
+package xsoar
+
+import (
+	"testing"
+)
+
+func TestListDataSource_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	m.putObject("mockacc", "lists", map[string]interface{}{
+		"id":   "MockRouting",
+		"name": "MockRouting",
+		"type": "json",
+		"data": `{"phishing":"soc-team"}`,
+		"tags": []interface{}{"routing"},
+	})
+	tf := newMockTerraform(t, m)
+
+	state := tf.mustReadDataSource("xsoar_list", map[string]interface{}{"name": "MockRouting", "account": "mockacc"})
+	if state["id"] != "MockRouting" || state["type"] != "json" || state["data"] != `{"phishing":"soc-team"}` {
+		t.Fatalf("unexpected list %v", state)
+	}
+
+	if _, err := tf.readDataSource("xsoar_list", map[string]interface{}{"name": "MockRouting"}); err == nil {
+		t.Fatal("expected an error for a list in another account")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"strings"
 )
 
 type dataSourceMapperType struct{}
@@ -62,8 +63,11 @@ type dataSourceMapper struct {
 }
 
 func (r dataSourceMapper) Read(ctx context.Context, req tfsdk.ReadDataSourceRequest, resp *tfsdk.ReadDataSourceResponse) {
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+
 	// Declare struct that this function will set to this data source's config
-	var config Mapper
+	var config MapperDataSource
 	diags := req.Config.Get(ctx, &config)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -106,12 +110,15 @@ func (r dataSourceMapper) Read(ctx context.Context, req tfsdk.ReadDataSourceRequ
 		)
 		return
 	}
-	result := Mapper{
+	classificationType := mapper.GetType()
+	splitClassification := strings.Split(classificationType, "-")
+	direction := splitClassification[len(splitClassification)-1]
+	result := MapperDataSource{
 		Name:              types.String{Value: mapper.GetName()},
 		Id:                types.String{Value: mapper.GetId()},
 		PropagationLabels: types.Set{Elems: propLabels, ElemType: types.StringType},
 		Account:           config.Account,
-		Direction:         config.Direction,
+		Direction:         types.String{Value: direction},
 	}
 	if m := string(mapping); m == "null" {
 		result.Mapping = types.String{Null: true}
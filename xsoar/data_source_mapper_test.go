@@ -3,7 +3,7 @@ package xsoar
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -18,7 +18,7 @@ func TestAccMapperDataSource_basic(t *testing.T) {
 		PreCheck: func() { testAccMapperDataSourcePreCheck(t) },
 		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
 			"xsoar": func() (tfprotov6.ProviderServer, error) {
-				return tfsdk.NewProtocol6Server(New()), nil
+				return providerserver.NewProtocol6WithError(New()())()
 			},
 		},
 		CheckDestroy: testAccCheckMapperDataSourceDestroy(rName),
@@ -31,6 +31,25 @@ func TestAccMapperDataSource_basic(t *testing.T) {
 	})
 }
 
+func TestMapperDataSource_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	id := m.putObject("", "classifiers", map[string]interface{}{
+		"name":    "mockmapper",
+		"type":    "mapping-outgoing",
+		"mapping": map[string]interface{}{"Phishing": map[string]interface{}{"dontMapEventToLabels": true}},
+	})
+	tf := newMockTerraform(t, m)
+
+	state := tf.mustReadDataSource("xsoar_mapper", map[string]interface{}{"name": "mockmapper"})
+	if state["id"] != id {
+		t.Fatalf("expected mapper ID %s, got %v", id, state["id"])
+	}
+	if state["direction"] != "outgoing" || state["mapping"] != `{"Phishing":{"dontMapEventToLabels":true}}` {
+		t.Fatalf("unexpected mapper %v", state)
+	}
+}
+
 func testAccMapperDataSourcePreCheck(t *testing.T) {}
 
 func testAccCheckMapperDataSourceDestroy(r string) resource.TestCheckFunc {
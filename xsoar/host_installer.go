@@ -0,0 +1,200 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	tfpath "github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The installer flags the provider passes are those of the Cortex XSOAR installation guide: -y, -external-address,
+// -temp-folder and -ha, and -elasticsearch-url, -elasticsearch-username and -elasticsearch-password to use an
+// external Elasticsearch cluster. Any other flag is passed through extra_flags.
+
+// reservedInstallerFlags are the flags the provider passes itself, mapped to the attribute setting them
+var reservedInstallerFlags = map[string]string{
+	"-y":                      "",
+	"-purge":                  "",
+	"-ha":                     "ha_group_name",
+	"-external-address":       "name",
+	"-elasticsearch-url":      "elasticsearch_url",
+	"-elasticsearch-username": "elasticsearch_username",
+	"-elasticsearch-password": "elasticsearch_password",
+	"-temp-folder":            "temp_folder",
+}
+
+var (
+	// extraFlagRegexp matches an extra flag of the installer, with one or two dashes, and its value if it takes one
+	extraFlagRegexp = regexp.MustCompile(`^--?([a-zA-Z][a-zA-Z0-9_-]*)(=(.*))?$`)
+)
+
+// isAbsolutePath validates that a string attribute is an absolute path on the machine of a host
+type isAbsolutePath struct{}
+
+func (v isAbsolutePath) Description(_ context.Context) string {
+	return "value must be an absolute path"
+}
+
+func (v isAbsolutePath) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v isAbsolutePath) Validate(ctx context.Context, request tfsdk.ValidateAttributeRequest, response *tfsdk.ValidateAttributeResponse) {
+	var str types.String
+	diags := tfsdk.ValueAs(ctx, request.AttributeConfig, &str)
+	response.Diagnostics.Append(diags...)
+	if diags.HasError() || str.Null || str.Unknown {
+		return
+	}
+	if !path.IsAbs(str.Value) || strings.ContainsAny(str.Value, "\x00\n") {
+		response.Diagnostics.AddAttributeError(
+			request.AttributePath,
+			"Invalid Path",
+			fmt.Sprintf("Value must be an absolute path on the host server, got: %q.", str.Value),
+		)
+	}
+}
+
+// validateInstallerOptions validates the installer options of a host config
+func validateInstallerOptions(config Host, diags *diag.Diagnostics) {
+	isHA := !config.HAGroupName.Null && (config.HAGroupName.Unknown || len(config.HAGroupName.Value) > 0)
+	elasticsearchSettings := map[string]types.String{
+		"elasticsearch_username": config.ElasticsearchUsername,
+		"elasticsearch_password": config.ElasticsearchPassword,
+	}
+	for attribute, value := range elasticsearchSettings {
+		if isHA && !value.Null {
+			diags.AddAttributeError(
+				tfpath.Root(attribute),
+				"Invalid Elasticsearch setting",
+				"The Elasticsearch settings of hosts in an HA group are set on the xsoar_ha_group.",
+			)
+		}
+	}
+	if config.ElasticsearchUsername.Null != config.ElasticsearchPassword.Null {
+		diags.AddAttributeError(
+			tfpath.Root("elasticsearch_password"),
+			"Invalid Elasticsearch credentials",
+			"elasticsearch_username and elasticsearch_password must be set together.",
+		)
+	}
+	if password := config.ElasticsearchPassword; !password.Null && !password.Unknown && strings.ContainsAny(password.Value, "\r\n") {
+		diags.AddAttributeError(
+			tfpath.Root("elasticsearch_password"),
+			"Invalid Elasticsearch credentials",
+			"elasticsearch_password must be a single line.",
+		)
+	}
+
+	if config.ExtraFlags.Null || config.ExtraFlags.Unknown {
+		return
+	}
+	for i, elem := range config.ExtraFlags.Elems {
+		flag, ok := elem.(types.String)
+		if !ok || flag.Null || flag.Unknown {
+			continue
+		}
+		at := tfpath.Root("extra_flags").AtListIndex(i)
+		name, _, ok := parseExtraFlag(flag.Value)
+		if !ok {
+			diags.AddAttributeError(
+				at,
+				"Invalid extra flag",
+				fmt.Sprintf("Extra flags must be a single installer flag such as -multi-tenant or -flag=value, got: %q.", flag.Value),
+			)
+			continue
+		}
+		if attribute, reserved := reservedInstallerFlags[name]; reserved {
+			detail := fmt.Sprintf("The provider passes %s to the installer itself.", name)
+			if len(attribute) > 0 {
+				detail = fmt.Sprintf("%s is set by the %s attribute.", name, attribute)
+			}
+			diags.AddAttributeError(at, "Invalid extra flag", detail)
+		}
+	}
+}
+
+// parseExtraFlag returns the name of an extra flag with a single dash, and its value if it has one. Extra flags used
+// to be passed to the shell as they were written, so a value quoted as a whole is unquoted.
+func parseExtraFlag(flag string) (string, *string, bool) {
+	match := extraFlagRegexp.FindStringSubmatch(flag)
+	if match == nil {
+		return "", nil, false
+	}
+	name := "-" + match[1]
+	if len(match[2]) == 0 {
+		return name, nil, true
+	}
+	value := match[3]
+	for _, quote := range []string{"'", `"`} {
+		if len(value) >= 2 && strings.HasPrefix(value, quote) && strings.HasSuffix(value, quote) && !strings.Contains(value[1:len(value)-1], quote) {
+			value = value[1 : len(value)-1]
+			break
+		}
+	}
+	return name, &value, true
+}
+
+// installerArgs returns the arguments the installer is run with to install a host, each quoted for the shell
+func installerArgs(ctx context.Context, plan Host, isHA bool) ([]string, error) {
+	args := []string{
+		"-y",
+		"-external-address=" + shellQuote(plan.Name.Value),
+	}
+	if !isHA && len(plan.ElasticsearchUrl.Value) > 0 {
+		args = append(args, "-elasticsearch-url="+shellQuote(plan.ElasticsearchUrl.Value))
+	}
+	tempFolder := plan.TempFolder.Value
+	if isHA && len(tempFolder) == 0 {
+		tempFolder = "/tmp/demisto"
+	}
+	if len(tempFolder) > 0 {
+		args = append(args, "-temp-folder="+shellQuote(tempFolder))
+	}
+	if !isHA && len(plan.ElasticsearchUsername.Value) > 0 {
+		args = append(args, "-elasticsearch-username="+shellQuote(plan.ElasticsearchUsername.Value))
+	}
+	if isHA {
+		args = append(args, "-ha")
+	}
+	if !plan.ExtraFlags.Null {
+		var extraFlags []string
+		diags := plan.ExtraFlags.ElementsAs(ctx, &extraFlags, false)
+		if diags.HasError() {
+			return nil, fmt.Errorf("could not read extra_flags: %v", diags)
+		}
+		for _, flag := range extraFlags {
+			name, value, ok := parseExtraFlag(flag)
+			if !ok {
+				return nil, fmt.Errorf("invalid extra flag %q", flag)
+			}
+			if value != nil {
+				name += "=" + shellQuote(*value)
+			}
+			args = append(args, name)
+		}
+	}
+	return args, nil
+}
+
+// installerPasswordVar is the shell variable the command running the installer reads the Elasticsearch password into
+const installerPasswordVar = "elasticsearch_password"
+
+// installerCommand returns the command running the installer at the path with the arguments, and what the command reads from
+// stdin. The Elasticsearch password is read from stdin into a shell variable and passed as -elasticsearch-password,
+// so that it is not part of the command sent over SSH.
+func installerCommand(installer string, args []string, password types.String) (string, []byte) {
+	if password.Null || len(password.Value) == 0 {
+		return "sudo " + shellQuote(installer) + " -- " + strings.Join(args, " "), nil
+	}
+	cmd := fmt.Sprintf(
+		`IFS= read -r %[1]s && sudo %[2]s -- %[3]s -elasticsearch-password="$%[1]s"`,
+		installerPasswordVar, shellQuote(installer), strings.Join(args, " "))
+	return cmd, []byte(password.Value + "\n")
+}
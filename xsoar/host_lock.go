@@ -0,0 +1,223 @@
+package xsoar
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	mathrand "math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// installLockName is the lock on the NFS volume of an HA group that the installs of its hosts take turns holding
+const installLockName = "xsoar_host_install.lock"
+
+// defaultInstallLockTTL is how old a lock may get before it is taken to be left behind by an install that crashed,
+// when nfs_lock_ttl is not set
+const defaultInstallLockTTL = time.Hour
+
+// installLockPoll is how often a lock held by another install is checked
+var installLockPoll = 10 * time.Second
+
+// installLock serializes the installs of the hosts of an HA group, which share the NFS volume mounted at nfs_mount.
+// The lock is a directory, as mkdir is atomic on NFS, holding an owner file that records which install holds it,
+// the token of that install and when it was acquired.
+type installLock struct {
+	conn  *ssh.Client
+	mount string
+	dir   string
+	owner string
+	// token tells the installs of the same host apart
+	token string
+	ttl   time.Duration
+}
+
+// lockHolder is the install holding a lock, as its owner file records it
+type lockHolder struct {
+	owner string
+	token string
+	// age is how long ago the lock was acquired, by the clock of the host server
+	age time.Duration
+}
+
+func (h lockHolder) String() string {
+	if len(h.owner) == 0 {
+		return "an unknown install"
+	}
+	return h.owner
+}
+
+// newInstallLock returns the lock of the NFS volume mounted at mount for the install of the host owner
+func newInstallLock(conn *ssh.Client, mount string, owner string, ttl time.Duration) (*installLock, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, fmt.Errorf("could not generate lock token: %w", err)
+	}
+	return &installLock{
+		conn:  conn,
+		mount: mount,
+		dir:   strings.TrimSuffix(mount, "/") + "/" + installLockName,
+		owner: owner,
+		token: hex.EncodeToString(token),
+		ttl:   ttl,
+	}, nil
+}
+
+func (l *installLock) ownerFile() string {
+	return l.dir + "/owner"
+}
+
+// acquire waits until the lock is free and takes it, taking over a lock older than the ttl
+func (l *installLock) acquire(ctx context.Context) error {
+	if err := runSSHCommand(ctx, l.conn, "sudo test -d "+shellQuote(l.mount)); err != nil {
+		return fmt.Errorf("nfs_mount %s is not a directory on the host server: %w", l.mount, err)
+	}
+	var waitingSince time.Time
+	for {
+		err := runSSHCommand(ctx, l.conn, fmt.Sprintf(
+			`sudo mkdir %s && printf 'owner=%%s\ntoken=%%s\nacquired=%%s\n' %s %s "$(date +%%s)" | sudo tee %s > /dev/null`,
+			shellQuote(l.dir), shellQuote(l.owner), shellQuote(l.token), shellQuote(l.ownerFile()),
+		))
+		if err == nil {
+			if !waitingSince.IsZero() {
+				log.Printf("acquired install lock %s after waiting %s", l.dir, time.Since(waitingSince).Round(time.Second))
+			} else {
+				log.Printf("acquired install lock %s", l.dir)
+			}
+			return nil
+		}
+		var exitErr *ssh.ExitError
+		if !errors.As(err, &exitErr) {
+			return fmt.Errorf("could not create lock %s: %w", l.dir, err)
+		}
+		if waitingSince.IsZero() {
+			waitingSince = time.Now()
+		}
+
+		holder, held, err := l.inspect(ctx)
+		if err != nil {
+			return err
+		}
+		wait := time.Second
+		switch {
+		case !held:
+			// released since the attempt to take it
+		case holder.age >= l.ttl:
+			log.Printf("install lock %s held by %s for %s is older than nfs_lock_ttl %s, taking it over", l.dir, holder, holder.age, l.ttl)
+			taken, err := l.takeOver(ctx, holder)
+			if err != nil {
+				return err
+			}
+			if taken {
+				continue
+			}
+		default:
+			if stale := l.ttl - holder.age; stale < installLockPoll {
+				wait = stale
+			} else {
+				wait = installLockPoll
+			}
+			// installs waiting on the same lock check it at different times
+			wait += time.Duration(mathrand.Int63n(int64(time.Second)))
+			log.Printf("waiting for install lock %s held by %s for %s, waited %s so far", l.dir, holder, holder.age.Round(time.Second), time.Since(waitingSince).Round(time.Second))
+		}
+		if err = sleepContext(ctx, wait); err != nil {
+			return fmt.Errorf("gave up waiting for install lock %s held by %s: %w", l.dir, holder, err)
+		}
+	}
+}
+
+// inspect returns the install holding the lock, and whether the lock is held at all
+func (l *installLock) inspect(ctx context.Context) (lockHolder, bool, error) {
+	// a lock without an owner file, as an install that crashed right after creating it or a previous version of
+	// the provider leaves, is as old as the lock itself
+	output, err := outputSSHCommand(ctx, l.conn, fmt.Sprintf(
+		`date +%%s; if sudo test -e %s; then sudo cat %s 2>/dev/null || sudo stat -c acquired=%%Y %s; fi`,
+		shellQuote(l.dir), shellQuote(l.ownerFile()), shellQuote(l.dir),
+	))
+	var exitErr *ssh.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return lockHolder{}, false, fmt.Errorf("could not read lock %s: %w", l.dir, err)
+	}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	if !scanner.Scan() {
+		return lockHolder{}, false, fmt.Errorf("could not read lock %s: no output", l.dir)
+	}
+	now, err := strconv.ParseInt(strings.TrimSpace(scanner.Text()), 10, 64)
+	if err != nil {
+		return lockHolder{}, false, fmt.Errorf("could not read the time of the host server: %w", err)
+	}
+	var holder lockHolder
+	held := false
+	var acquired int64
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !found {
+			continue
+		}
+		held = true
+		switch key {
+		case "owner":
+			holder.owner = value
+		case "token":
+			holder.token = value
+		case "acquired":
+			// a lock whose acquisition time can't be read is taken to be stale
+			acquired, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	holder.age = time.Duration(now-acquired) * time.Second
+	return holder, held, nil
+}
+
+// installLockRestoreFailed is the exit status of a take over that moved aside a lock it should not have taken and
+// could not move it back
+const installLockRestoreFailed = 2
+
+// takeOver removes a stale lock, when it is still held by holder, returning whether it did. The lock is first renamed
+// to a name of this install, which only one of the installs taking it over at the same time manages, and the owner is
+// checked on what was moved. A lock that changed hands since it was inspected is moved back.
+func (l *installLock) takeOver(ctx context.Context, holder lockHolder) (bool, error) {
+	stale := l.dir + ".stale." + l.token
+	staleOwner := stale + "/owner"
+	guard := "! sudo grep -qs '^token=' " + shellQuote(staleOwner)
+	if len(holder.token) > 0 {
+		guard = "sudo grep -qxF " + shellQuote("token="+holder.token) + " " + shellQuote(staleOwner)
+	}
+	err := runSSHCommand(ctx, l.conn, fmt.Sprintf(
+		"sudo mv -T %s %s || exit 1; if %s; then sudo rm -rf %s; else sudo mv -T %s %s || exit %d; exit 1; fi",
+		shellQuote(l.dir), shellQuote(stale), guard, shellQuote(stale), shellQuote(stale), shellQuote(l.dir), installLockRestoreFailed,
+	))
+	var exitErr *ssh.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return false, fmt.Errorf("could not take over lock %s: %w", l.dir, err)
+	}
+	if exitErr != nil && exitErr.ExitStatus() == installLockRestoreFailed {
+		return false, fmt.Errorf("lock %s changed hands while it was taken over and could not be moved back from %s, check which install holds it", l.dir, stale)
+	}
+	// a failed rename or a different owner means another install took the lock over first
+	return err == nil, nil
+}
+
+// release removes the lock if this install still holds it. It runs even when the install was interrupted, so it
+// does not take the context of the install.
+func (l *installLock) release() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	err := runSSHCommand(ctx, l.conn, fmt.Sprintf(
+		"if sudo grep -qxF %s %s; then sudo rm -rf %s; fi",
+		shellQuote("token="+l.token), shellQuote(l.ownerFile()), shellQuote(l.dir),
+	))
+	if err != nil {
+		return fmt.Errorf("could not remove lock %s: %w", l.dir, err)
+	}
+	log.Printf("released install lock %s", l.dir)
+	return nil
+}
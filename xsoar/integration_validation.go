@@ -0,0 +1,344 @@
+package xsoar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The parameter types of the integration catalog that configured values are checked against, other types accept
+// any value
+const (
+	parameterTypeShortText         = 0
+	parameterTypeNumber            = 1
+	parameterTypeEncrypted         = 4
+	parameterTypeBoolean           = 8
+	parameterTypeTextArea          = 12
+	parameterTypeIncidentType      = 13
+	parameterTypeTextAreaEncrypted = 14
+	parameterTypeSingleSelect      = 15
+	parameterTypeMultiSelect       = 16
+)
+
+// integrationParameterProblem is a configured parameter that does not match the integration catalog
+type integrationParameterProblem struct {
+	// attribute holds the parameter, or is empty when the parameter is missing
+	attribute string
+	summary   string
+	detail    string
+}
+
+// integrationConfigurations returns the integrations of a ListIntegrations response
+func integrationConfigurations(integrations map[string]interface{}) []map[string]interface{} {
+	var configurations []map[string]interface{}
+	for _, item := range interfaceSlice(integrations["configurations"]) {
+		if configuration, ok := item.(map[string]interface{}); ok {
+			configurations = append(configurations, configuration)
+		}
+	}
+	return configurations
+}
+
+// findIntegration returns the integration with the given brand from a ListIntegrations response, or nil if it is
+// not installed
+func findIntegration(integrations map[string]interface{}, brand string) map[string]interface{} {
+	for _, configuration := range integrationConfigurations(integrations) {
+		if configuration["name"] == brand {
+			return configuration
+		}
+	}
+	return nil
+}
+
+// unknownIntegrationDetail describes a brand that is not installed, suggesting the closest installed brands
+func unknownIntegrationDetail(integrations map[string]interface{}, brand string) string {
+	var names []string
+	for _, configuration := range integrationConfigurations(integrations) {
+		if name, ok := configuration["name"].(string); ok {
+			names = append(names, name)
+		}
+	}
+	detail := fmt.Sprintf("No installed integration is named %q.", brand)
+	if matches := closestMatches(brand, names, 3); len(matches) > 0 {
+		detail += fmt.Sprintf(" Did you mean %s?", quoteJoin(matches, " or "))
+	} else {
+		detail += " Install the content pack providing it, e.g. with xsoar_content_pack."
+	}
+	return detail
+}
+
+// closestMatches returns up to max candidates that look like name, closest first
+func closestMatches(name string, candidates []string, max int) []string {
+	type match struct {
+		candidate string
+		distance  int
+	}
+	lower := strings.ToLower(name)
+	var matches []match
+	for _, candidate := range candidates {
+		c := strings.ToLower(candidate)
+		distance := levenshtein(lower, c)
+		limit := len(lower) / 3
+		if limit < 3 {
+			limit = 3
+		}
+		if distance <= limit || (len(lower) > 2 && (strings.Contains(c, lower) || strings.Contains(lower, c))) {
+			matches = append(matches, match{candidate, distance})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].candidate < matches[j].candidate
+	})
+	var result []string
+	for i := 0; i < len(matches) && i < max; i++ {
+		result = append(result, matches[i].candidate)
+	}
+	return result
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, minInt(current[j-1]+1, previous[j-1]+cost))
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// quoteJoin quotes every value and joins them with sep
+func quoteJoin(values []string, sep string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return strings.Join(quoted, sep)
+}
+
+// integrationParameter returns the parameter of an integration configured by key, which is either the name or the
+// display name of the parameter
+func integrationParameter(configuration []interface{}, key string) map[string]interface{} {
+	for _, item := range configuration {
+		param, ok := item.(map[string]interface{})
+		if ok && (param["name"] == key || param["display"] == key) {
+			return param
+		}
+	}
+	return nil
+}
+
+// parameterOptions returns the values a select parameter accepts
+func parameterOptions(param map[string]interface{}) []string {
+	var options []string
+	for _, option := range interfaceSlice(param["options"]) {
+		if o, ok := option.(string); ok {
+			options = append(options, o)
+		}
+	}
+	return options
+}
+
+// checkParameterValue returns an error when the value cannot be given to a parameter of its type. A nil value is
+// unknown until apply, as a value taken from another resource is, and is not checked.
+func checkParameterValue(param map[string]interface{}, value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	paramType, _ := param["type"].(float64)
+	switch int(paramType) {
+	case parameterTypeShortText, parameterTypeEncrypted, parameterTypeTextArea, parameterTypeIncidentType, parameterTypeTextAreaEncrypted:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("expected a string, got %s", jsonKind(value))
+		}
+	case parameterTypeNumber:
+		switch v := value.(type) {
+		case float64:
+		case string:
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return fmt.Errorf("expected a number, got %q", v)
+			}
+		default:
+			return fmt.Errorf("expected a number, got %s", jsonKind(value))
+		}
+	case parameterTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+		case string:
+			if _, err := strconv.ParseBool(v); err != nil {
+				return fmt.Errorf("expected a boolean, got %q", v)
+			}
+		default:
+			return fmt.Errorf("expected a boolean, got %s", jsonKind(value))
+		}
+	case credentialsParameterType:
+		switch value.(type) {
+		case string, map[string]interface{}:
+		default:
+			return fmt.Errorf("expected an object with identifier and password, got %s", jsonKind(value))
+		}
+	case parameterTypeSingleSelect:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %s", jsonKind(value))
+		}
+		if options := parameterOptions(param); len(options) > 0 && len(v) > 0 && !isOption(options, v) {
+			return fmt.Errorf("expected one of %s, got %q", quoteJoin(options, ", "), v)
+		}
+	case parameterTypeMultiSelect:
+		var values []string
+		switch v := value.(type) {
+		case string:
+			if len(v) > 0 {
+				values = strings.Split(v, ",")
+			}
+		case []interface{}:
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return fmt.Errorf("expected a list of strings, got a list holding %s", jsonKind(item))
+				}
+				values = append(values, s)
+			}
+		default:
+			return fmt.Errorf("expected a list of strings, got %s", jsonKind(value))
+		}
+		options := parameterOptions(param)
+		for _, v := range values {
+			if len(options) > 0 && !isOption(options, v) {
+				return fmt.Errorf("expected values among %s, got %q", quoteJoin(options, ", "), v)
+			}
+		}
+	}
+	return nil
+}
+
+func isOption(options []string, value string) bool {
+	for _, o := range options {
+		if o == value {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonKind names the JSON type of a decoded value
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// validateIntegrationParameters checks configured parameters against the configuration of the integration. Every
+// map holds the parameters of the attribute it is keyed by, and missing required parameters are only reported
+// when complete is set, that is when every attribute holding parameters is known.
+func validateIntegrationParameters(configuration []interface{}, attributes map[string]map[string]interface{}, complete bool) []integrationParameterProblem {
+	var problems []integrationParameterProblem
+	var names []string
+	for _, item := range configuration {
+		if param, ok := item.(map[string]interface{}); ok {
+			if name, ok := param["name"].(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	configured := map[string]bool{}
+	var attributeNames []string
+	for attribute := range attributes {
+		attributeNames = append(attributeNames, attribute)
+	}
+	sort.Strings(attributeNames)
+	for _, attribute := range attributeNames {
+		params := attributes[attribute]
+		var keys []string
+		for key := range params {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			param := integrationParameter(configuration, key)
+			if param == nil {
+				detail := fmt.Sprintf("The integration has no parameter %q.", key)
+				if matches := closestMatches(key, names, 3); len(matches) > 0 {
+					detail += fmt.Sprintf(" Did you mean %s?", quoteJoin(matches, " or "))
+				}
+				problems = append(problems, integrationParameterProblem{attribute, "Unknown integration parameter", detail})
+				continue
+			}
+			name, _ := param["name"].(string)
+			configured[name] = true
+			if attribute == "credentials" {
+				if paramType, _ := param["type"].(float64); paramType != credentialsParameterType {
+					problems = append(problems, integrationParameterProblem{
+						attribute,
+						"Invalid integration parameter",
+						fmt.Sprintf("Parameter %q does not accept credentials.", key),
+					})
+				}
+				continue
+			}
+			if err := checkParameterValue(param, params[key]); err != nil {
+				problems = append(problems, integrationParameterProblem{
+					attribute,
+					"Invalid integration parameter",
+					fmt.Sprintf("Parameter %q: %s.", key, err.Error()),
+				})
+			}
+		}
+	}
+	if !complete {
+		return problems
+	}
+	for _, item := range configuration {
+		param, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := param["name"].(string)
+		required, _ := param["required"].(bool)
+		defaultValue, _ := param["defaultValue"].(string)
+		if required && len(defaultValue) == 0 && !configured[name] {
+			display, _ := param["display"].(string)
+			problems = append(problems, integrationParameterProblem{
+				"",
+				"Missing integration parameter",
+				fmt.Sprintf("The integration requires parameter %q (%s) to be set.", name, display),
+			})
+		}
+	}
+	return problems
+}
@@ -0,0 +1,761 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// The acceptance tests need a terraform binary and a live XSOAR server. The mock tests drive the
+// provider over the plugin protocol the same way terraform does, against the fake from mock_server_test.go.
+
+type mockUnknownValue struct{}
+
+// mockUnknown marks a value that is unknown in a plan
+var mockUnknown = mockUnknownValue{}
+
+type mockTerraform struct {
+	t      *testing.T
+	ctx    context.Context
+	server tfprotov6.ProviderServer
+	schema *tfprotov6.GetProviderSchemaResponse
+}
+
+// newMockTerraform starts a provider server configured against the given fake XSOAR server
+func newMockTerraform(t *testing.T, m *mockXSOAR) *mockTerraform {
+	return newMockTerraformWithConfig(t, map[string]interface{}{
+		"main_host": m.URL,
+		"api_key":   mockAPIKey,
+	})
+}
+
+func newMockTerraformWithConfig(t *testing.T, config map[string]interface{}) *mockTerraform {
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New()())()
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mockDiagnosticsError(schema.Diagnostics); err != nil {
+		t.Fatal(err)
+	}
+	tf := &mockTerraform{t: t, ctx: ctx, server: server, schema: schema}
+
+	providerConfig, err := mockDynamicValue(schema.Provider.ValueType(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.ConfigureProvider(ctx, &tfprotov6.ConfigureProviderRequest{
+		TerraformVersion: "1.2.0",
+		Config:           &providerConfig,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = mockDiagnosticsError(resp.Diagnostics); err != nil {
+		t.Fatal(err)
+	}
+	return tf
+}
+
+// mockResource tracks the state of a single resource across lifecycle steps
+type mockResource struct {
+	tf       *mockTerraform
+	typeName string
+	schema   *tfprotov6.Schema
+	state    tftypes.Value
+	private  []byte
+	// warnings returned by the last step
+	warnings []string
+}
+
+func (tf *mockTerraform) resource(typeName string) *mockResource {
+	schema, ok := tf.schema.ResourceSchemas[typeName]
+	if !ok {
+		tf.t.Fatalf("resource %s is not registered with the provider", typeName)
+	}
+	return &mockResource{
+		tf:       tf,
+		typeName: typeName,
+		schema:   schema,
+		state:    tftypes.NewValue(schema.ValueType(), nil),
+	}
+}
+
+// apply plans and applies config the way terraform apply does, replacing the resource when required.
+// The result is refreshed and must not produce a diff when planned again.
+func (r *mockResource) apply(config map[string]interface{}) error {
+	r.warnings = nil
+	ctx := r.tf.ctx
+	typ := r.schema.ValueType()
+	config = mockNormalizeConfig(r.schema.Block, config)
+	configValue, err := mockValue(typ, config)
+	if err != nil {
+		return err
+	}
+	configDynamic, err := tfprotov6.NewDynamicValue(typ, configValue)
+	if err != nil {
+		return err
+	}
+
+	validateResp, err := r.tf.server.ValidateResourceConfig(ctx, &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: r.typeName,
+		Config:   &configDynamic,
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.diagnostics(validateResp.Diagnostics); err != nil {
+		return err
+	}
+
+	planned, requiresReplace, err := r.plan(config, configDynamic)
+	if err != nil {
+		return err
+	}
+	if requiresReplace && !r.state.IsNull() {
+		if err = r.destroy(); err != nil {
+			return err
+		}
+		if planned, _, err = r.plan(config, configDynamic); err != nil {
+			return err
+		}
+	}
+	if planned.Equal(r.state) {
+		return nil
+	}
+
+	priorState, err := tfprotov6.NewDynamicValue(typ, r.state)
+	if err != nil {
+		return err
+	}
+	plannedState, err := tfprotov6.NewDynamicValue(typ, planned)
+	if err != nil {
+		return err
+	}
+	applyResp, err := r.tf.server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       r.typeName,
+		PriorState:     &priorState,
+		PlannedState:   &plannedState,
+		Config:         &configDynamic,
+		PlannedPrivate: r.private,
+	})
+	if err != nil {
+		return err
+	}
+	if applyResp.NewState != nil {
+		newState, err := applyResp.NewState.Unmarshal(typ)
+		if err != nil {
+			return err
+		}
+		r.state = newState
+		r.private = applyResp.Private
+	}
+	if err = r.diagnostics(applyResp.Diagnostics); err != nil {
+		return err
+	}
+	if diffs := mockDiff("", mockGo(planned), mockGo(r.state)); len(diffs) > 0 {
+		return fmt.Errorf("provider produced inconsistent result after apply:\n%s", strings.Join(diffs, "\n"))
+	}
+
+	if err = r.refresh(); err != nil {
+		return err
+	}
+	if r.state.IsNull() {
+		return fmt.Errorf("%s was removed from state after apply", r.typeName)
+	}
+	planned, _, err = r.plan(config, configDynamic)
+	if err != nil {
+		return err
+	}
+	if diffs := mockDiff("", mockGo(r.state), mockGo(planned)); len(diffs) > 0 {
+		return fmt.Errorf("plan was not empty after apply:\n%s", strings.Join(diffs, "\n"))
+	}
+	return nil
+}
+
+// planOnly validates and plans config the way terraform plan does, without applying it. Values set to
+// mockUnknown are unknown until apply.
+func (r *mockResource) planOnly(config map[string]interface{}) error {
+	typ := r.schema.ValueType()
+	config = mockNormalizeConfig(r.schema.Block, config)
+	configValue, err := mockValue(typ, config)
+	if err != nil {
+		return err
+	}
+	configDynamic, err := tfprotov6.NewDynamicValue(typ, configValue)
+	if err != nil {
+		return err
+	}
+	validateResp, err := r.tf.server.ValidateResourceConfig(r.tf.ctx, &tfprotov6.ValidateResourceConfigRequest{
+		TypeName: r.typeName,
+		Config:   &configDynamic,
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.diagnostics(validateResp.Diagnostics); err != nil {
+		return err
+	}
+	_, _, err = r.plan(config, configDynamic)
+	return err
+}
+
+// mustApply is apply that fails the test on error
+func (r *mockResource) mustApply(config map[string]interface{}) {
+	r.tf.t.Helper()
+	if err := r.apply(config); err != nil {
+		r.tf.t.Fatalf("applying %s: %s", r.typeName, err)
+	}
+}
+
+func (r *mockResource) plan(config map[string]interface{}, configDynamic tfprotov6.DynamicValue) (tftypes.Value, bool, error) {
+	typ := r.schema.ValueType()
+	proposed, err := mockValue(typ, mockProposedNew(r.schema.Block, mockObject(mockGo(r.state)), config))
+	if err != nil {
+		return tftypes.Value{}, false, err
+	}
+	priorState, err := tfprotov6.NewDynamicValue(typ, r.state)
+	if err != nil {
+		return tftypes.Value{}, false, err
+	}
+	proposedState, err := tfprotov6.NewDynamicValue(typ, proposed)
+	if err != nil {
+		return tftypes.Value{}, false, err
+	}
+	resp, err := r.tf.server.PlanResourceChange(r.tf.ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         r.typeName,
+		PriorState:       &priorState,
+		ProposedNewState: &proposedState,
+		Config:           &configDynamic,
+		PriorPrivate:     r.private,
+	})
+	if err != nil {
+		return tftypes.Value{}, false, err
+	}
+	if err = r.diagnostics(resp.Diagnostics); err != nil {
+		return tftypes.Value{}, false, err
+	}
+	planned, err := resp.PlannedState.Unmarshal(typ)
+	if err != nil {
+		return tftypes.Value{}, false, err
+	}
+	return planned, len(resp.RequiresReplace) > 0, nil
+}
+
+// refresh reads the resource into state, a removed resource leaves a null state
+func (r *mockResource) refresh() error {
+	typ := r.schema.ValueType()
+	currentState, err := tfprotov6.NewDynamicValue(typ, r.state)
+	if err != nil {
+		return err
+	}
+	resp, err := r.tf.server.ReadResource(r.tf.ctx, &tfprotov6.ReadResourceRequest{
+		TypeName:     r.typeName,
+		CurrentState: &currentState,
+		Private:      r.private,
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.diagnostics(resp.Diagnostics); err != nil {
+		return err
+	}
+	newState, err := resp.NewState.Unmarshal(typ)
+	if err != nil {
+		return err
+	}
+	r.state = newState
+	r.private = resp.Private
+	return nil
+}
+
+// importState imports id into a new resource and compares it with the managed one the way
+// ImportStateVerify does, ignoring the listed attributes
+func (r *mockResource) importState(id string, ignore ...string) error {
+	resp, err := r.tf.server.ImportResourceState(r.tf.ctx, &tfprotov6.ImportResourceStateRequest{
+		TypeName: r.typeName,
+		ID:       id,
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.diagnostics(resp.Diagnostics); err != nil {
+		return err
+	}
+	if len(resp.ImportedResources) != 1 {
+		return fmt.Errorf("expected 1 imported resource, got %d", len(resp.ImportedResources))
+	}
+	imported := &mockResource{tf: r.tf, typeName: r.typeName, schema: r.schema, private: resp.ImportedResources[0].Private}
+	imported.state, err = resp.ImportedResources[0].State.Unmarshal(r.schema.ValueType())
+	if err != nil {
+		return err
+	}
+	if err = imported.refresh(); err != nil {
+		return err
+	}
+	if imported.state.IsNull() {
+		return fmt.Errorf("imported %s %s was removed from state on read", r.typeName, id)
+	}
+	expected, actual := mockObject(mockGo(r.state)), mockObject(mockGo(imported.state))
+	for _, name := range ignore {
+		delete(expected, name)
+		delete(actual, name)
+	}
+	if diffs := mockDiff("", expected, actual); len(diffs) > 0 {
+		return fmt.Errorf("imported state does not match:\n%s", strings.Join(diffs, "\n"))
+	}
+	return nil
+}
+
+func (r *mockResource) mustImport(id string, ignore ...string) {
+	r.tf.t.Helper()
+	if err := r.importState(id, ignore...); err != nil {
+		r.tf.t.Fatalf("importing %s %s: %s", r.typeName, id, err)
+	}
+}
+
+// destroy plans and applies the deletion of the resource
+func (r *mockResource) destroy() error {
+	ctx := r.tf.ctx
+	typ := r.schema.ValueType()
+	null := tftypes.NewValue(typ, nil)
+	priorState, err := tfprotov6.NewDynamicValue(typ, r.state)
+	if err != nil {
+		return err
+	}
+	nullState, err := tfprotov6.NewDynamicValue(typ, null)
+	if err != nil {
+		return err
+	}
+	planResp, err := r.tf.server.PlanResourceChange(ctx, &tfprotov6.PlanResourceChangeRequest{
+		TypeName:         r.typeName,
+		PriorState:       &priorState,
+		ProposedNewState: &nullState,
+		Config:           &nullState,
+		PriorPrivate:     r.private,
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.diagnostics(planResp.Diagnostics); err != nil {
+		return err
+	}
+	applyResp, err := r.tf.server.ApplyResourceChange(ctx, &tfprotov6.ApplyResourceChangeRequest{
+		TypeName:       r.typeName,
+		PriorState:     &priorState,
+		PlannedState:   &nullState,
+		Config:         &nullState,
+		PlannedPrivate: r.private,
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.diagnostics(applyResp.Diagnostics); err != nil {
+		return err
+	}
+	newState, err := applyResp.NewState.Unmarshal(typ)
+	if err != nil {
+		return err
+	}
+	if !newState.IsNull() {
+		return fmt.Errorf("%s was not removed from state on destroy", r.typeName)
+	}
+	r.state = newState
+	r.private = nil
+	return nil
+}
+
+func (r *mockResource) mustDestroy() {
+	r.tf.t.Helper()
+	if err := r.destroy(); err != nil {
+		r.tf.t.Fatalf("destroying %s: %s", r.typeName, err)
+	}
+}
+
+// attr returns a top level attribute of the current state
+func (r *mockResource) attr(name string) interface{} {
+	return mockObject(mockGo(r.state))[name]
+}
+
+// attrString returns a top level string attribute of the current state
+func (r *mockResource) attrString(name string) string {
+	s, _ := r.attr(name).(string)
+	return s
+}
+
+func (r *mockResource) diagnostics(diags []*tfprotov6.Diagnostic) error {
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityWarning {
+			r.warnings = append(r.warnings, d.Summary+": "+d.Detail)
+		}
+	}
+	return mockDiagnosticsError(diags)
+}
+
+// readDataSource validates and reads a data source, returning its state
+func (tf *mockTerraform) readDataSource(typeName string, config map[string]interface{}) (map[string]interface{}, error) {
+	schema, ok := tf.schema.DataSourceSchemas[typeName]
+	if !ok {
+		tf.t.Fatalf("data source %s is not registered with the provider", typeName)
+	}
+	typ := schema.ValueType()
+	configValue, err := mockValue(typ, mockNormalizeConfig(schema.Block, config))
+	if err != nil {
+		return nil, err
+	}
+	configDynamic, err := tfprotov6.NewDynamicValue(typ, configValue)
+	if err != nil {
+		return nil, err
+	}
+	validateResp, err := tf.server.ValidateDataResourceConfig(tf.ctx, &tfprotov6.ValidateDataResourceConfigRequest{
+		TypeName: typeName,
+		Config:   &configDynamic,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = mockDiagnosticsError(validateResp.Diagnostics); err != nil {
+		return nil, err
+	}
+	resp, err := tf.server.ReadDataSource(tf.ctx, &tfprotov6.ReadDataSourceRequest{
+		TypeName: typeName,
+		Config:   &configDynamic,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = mockDiagnosticsError(resp.Diagnostics); err != nil {
+		return nil, err
+	}
+	state, err := resp.State.Unmarshal(typ)
+	if err != nil {
+		return nil, err
+	}
+	return mockObject(mockGo(state)), nil
+}
+
+func (tf *mockTerraform) mustReadDataSource(typeName string, config map[string]interface{}) map[string]interface{} {
+	tf.t.Helper()
+	state, err := tf.readDataSource(typeName, config)
+	if err != nil {
+		tf.t.Fatalf("reading data source %s: %s", typeName, err)
+	}
+	return state
+}
+
+func mockDiagnosticsError(diags []*tfprotov6.Diagnostic) error {
+	var errs []string
+	for _, d := range diags {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			errs = append(errs, d.Summary+": "+d.Detail)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// mockNormalizeConfig fills in absent nested blocks as empty collections the way terraform sends them
+func mockNormalizeConfig(block *tfprotov6.SchemaBlock, config map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	for k, v := range config {
+		out[k] = v
+	}
+	for _, b := range block.BlockTypes {
+		switch b.Nesting {
+		case tfprotov6.SchemaNestedBlockNestingModeList, tfprotov6.SchemaNestedBlockNestingModeSet:
+			var elems []interface{}
+			for _, elem := range mockSlice(out[b.TypeName]) {
+				elems = append(elems, mockNormalizeConfig(b.Block, mockObject(elem)))
+			}
+			if elems == nil {
+				elems = []interface{}{}
+			}
+			out[b.TypeName] = elems
+		case tfprotov6.SchemaNestedBlockNestingModeSingle:
+			if v, ok := out[b.TypeName]; ok && v != nil {
+				out[b.TypeName] = mockNormalizeConfig(b.Block, mockObject(v))
+			}
+		}
+	}
+	return out
+}
+
+// mockProposedNew merges config into the prior state, keeping computed values which are not configured
+func mockProposedNew(block *tfprotov6.SchemaBlock, prior, config map[string]interface{}) map[string]interface{} {
+	out := map[string]interface{}{}
+	for _, a := range block.Attributes {
+		v := config[a.Name]
+		if v == nil && a.Computed && prior != nil {
+			v = prior[a.Name]
+		}
+		out[a.Name] = v
+	}
+	for _, b := range block.BlockTypes {
+		switch b.Nesting {
+		case tfprotov6.SchemaNestedBlockNestingModeSingle:
+			if config[b.TypeName] != nil {
+				var priorBlock map[string]interface{}
+				if prior != nil {
+					priorBlock = mockObject(prior[b.TypeName])
+				}
+				out[b.TypeName] = mockProposedNew(b.Block, priorBlock, mockObject(config[b.TypeName]))
+			}
+		case tfprotov6.SchemaNestedBlockNestingModeList:
+			var priorElems []interface{}
+			if prior != nil {
+				priorElems = mockSlice(prior[b.TypeName])
+			}
+			elems := []interface{}{}
+			for i, elem := range mockSlice(config[b.TypeName]) {
+				var priorElem map[string]interface{}
+				if i < len(priorElems) {
+					priorElem = mockObject(priorElems[i])
+				}
+				elems = append(elems, mockProposedNew(b.Block, priorElem, mockObject(elem)))
+			}
+			out[b.TypeName] = elems
+		default:
+			elems := []interface{}{}
+			for _, elem := range mockSlice(config[b.TypeName]) {
+				elems = append(elems, mockProposedNew(b.Block, nil, mockObject(elem)))
+			}
+			out[b.TypeName] = elems
+		}
+	}
+	return out
+}
+
+func mockObject(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+func mockSlice(v interface{}) []interface{} {
+	switch s := v.(type) {
+	case []interface{}:
+		return s
+	case []string:
+		var out []interface{}
+		for _, e := range s {
+			out = append(out, e)
+		}
+		return out
+	case []map[string]interface{}:
+		var out []interface{}
+		for _, e := range s {
+			out = append(out, e)
+		}
+		return out
+	}
+	return nil
+}
+
+func mockDynamicValue(typ tftypes.Type, v interface{}) (tfprotov6.DynamicValue, error) {
+	value, err := mockValue(typ, v)
+	if err != nil {
+		return tfprotov6.DynamicValue{}, err
+	}
+	return tfprotov6.NewDynamicValue(typ, value)
+}
+
+// mockValue converts plain go values into terraform values of the given type
+func mockValue(typ tftypes.Type, v interface{}) (tftypes.Value, error) {
+	if v == nil {
+		return tftypes.NewValue(typ, nil), nil
+	}
+	if _, ok := v.(mockUnknownValue); ok {
+		return tftypes.NewValue(typ, tftypes.UnknownValue), nil
+	}
+	if tv, ok := v.(tftypes.Value); ok {
+		return tv, nil
+	}
+	switch t := typ.(type) {
+	case tftypes.List, tftypes.Set:
+		var elemType tftypes.Type
+		if list, ok := t.(tftypes.List); ok {
+			elemType = list.ElementType
+		} else {
+			elemType = t.(tftypes.Set).ElementType
+		}
+		elems := []tftypes.Value{}
+		for _, e := range mockSlice(v) {
+			elem, err := mockValue(elemType, e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems = append(elems, elem)
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case tftypes.Map:
+		elems := map[string]tftypes.Value{}
+		values := map[string]interface{}{}
+		switch m := v.(type) {
+		case map[string]interface{}:
+			values = m
+		case map[string]string:
+			for k, e := range m {
+				values[k] = e
+			}
+		default:
+			return tftypes.Value{}, fmt.Errorf("cannot use %T as a map", v)
+		}
+		for k, e := range values {
+			elem, err := mockValue(t.ElementType, e)
+			if err != nil {
+				return tftypes.Value{}, err
+			}
+			elems[k] = elem
+		}
+		return tftypes.NewValue(typ, elems), nil
+	case tftypes.Object:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("cannot use %T as an object", v)
+		}
+		for k := range m {
+			if _, ok := t.AttributeTypes[k]; !ok {
+				return tftypes.Value{}, fmt.Errorf("unsupported attribute %q", k)
+			}
+		}
+		attrs := map[string]tftypes.Value{}
+		for k, attrType := range t.AttributeTypes {
+			attr, err := mockValue(attrType, m[k])
+			if err != nil {
+				return tftypes.Value{}, fmt.Errorf("%s: %w", k, err)
+			}
+			attrs[k] = attr
+		}
+		return tftypes.NewValue(typ, attrs), nil
+	}
+	switch {
+	case typ.Equal(tftypes.Number):
+		switch n := v.(type) {
+		case int:
+			return tftypes.NewValue(typ, big.NewFloat(float64(n))), nil
+		case int64:
+			return tftypes.NewValue(typ, big.NewFloat(float64(n))), nil
+		case float64:
+			return tftypes.NewValue(typ, big.NewFloat(n)), nil
+		}
+		return tftypes.Value{}, fmt.Errorf("cannot use %T as a number", v)
+	case typ.Equal(tftypes.String):
+		if s, ok := v.(string); ok {
+			return tftypes.NewValue(typ, s), nil
+		}
+		return tftypes.Value{}, fmt.Errorf("cannot use %T as a string", v)
+	case typ.Equal(tftypes.Bool):
+		if b, ok := v.(bool); ok {
+			return tftypes.NewValue(typ, b), nil
+		}
+		return tftypes.Value{}, fmt.Errorf("cannot use %T as a bool", v)
+	}
+	return tftypes.Value{}, fmt.Errorf("unsupported type %s", typ)
+}
+
+// mockGo converts terraform values into plain go values, sets are returned sorted
+func mockGo(v tftypes.Value) interface{} {
+	if !v.IsKnown() {
+		return mockUnknown
+	}
+	if v.IsNull() {
+		return nil
+	}
+	typ := v.Type()
+	switch typ.(type) {
+	case tftypes.List, tftypes.Set, tftypes.Tuple:
+		var elems []tftypes.Value
+		_ = v.As(&elems)
+		out := []interface{}{}
+		for _, elem := range elems {
+			out = append(out, mockGo(elem))
+		}
+		if _, ok := typ.(tftypes.Set); ok {
+			sort.Slice(out, func(i, j int) bool {
+				return fmt.Sprint(out[i]) < fmt.Sprint(out[j])
+			})
+		}
+		return out
+	case tftypes.Map, tftypes.Object:
+		var attrs map[string]tftypes.Value
+		_ = v.As(&attrs)
+		out := map[string]interface{}{}
+		for k, attr := range attrs {
+			out[k] = mockGo(attr)
+		}
+		return out
+	}
+	switch {
+	case typ.Equal(tftypes.Number):
+		var f big.Float
+		_ = v.As(&f)
+		n, _ := f.Float64()
+		return n
+	case typ.Equal(tftypes.Bool):
+		var b bool
+		_ = v.As(&b)
+		return b
+	default:
+		var s string
+		_ = v.As(&s)
+		return s
+	}
+}
+
+// mockDiff lists the differences between two converted values, unknown expected values match anything
+func mockDiff(path string, expected, actual interface{}) []string {
+	if _, ok := expected.(mockUnknownValue); ok {
+		return nil
+	}
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected %v, got %v", path, expected, actual)}
+		}
+		var diffs []string
+		keys := map[string]bool{}
+		for k := range e {
+			keys[k] = true
+		}
+		for k := range a {
+			keys[k] = true
+		}
+		var sorted []string
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			diffs = append(diffs, mockDiff(strings.TrimPrefix(path+"."+k, "."), e[k], a[k])...)
+		}
+		return diffs
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(e) {
+			return []string{fmt.Sprintf("%s: expected %v, got %v", path, expected, actual)}
+		}
+		var diffs []string
+		for i := range e {
+			diffs = append(diffs, mockDiff(fmt.Sprintf("%s[%d]", path, i), e[i], a[i])...)
+		}
+		return diffs
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		return []string{fmt.Sprintf("%s: expected %#v, got %#v", path, expected, actual)}
+	}
+	return nil
+}
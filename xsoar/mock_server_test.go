@@ -0,0 +1,1564 @@
+package xsoar
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const mockAPIKey = "mock-api-key"
+
+// mockXSOAR is an in-process fake of the XSOAR endpoints the provider calls.
+// Requests prefixed with /acc_<name> are routed to that account's content store.
+type mockXSOAR struct {
+	*httptest.Server
+	t  *testing.T
+	mu sync.Mutex
+
+	nextId       int
+	accounts     *mockStore
+	haGroups     *mockStore
+	hosts        *mockStore
+	integrations []interface{}
+	tenants      map[string]*mockTenant
+	// id of the HA group whose installer was downloaded last, empty for a plain host installer
+	installerGroup string
+	// the engine whose installer was last downloaded
+	engineInstaller string
+	// storedParameters are the values integration instances are saved with in place of those sent, by parameter
+	storedParameters map[string]interface{}
+	// failures are the statuses requests are answered with in place of their route, by method and pattern
+	failures map[string]int
+}
+
+// mockTenant holds the content of a single account, the main tenant is stored under ""
+type mockTenant struct {
+	stores map[string]*mockStore
+}
+
+// store returns the collection of the given kind of content, e.g. "classifiers"
+func (t *mockTenant) store(kind string) *mockStore {
+	s, ok := t.stores[kind]
+	if !ok {
+		s = newMockStore()
+		t.stores[kind] = s
+	}
+	return s
+}
+
+// mockStore is an insertion ordered collection of API objects keyed by id
+type mockStore struct {
+	ids   []string
+	items map[string]map[string]interface{}
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{items: map[string]map[string]interface{}{}}
+}
+
+func (s *mockStore) put(id string, item map[string]interface{}) {
+	if _, ok := s.items[id]; !ok {
+		s.ids = append(s.ids, id)
+	}
+	s.items[id] = item
+}
+
+func (s *mockStore) get(id string) map[string]interface{} {
+	return s.items[id]
+}
+
+func (s *mockStore) find(key, value string) map[string]interface{} {
+	for _, id := range s.ids {
+		if v, ok := s.items[id][key].(string); ok && v == value {
+			return s.items[id]
+		}
+	}
+	return nil
+}
+
+func (s *mockStore) remove(id string) bool {
+	if _, ok := s.items[id]; !ok {
+		return false
+	}
+	delete(s.items, id)
+	for i, v := range s.ids {
+		if v == id {
+			s.ids = append(s.ids[:i], s.ids[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (s *mockStore) list() []interface{} {
+	list := make([]interface{}, 0, len(s.ids))
+	for _, id := range s.ids {
+		list = append(list, s.items[id])
+	}
+	return list
+}
+
+func newMockTenant() *mockTenant {
+	return &mockTenant{stores: map[string]*mockStore{}}
+}
+
+type mockRoute struct {
+	method  string
+	pattern string
+	handler func(m *mockXSOAR, req *mockRequest) (int, interface{})
+}
+
+// mockRequest is a parsed request handed to a route handler
+type mockRequest struct {
+	tenant *mockTenant
+	acc    string
+	params []string
+	body   map[string]interface{}
+	raw    []byte
+	http   *http.Request
+}
+
+var mockRoutes = []mockRoute{
+	{"GET", "accounts", mockListAccounts},
+	{"GET", "accounts/data", mockListAccountsDetails},
+	{"POST", "account", mockCreateAccount},
+	{"POST", "account/update/*", mockUpdateAccount},
+	{"DELETE", "account/purge/*", mockDeleteAccount},
+	{"POST", "host/move/*/*", mockMoveAccount},
+	{"GET", "ha-groups", mockListHAGroups},
+	{"POST", "ha-group/create", mockCreateHAGroup},
+	{"GET", "ha-group/*", mockGetHAGroup},
+	{"DELETE", "ha-group/*", mockDeleteHAGroup},
+	{"POST", "host/build", mockBuildInstaller},
+	{"POST", "host/build/*", mockBuildInstaller},
+	{"GET", "host/download", mockDownloadInstaller},
+	{"GET", "host/download/*", mockDownloadInstaller},
+	{"GET", "hosts", mockListHosts},
+	{"DELETE", "host/*", mockDeleteHost},
+	{"POST", "settings/integration/search", mockSearchIntegrations},
+	{"PUT", "settings/integration", mockCreateUpdateIntegrationInstance},
+	{"POST", "settings/integration/test", mockTestIntegrationInstance},
+	{"DELETE", "settings/integration/*", mockDeleteIntegrationInstance},
+	{"POST", "classifier/search", mockSearchClassifiers},
+	{"POST", "classifier", mockCreateUpdateClassifier},
+	{"DELETE", "classifier/*", mockDeleteClassifier},
+	{"POST", "playbook/save/yaml", mockSavePlaybook},
+	{"GET", "playbook/*/yaml", mockGetPlaybookYAML},
+	{"POST", "playbook/search", mockSearchPlaybooks},
+	{"POST", "playbook/delete", mockDeletePlaybook},
+	{"POST", "automation", mockSaveScript},
+	{"POST", "automation/search", mockSearchScripts},
+	{"POST", "automation/delete", mockDeleteScript},
+	{"GET", "incidenttype", mockListIncidentTypes},
+	{"POST", "incidenttype", mockSaveIncidentType},
+	{"POST", "incidenttype/delete", mockDeleteIncidentType},
+	{"GET", "incidentfields", mockListFields},
+	{"POST", "incidentfield", mockSaveField},
+	{"DELETE", "incidentfield/*", mockDeleteField},
+	{"GET", "layouts", mockListLayouts},
+	{"POST", "layouts/save", mockSaveLayout},
+	{"POST", "layout/*/remove", mockDeleteLayout},
+	{"GET", "lists", mockListLists},
+	{"POST", "lists/save", mockSaveList},
+	{"POST", "lists/delete", mockDeleteList},
+	{"POST", "jobs", mockSaveJob},
+	{"POST", "jobs/search", mockSearchJobs},
+	{"DELETE", "jobs/*", mockDeleteJob},
+	{"GET", "preprocess/rules", mockListPreprocessRules},
+	{"POST", "preprocess/rule", mockSavePreprocessRule},
+	{"POST", "preprocess/rules/order", mockOrderPreprocessRules},
+	{"DELETE", "preprocess/rule/*", mockDeletePreprocessRule},
+	{"GET", "roles", mockListRoles},
+	{"POST", "roles/update", mockSaveRole},
+	{"DELETE", "roles/*", mockDeleteRole},
+	{"GET", "users", mockListUsers},
+	{"POST", "users/update", mockSaveUser},
+	{"POST", "users/delete", mockDeleteUsers},
+	{"GET", "apikeys", mockListAPIKeys},
+	{"POST", "apikeys", mockCreateAPIKey},
+	{"DELETE", "apikeys/*", mockRevokeAPIKey},
+	{"GET", "engines", mockListEngines},
+	{"POST", "engines/create", mockCreateEngine},
+	{"GET", "engines/download/*/*", mockDownloadEngineInstaller},
+	{"POST", "engines/delete", mockDeleteEngines},
+	{"GET", "engines/groups", mockListEngineGroups},
+	{"POST", "engines/groups", mockSaveEngineGroup},
+	{"DELETE", "engines/groups/*", mockDeleteEngineGroup},
+	{"GET", "contentpacks/marketplace/*", mockGetMarketplacePack},
+	{"POST", "contentpacks/marketplace/install", mockInstallPacks},
+	{"GET", "contentpacks/metadata/installed", mockListInstalledPacks},
+	{"POST", "contentpacks/installed/upload", mockUploadPack},
+	{"POST", "contentpacks/installed/delete", mockDeletePacks},
+	{"POST", "settings/credentials", mockSearchCredentials},
+	{"PUT", "settings/credentials", mockSaveCredential},
+	{"POST", "settings/credentials/delete", mockDeleteCredentials},
+}
+
+// newMockXSOAR starts a fake XSOAR server that is shut down when the test completes
+func newMockXSOAR(t *testing.T) *mockXSOAR {
+	m := &mockXSOAR{
+		t:        t,
+		accounts: newMockStore(),
+		haGroups: newMockStore(),
+		hosts:    newMockStore(),
+		tenants:  map[string]*mockTenant{"": newMockTenant()},
+		integrations: []interface{}{
+			map[string]interface{}{
+				"name":              "MockIntegration",
+				"category":          "Utilities",
+				"canGetSamples":     false,
+				"integrationScript": nil,
+				"configuration": []interface{}{
+					map[string]interface{}{"name": "url", "display": "Server URL", "type": float64(0), "required": true, "defaultValue": "https://mock.local"},
+					map[string]interface{}{"name": "apikey", "display": "API Key", "type": float64(4), "required": true, "defaultValue": ""},
+					map[string]interface{}{"name": "insecure", "display": "Trust any certificate", "type": float64(8), "required": false, "defaultValue": "false"},
+				},
+			},
+			map[string]interface{}{
+				"name":              "MockFetchIntegration",
+				"category":          "Utilities",
+				"canGetSamples":     true,
+				"integrationScript": nil,
+				"configuration": []interface{}{
+					map[string]interface{}{"name": "url", "display": "Server URL", "type": float64(0), "required": true, "defaultValue": "https://mock.local"},
+					map[string]interface{}{"name": "isFetch", "display": "Fetch incidents", "type": float64(8), "required": false, "defaultValue": "false"},
+					map[string]interface{}{"name": "incidentType", "display": "Incident type", "type": float64(13), "required": false, "defaultValue": ""},
+					map[string]interface{}{"name": "incidentFetchInterval", "display": "Incidents Fetch Interval", "type": float64(19), "required": false, "defaultValue": "1"},
+					map[string]interface{}{"name": "mirror_direction", "display": "Incident Mirroring Direction", "type": float64(15), "required": false, "defaultValue": "None", "options": []interface{}{"None", "Incoming", "Outgoing", "Incoming And Outgoing"}},
+				},
+			},
+			map[string]interface{}{
+				"name":              "MockCredentialIntegration",
+				"category":          "Utilities",
+				"canGetSamples":     false,
+				"integrationScript": nil,
+				"configuration": []interface{}{
+					map[string]interface{}{"name": "url", "display": "Server URL", "type": float64(0), "required": true, "defaultValue": "https://mock.local"},
+					map[string]interface{}{"name": "credentials", "display": "Username", "type": float64(9), "required": true, "defaultValue": ""},
+				},
+			},
+		},
+	}
+	m.Server = httptest.NewServer(m)
+	t.Cleanup(m.Server.Close)
+	return m
+}
+
+// object returns a stored object of the given kind from an account, "" being the main tenant
+func (m *mockXSOAR) object(acc, kind, id string) map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tenant, ok := m.tenants[accountTenant(acc)]
+	if !ok {
+		return nil
+	}
+	return tenant.store(kind).get(id)
+}
+
+// putObject seeds an object of the given kind into an account and returns its id
+func (m *mockXSOAR) putObject(acc, kind string, object map[string]interface{}) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id, _ := object["id"].(string)
+	if id == "" {
+		id = m.newId()
+		object["id"] = id
+	}
+	m.tenants[accountTenant(acc)].store(kind).put(id, object)
+	return id
+}
+
+// addHAGroup seeds an HA group and returns its id
+func (m *mockXSOAR) addHAGroup(name string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := m.newId()
+	m.haGroups.put(id, map[string]interface{}{
+		"id":                   id,
+		"name":                 name,
+		"elasticsearchAddress": "http://elastic.local:9200",
+		"elasticIndexPrefix":   name + "_",
+		"accountIds":           []interface{}{},
+		"hostIds":              []interface{}{},
+	})
+	return id
+}
+
+// addAccount seeds an account into an HA group, which may be empty
+func (m *mockXSOAR) addAccount(name, hostGroupId string) {
+	req := &mockRequest{body: map[string]interface{}{
+		"name":         name,
+		"hostGroupId":  hostGroupId,
+		"accountRoles": []interface{}{"Administrator"},
+	}}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if status, body := mockCreateAccount(m, req); status != http.StatusOK {
+		m.t.Fatalf("could not seed account %s: %v", name, body)
+	}
+}
+
+// account returns the account with the given display name
+func (m *mockXSOAR) account(name string) map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.accounts.find("name", "acc_"+name)
+}
+
+// haGroup returns the HA group with the given id
+func (m *mockXSOAR) haGroup(id string) map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.haGroups.get(id)
+}
+
+// host returns the host with the given name
+func (m *mockXSOAR) host(name string) map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.hosts.find("host", name)
+}
+
+// removeHost removes a host behind the back of the provider
+func (m *mockXSOAR) removeHost(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if host := m.hosts.find("host", name); host != nil {
+		m.hosts.remove(host["id"].(string))
+	}
+}
+
+// removeObject removes a stored object of the given kind from an account behind the back of the provider
+func (m *mockXSOAR) removeObject(acc, kind, id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if tenant, ok := m.tenants[accountTenant(acc)]; ok {
+		tenant.store(kind).remove(id)
+	}
+}
+
+// fail answers every request matching the method and route pattern with the given status, 0 clears it
+func (m *mockXSOAR) fail(method, pattern string, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failures == nil {
+		m.failures = map[string]int{}
+	}
+	if status == 0 {
+		delete(m.failures, method+" "+pattern)
+		return
+	}
+	m.failures[method+" "+pattern] = status
+}
+
+func accountTenant(acc string) string {
+	if acc == "" {
+		return ""
+	}
+	return "acc_" + acc
+}
+
+func (m *mockXSOAR) newId() string {
+	m.nextId++
+	return fmt.Sprintf("mock-%04d", m.nextId)
+}
+
+func (m *mockXSOAR) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if key := r.Header.Get("Authorization"); key != mockAPIKey && m.tenants[""].store("apikeys").find("apikey", key) == nil {
+		mockWrite(w, http.StatusUnauthorized, map[string]interface{}{"error": "invalid api key"})
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	req := &mockRequest{http: r}
+	tenantName := ""
+	if strings.HasPrefix(segments[0], "acc_") {
+		tenantName, segments = segments[0], segments[1:]
+	}
+	tenant, ok := m.tenants[tenantName]
+	if !ok {
+		mockWrite(w, http.StatusNotFound, map[string]interface{}{"error": "account " + tenantName + " not found"})
+		return
+	}
+	req.tenant = tenant
+	req.acc = tenantName
+
+	raw, _ := io.ReadAll(r.Body)
+	req.raw = raw
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &req.body)
+	}
+	if req.body == nil {
+		req.body = map[string]interface{}{}
+	}
+
+	for _, route := range mockRoutes {
+		if route.method != r.Method {
+			continue
+		}
+		params, ok := mockMatch(route.pattern, segments)
+		if !ok {
+			continue
+		}
+		req.params = params
+		if status, ok := m.failures[route.method+" "+route.pattern]; ok {
+			mockWrite(w, status, map[string]interface{}{"error": http.StatusText(status)})
+			return
+		}
+		status, body := route.handler(m, req)
+		mockWrite(w, status, body)
+		return
+	}
+	m.t.Logf("mock xsoar: unhandled request %s %s", r.Method, r.URL.Path)
+	mockWrite(w, http.StatusNotFound, map[string]interface{}{"error": "not found"})
+}
+
+func mockMatch(pattern string, segments []string) ([]string, bool) {
+	parts := strings.Split(pattern, "/")
+	if len(parts) != len(segments) {
+		return nil, false
+	}
+	var params []string
+	for i, part := range parts {
+		if part == "*" {
+			params = append(params, segments[i])
+		} else if part != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// file returns the content of the multipart file field of an upload
+func (req *mockRequest) file(field string) ([]byte, error) {
+	_, params, err := mime.ParseMediaType(req.http.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	form, err := multipart.NewReader(bytes.NewReader(req.raw), params["boundary"]).ReadForm(1 << 20)
+	if err != nil {
+		return nil, err
+	}
+	files := form.File[field]
+	if len(files) == 0 {
+		return nil, fmt.Errorf("missing file field %s", field)
+	}
+	f, err := files[0].Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+func mockWrite(w http.ResponseWriter, status int, body interface{}) {
+	if b, ok := body.([]byte); ok {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.WriteHeader(status)
+		_, _ = w.Write(b)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func mockStrings(v interface{}) []interface{} {
+	list := []interface{}{}
+	if items, ok := v.([]interface{}); ok {
+		list = append(list, items...)
+	}
+	return list
+}
+
+func mockRemoveString(list []interface{}, value string) []interface{} {
+	result := []interface{}{}
+	for _, v := range list {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// accounts
+
+func mockListAccounts(m *mockXSOAR, _ *mockRequest) (int, interface{}) {
+	return http.StatusOK, m.accounts.list()
+}
+
+func mockListAccountsDetails(m *mockXSOAR, _ *mockRequest) (int, interface{}) {
+	details := map[string]interface{}{}
+	for _, item := range m.accounts.list() {
+		account := item.(map[string]interface{})
+		var roles []interface{}
+		for _, role := range account["roles"].(map[string]interface{})["roles"].([]interface{}) {
+			roles = append(roles, map[string]interface{}{"name": role})
+		}
+		details[account["name"].(string)] = map[string]interface{}{
+			"name":  account["name"],
+			"roles": roles,
+		}
+	}
+	return http.StatusOK, details
+}
+
+func mockCreateAccount(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	name, _ := req.body["name"].(string)
+	accName := "acc_" + name
+	if name == "" || m.accounts.find("name", accName) != nil {
+		return http.StatusBadRequest, map[string]interface{}{"error": "invalid account name " + name}
+	}
+	hostGroupId, _ := req.body["hostGroupId"].(string)
+	id := m.newId()
+	m.accounts.put(id, map[string]interface{}{
+		"id":                id,
+		"name":              accName,
+		"displayName":       name,
+		"hostGroupId":       hostGroupId,
+		"status":            "ready",
+		"propagationLabels": mockStrings(req.body["propagationLabels"]),
+		"roles":             map[string]interface{}{"roles": mockStrings(req.body["accountRoles"])},
+	})
+	if group := m.haGroups.get(hostGroupId); group != nil {
+		group["accountIds"] = append(mockStrings(group["accountIds"]), id)
+	}
+	m.tenants[accName] = newMockTenant()
+	return http.StatusOK, m.accounts.list()
+}
+
+func mockUpdateAccount(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	account := m.accounts.find("name", "acc_"+strings.TrimPrefix(req.params[0], "acc_"))
+	if account == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "account not found"}
+	}
+	if roles, ok := req.body["selectedRoles"]; ok {
+		account["roles"] = map[string]interface{}{"roles": mockStrings(roles)}
+	}
+	if labels, ok := req.body["selectedPropagationLabels"]; ok {
+		account["propagationLabels"] = mockStrings(labels)
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+func mockDeleteAccount(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	account := m.accounts.find("name", req.params[0])
+	if account == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "account not found"}
+	}
+	id := account["id"].(string)
+	if group := m.haGroups.get(account["hostGroupId"].(string)); group != nil {
+		group["accountIds"] = mockRemoveString(mockStrings(group["accountIds"]), id)
+	}
+	m.accounts.remove(id)
+	delete(m.tenants, req.params[0])
+	return http.StatusOK, m.accounts.list()
+}
+
+func mockMoveAccount(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	account := m.accounts.find("name", req.params[0])
+	group := m.haGroups.get(req.params[1])
+	if account == nil || group == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "account or host group not found"}
+	}
+	id := account["id"].(string)
+	if previous := m.haGroups.get(account["hostGroupId"].(string)); previous != nil {
+		previous["accountIds"] = mockRemoveString(mockStrings(previous["accountIds"]), id)
+	}
+	account["hostGroupId"] = req.params[1]
+	group["accountIds"] = append(mockStrings(group["accountIds"]), id)
+	return http.StatusOK, m.accounts.list()
+}
+
+// ha groups and hosts
+
+func mockListHAGroups(m *mockXSOAR, _ *mockRequest) (int, interface{}) {
+	return http.StatusOK, m.haGroups.list()
+}
+
+func mockCreateHAGroup(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	id, _ := req.body["id"].(string)
+	group := m.haGroups.get(id)
+	if group == nil {
+		id = m.newId()
+		group = map[string]interface{}{
+			"id":         id,
+			"accountIds": []interface{}{},
+			"hostIds":    []interface{}{},
+		}
+	}
+	group["name"] = req.body["name"]
+	group["elasticsearchAddress"] = req.body["elasticsearchAddress"]
+	group["elasticIndexPrefix"] = req.body["elasticIndexPrefix"]
+	m.haGroups.put(id, group)
+	return http.StatusOK, group
+}
+
+func mockGetHAGroup(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	group := m.haGroups.get(req.params[0])
+	if group == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "ha group not found"}
+	}
+	return http.StatusOK, group
+}
+
+func mockDeleteHAGroup(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if !m.haGroups.remove(req.params[0]) {
+		return http.StatusNotFound, map[string]interface{}{"error": "ha group not found"}
+	}
+	return http.StatusOK, "deleted"
+}
+
+func mockBuildInstaller(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if len(req.params) > 0 && m.haGroups.get(req.params[0]) == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "ha group not found"}
+	}
+	return http.StatusOK, "installer built"
+}
+
+func mockDownloadInstaller(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	m.installerGroup = ""
+	if len(req.params) > 0 {
+		m.installerGroup = req.params[0]
+	}
+	return http.StatusOK, mockInstaller
+}
+
+// mockInstaller is the installer the main server builds for hosts
+var mockInstaller = []byte("#!/bin/sh\necho mock installer\n")
+
+func mockListHosts(m *mockXSOAR, _ *mockRequest) (int, interface{}) {
+	return http.StatusOK, m.hosts.list()
+}
+
+func mockDeleteHost(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	host := m.hosts.get(req.params[0])
+	if host == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "host not found"}
+	}
+	group := m.haGroups.get(host["hostGroupId"].(string))
+	if group != nil {
+		if group["name"] == host["host"] {
+			m.haGroups.remove(group["id"].(string))
+		} else {
+			group["hostIds"] = mockRemoveString(mockStrings(group["hostIds"]), req.params[0])
+		}
+	}
+	m.hosts.remove(req.params[0])
+	return http.StatusOK, "deleted"
+}
+
+// installHost registers a host the way a finished installer run does. Hosts installed outside an
+// HA group get a group of their own named after the host.
+func (m *mockXSOAR) installHost(name string, elasticsearchUrl string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.hosts.find("host", name) != nil {
+		return
+	}
+	id := m.newId()
+	group := m.haGroups.get(m.installerGroup)
+	if group == nil {
+		groupId := m.newId()
+		group = map[string]interface{}{
+			"id":                   groupId,
+			"name":                 name,
+			"elasticsearchAddress": elasticsearchUrl,
+			"elasticIndexPrefix":   "",
+			"accountIds":           []interface{}{},
+			"hostIds":              []interface{}{},
+		}
+		m.haGroups.put(groupId, group)
+	}
+	group["hostIds"] = append(mockStrings(group["hostIds"]), id)
+	elasticsearchAddress, _ := group["elasticsearchAddress"].(string)
+	m.hosts.put(id, map[string]interface{}{
+		"id":                   id,
+		"host":                 name,
+		"hostGroupId":          group["id"],
+		"elasticsearchAddress": elasticsearchAddress,
+		"status":               "active",
+	})
+}
+
+// integration instances
+
+func mockSearchIntegrations(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	// integrations installed in an account only are seeded into its "integrations" store
+	return http.StatusOK, map[string]interface{}{
+		"configurations": append(append([]interface{}{}, m.integrations...), req.tenant.store("integrations").list()...),
+		"instances":      req.tenant.store("instances").list(),
+	}
+}
+
+func mockCreateUpdateIntegrationInstance(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	brand, _ := req.body["brand"].(string)
+	if brand == "" {
+		return http.StatusBadRequest, map[string]interface{}{"error": "missing integration brand"}
+	}
+	id, _ := req.body["id"].(string)
+	if id == "" {
+		id = m.newId()
+	} else if req.tenant.store("instances").get(id) == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "integration instance not found"}
+	}
+	instance := map[string]interface{}{}
+	for key, value := range req.body {
+		if key != "configuration" {
+			instance[key] = value
+		}
+	}
+	instance["id"] = id
+	var data []interface{}
+	for _, item := range mockStrings(req.body["data"]) {
+		param := item.(map[string]interface{})
+		value := param["value"]
+		if stored, ok := m.storedParameters[param["name"].(string)]; ok {
+			value = stored
+		}
+		data = append(data, map[string]interface{}{
+			"name":     param["name"],
+			"display":  param["display"],
+			"value":    value,
+			"hasvalue": param["hasvalue"],
+			"type":     param["type"],
+		})
+	}
+	instance["data"] = data
+	req.tenant.store("instances").put(id, instance)
+	return http.StatusOK, instance
+}
+
+// mockTestIntegrationInstance passes the test of every instance but those configured with the API key "bad"
+func mockTestIntegrationInstance(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	id, _ := req.body["id"].(string)
+	if req.tenant.store("instances").get(id) == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "integration instance not found"}
+	}
+	for _, item := range mockStrings(req.body["data"]) {
+		if param := item.(map[string]interface{}); param["name"] == "apikey" && param["value"] == "bad" {
+			return http.StatusOK, map[string]interface{}{"success": false, "message": "Invalid API key"}
+		}
+	}
+	return http.StatusOK, map[string]interface{}{"success": true, "message": ""}
+}
+
+func mockDeleteIntegrationInstance(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if !req.tenant.store("instances").remove(req.params[0]) {
+		return http.StatusNotFound, map[string]interface{}{"error": "integration instance not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// classifiers and mappers
+
+func mockSearchClassifiers(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	return http.StatusOK, map[string]interface{}{"classifiers": req.tenant.store("classifiers").list()}
+}
+
+func mockCreateUpdateClassifier(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	id, _ := req.body["id"].(string)
+	if id == "" {
+		id = m.newId()
+	} else if req.tenant.store("classifiers").get(id) == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "classifier not found"}
+	}
+	classifier := map[string]interface{}{}
+	for key, value := range req.body {
+		classifier[key] = value
+	}
+	classifier["id"] = id
+	// mappers are sent with their mapping in keyTypeMap and stored under mapping
+	if classifierType, _ := classifier["type"].(string); strings.HasPrefix(classifierType, "mapping-") {
+		classifier["mapping"] = classifier["keyTypeMap"]
+		delete(classifier, "keyTypeMap")
+	}
+	req.tenant.store("classifiers").put(id, classifier)
+	return http.StatusOK, classifier
+}
+
+func mockDeleteClassifier(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if !req.tenant.store("classifiers").remove(req.params[0]) {
+		return http.StatusNotFound, map[string]interface{}{"error": "classifier not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// playbooks, stored with the YAML they are exported as, the uploaded YAML along with the keys XSOAR manages
+
+// mockQueryRegexp matches the field:"value" terms of a search query
+var mockQueryRegexp = regexp.MustCompile(`(\w+):"((?:[^"\\]|\\.)*)"`)
+
+// mockQueryMatch reports whether object has every field:"value" term of query
+func mockQueryMatch(query string, object map[string]interface{}) bool {
+	for _, match := range mockQueryRegexp.FindAllStringSubmatch(query, -1) {
+		if fmt.Sprint(object[match[1]]) != strings.ReplaceAll(match[2], `\"`, `"`) {
+			return false
+		}
+	}
+	return true
+}
+
+func mockSavePlaybook(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	content, err := req.file("file")
+	if err != nil {
+		return http.StatusBadRequest, map[string]interface{}{"error": err.Error()}
+	}
+	var doc map[string]interface{}
+	if err = yaml.Unmarshal(content, &doc); err != nil {
+		return http.StatusBadRequest, map[string]interface{}{"error": "invalid playbook yaml: " + err.Error()}
+	}
+	name, _ := doc["name"].(string)
+	if name == "" {
+		return http.StatusBadRequest, map[string]interface{}{"error": "playbook has no name"}
+	}
+	id, _ := doc["id"].(string)
+	if id == "" {
+		id = m.newId()
+	}
+	version := float64(1)
+	if previous := req.tenant.store("playbooks").get(id); previous != nil {
+		version = previous["version"].(float64) + 1
+	}
+	doc["id"] = id
+	doc["version"] = -1
+	doc["modified"] = time.Now().UTC().Format(time.RFC3339Nano)
+	if content, err = yaml.Marshal(doc); err != nil {
+		return http.StatusInternalServerError, map[string]interface{}{"error": err.Error()}
+	}
+	playbook := map[string]interface{}{"id": id, "name": name, "version": version, "yaml": string(content)}
+	req.tenant.store("playbooks").put(id, playbook)
+	return http.StatusOK, map[string]interface{}{"id": id, "name": name, "version": version}
+}
+
+func mockGetPlaybookYAML(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	playbook := req.tenant.store("playbooks").get(req.params[0])
+	if playbook == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "playbook not found"}
+	}
+	return http.StatusOK, []byte(playbook["yaml"].(string))
+}
+
+func mockSearchPlaybooks(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	query, _ := req.body["query"].(string)
+	playbooks := []interface{}{}
+	for _, item := range req.tenant.store("playbooks").list() {
+		playbook := item.(map[string]interface{})
+		if mockQueryMatch(query, playbook) {
+			playbooks = append(playbooks, map[string]interface{}{"id": playbook["id"], "name": playbook["name"]})
+		}
+	}
+	return http.StatusOK, map[string]interface{}{"playbooks": playbooks, "total": len(playbooks)}
+}
+
+func mockDeletePlaybook(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	id, _ := req.body["id"].(string)
+	if !req.tenant.store("playbooks").remove(id) {
+		return http.StatusNotFound, map[string]interface{}{"error": "playbook not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// automations
+
+func mockSaveScript(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	body, _ := req.body["script"].(map[string]interface{})
+	if body == nil || body["name"] == nil || body["script"] == nil {
+		return http.StatusBadRequest, map[string]interface{}{"error": "invalid script"}
+	}
+	id, _ := body["id"].(string)
+	if id == "" {
+		id = m.newId()
+	} else if req.tenant.store("scripts").get(id) == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "script not found"}
+	}
+	script := map[string]interface{}{}
+	for key, value := range body {
+		script[key] = value
+	}
+	script["id"] = id
+	// the server trims the script body and fills in defaults
+	script["script"] = strings.TrimRight(body["script"].(string), "\n") + "\n"
+	if script["dockerImage"] == nil && script["type"] == "python" {
+		script["dockerImage"] = "demisto/python3:3.10.4.29342"
+	}
+	if script["runAs"] == nil {
+		script["runAs"] = "DBotWeakRole"
+	}
+	req.tenant.store("scripts").put(id, script)
+	return http.StatusOK, script
+}
+
+func mockSearchScripts(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	query, _ := req.body["query"].(string)
+	scripts := []interface{}{}
+	for _, item := range req.tenant.store("scripts").list() {
+		if mockQueryMatch(query, item.(map[string]interface{})) {
+			scripts = append(scripts, item)
+		}
+	}
+	return http.StatusOK, map[string]interface{}{"scripts": scripts, "total": len(scripts)}
+}
+
+func mockDeleteScript(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	body, _ := req.body["script"].(map[string]interface{})
+	id, _ := body["id"].(string)
+	if !req.tenant.store("scripts").remove(id) {
+		return http.StatusNotFound, map[string]interface{}{"error": "script not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// incident types, whose id is derived from the name
+
+func mockListIncidentTypes(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	return http.StatusOK, req.tenant.store("incidenttypes").list()
+}
+
+func mockSaveIncidentType(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	name, _ := req.body["name"].(string)
+	if name == "" {
+		return http.StatusBadRequest, map[string]interface{}{"error": "incident type has no name"}
+	}
+	id, _ := req.body["id"].(string)
+	previous := req.tenant.store("incidenttypes").get(id)
+	if id == "" {
+		id = name
+		if req.tenant.store("incidenttypes").get(id) != nil {
+			return http.StatusBadRequest, map[string]interface{}{"error": "incident type " + name + " already exists"}
+		}
+	} else if previous == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "incident type not found"}
+	}
+	incidentType := map[string]interface{}{
+		"color":    "#32d296",
+		"sla":      float64(0),
+		"disabled": false,
+		"autorun":  false,
+	}
+	for key, value := range previous {
+		incidentType[key] = value
+	}
+	for key, value := range req.body {
+		incidentType[key] = value
+	}
+	incidentType["id"] = id
+	req.tenant.store("incidenttypes").put(id, incidentType)
+	return http.StatusOK, incidentType
+}
+
+func mockDeleteIncidentType(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	id, _ := req.body["id"].(string)
+	if !req.tenant.store("incidenttypes").remove(id) {
+		return http.StatusNotFound, map[string]interface{}{"error": "incident type not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// incident and indicator fields, whose id is derived from the group and cliName
+
+func mockListFields(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	return http.StatusOK, req.tenant.store("fields").list()
+}
+
+func mockSaveField(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	cliName, _ := req.body["cliName"].(string)
+	if cliName == "" {
+		return http.StatusBadRequest, map[string]interface{}{"error": "field has no cliName"}
+	}
+	id, _ := req.body["id"].(string)
+	previous := req.tenant.store("fields").get(id)
+	if id == "" {
+		prefix := "incident_"
+		if group, _ := req.body["group"].(float64); group == 2 {
+			prefix = "indicator_"
+		}
+		id = prefix + cliName
+		if req.tenant.store("fields").get(id) != nil {
+			return http.StatusBadRequest, map[string]interface{}{"error": "field " + cliName + " already exists"}
+		}
+	} else if previous == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "field not found"}
+	}
+	field := map[string]interface{}{
+		"associatedTypes":       []interface{}{},
+		"systemAssociatedTypes": []interface{}{},
+		"associatedToAll":       false,
+		"unsearchable":          false,
+		"required":              false,
+		"closeForm":             false,
+	}
+	for key, value := range previous {
+		field[key] = value
+	}
+	for key, value := range req.body {
+		field[key] = value
+	}
+	field["id"] = id
+	req.tenant.store("fields").put(id, field)
+	return http.StatusOK, field
+}
+
+func mockDeleteField(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if !req.tenant.store("fields").remove(req.params[0]) {
+		return http.StatusNotFound, map[string]interface{}{"error": "field not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// layouts containers, which are replaced as a whole on every save
+
+func mockListLayouts(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	return http.StatusOK, req.tenant.store("layouts").list()
+}
+
+func mockSaveLayout(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	id, _ := req.body["id"].(string)
+	version := float64(1)
+	if id == "" {
+		id = m.newId()
+	} else if previous := req.tenant.store("layouts").get(id); previous != nil {
+		version, _ = previous["version"].(float64)
+		version++
+	} else {
+		return http.StatusNotFound, map[string]interface{}{"error": "layout not found"}
+	}
+	layout := map[string]interface{}{
+		"propagationLabels": []interface{}{"all"},
+	}
+	for key, value := range req.body {
+		layout[key] = value
+	}
+	layout["id"] = id
+	layout["version"] = version
+	layout["modified"] = "2022-08-01T00:00:00Z"
+	req.tenant.store("layouts").put(id, layout)
+	return http.StatusOK, layout
+}
+
+func mockDeleteLayout(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if !req.tenant.store("layouts").remove(req.params[0]) {
+		return http.StatusNotFound, map[string]interface{}{"error": "layout not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// lists, whose id is the name; the data of json lists is reformatted like XSOAR does
+
+func mockListLists(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	return http.StatusOK, req.tenant.store("lists").list()
+}
+
+func mockSaveList(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	name, _ := req.body["name"].(string)
+	if name == "" {
+		return http.StatusBadRequest, map[string]interface{}{"error": "list has no name"}
+	}
+	list := map[string]interface{}{
+		"tags": []interface{}{},
+	}
+	for key, value := range req.body {
+		list[key] = value
+	}
+	if data, _ := list["data"].(string); list["type"] == "json" {
+		var object interface{}
+		if err := json.Unmarshal([]byte(data), &object); err != nil {
+			return http.StatusBadRequest, map[string]interface{}{"error": "list data is not valid json"}
+		}
+		b, _ := json.MarshalIndent(object, "", "    ")
+		list["data"] = string(b)
+	}
+	list["id"] = name
+	req.tenant.store("lists").put(name, list)
+	return http.StatusOK, list
+}
+
+func mockDeleteList(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	id, _ := req.body["id"].(string)
+	if !req.tenant.store("lists").remove(id) {
+		return http.StatusNotFound, map[string]interface{}{"error": "list not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// jobs, which are searched with a query like the other content
+
+func mockSaveJob(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	id, _ := req.body["id"].(string)
+	previous := req.tenant.store("jobs").get(id)
+	if id == "" {
+		id = m.newId()
+	} else if previous == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "job not found"}
+	}
+	job := map[string]interface{}{
+		"type":             "Unclassified",
+		"startDate":        "2022-08-01T00:00:00Z",
+		"endDate":          "0001-01-01T00:00:00Z",
+		"isFeed":           false,
+		"selectedFeeds":    []interface{}{},
+		"shouldTriggerNew": false,
+		"closePrevRun":     false,
+		"tags":             []interface{}{},
+	}
+	for key, value := range previous {
+		job[key] = value
+	}
+	for key, value := range req.body {
+		job[key] = value
+	}
+	job["id"] = id
+	req.tenant.store("jobs").put(id, job)
+	return http.StatusOK, job
+}
+
+func mockSearchJobs(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	query, _ := req.body["query"].(string)
+	jobs := []interface{}{}
+	for _, job := range req.tenant.store("jobs").list() {
+		if mockQueryMatch(query, job.(map[string]interface{})) {
+			jobs = append(jobs, job)
+		}
+	}
+	return http.StatusOK, map[string]interface{}{"data": jobs, "total": len(jobs)}
+}
+
+func mockDeleteJob(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if !req.tenant.store("jobs").remove(req.params[0]) {
+		return http.StatusNotFound, map[string]interface{}{"error": "job not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// pre-process rules, which XSOAR applies in the order of their index
+
+func mockListPreprocessRules(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	return http.StatusOK, req.tenant.store("preprocessrules").list()
+}
+
+func mockSavePreprocessRule(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	rules := req.tenant.store("preprocessrules")
+	id, _ := req.body["id"].(string)
+	previous := rules.get(id)
+	rule := map[string]interface{}{}
+	if id == "" {
+		id = m.newId()
+		// new rules are added last
+		rule["index"] = float64(len(rules.list()))
+	} else if previous == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "rule not found"}
+	} else {
+		rule["index"] = previous["index"]
+	}
+	for key, value := range req.body {
+		rule[key] = value
+	}
+	rule["id"] = id
+	rules.put(id, rule)
+	return http.StatusOK, rule
+}
+
+func mockOrderPreprocessRules(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	rules := req.tenant.store("preprocessrules")
+	order := mockStrings(req.body["rulesOrder"])
+	if len(order) != len(rules.list()) {
+		return http.StatusBadRequest, map[string]interface{}{"error": "the order must hold every rule"}
+	}
+	for i, id := range order {
+		rule := rules.get(id.(string))
+		if rule == nil {
+			return http.StatusBadRequest, map[string]interface{}{"error": "rule not found"}
+		}
+		rule["index"] = float64(i)
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+func mockDeletePreprocessRule(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if !req.tenant.store("preprocessrules").remove(req.params[0]) {
+		return http.StatusNotFound, map[string]interface{}{"error": "rule not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// roles, whose id is the name; XSOAR lists every permission of a role
+
+var mockPermissions = []string{"incidents", "indicators", "playbooks", "scripts", "settings"}
+
+func mockListRoles(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	return http.StatusOK, req.tenant.store("roles").list()
+}
+
+func mockSaveRole(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	name, _ := req.body["name"].(string)
+	if name == "" {
+		return http.StatusBadRequest, map[string]interface{}{"error": "role has no name"}
+	}
+	id, _ := req.body["id"].(string)
+	if id == "" {
+		id = name
+		if req.tenant.store("roles").get(id) != nil {
+			return http.StatusBadRequest, map[string]interface{}{"error": "role " + name + " already exists"}
+		}
+	} else if req.tenant.store("roles").get(id) == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "role not found"}
+	}
+	role := map[string]interface{}{
+		"pageAccess":        []interface{}{},
+		"propagationLabels": []interface{}{"all"},
+	}
+	for key, value := range req.body {
+		role[key] = value
+	}
+	granted := map[string]interface{}{}
+	for _, item := range mockSlice(mockObject(req.body["permissions"])["demisto"]) {
+		permission := mockObject(item)
+		granted[permission["id"].(string)] = permission
+	}
+	permissions := []interface{}{}
+	for _, permissionId := range mockPermissions {
+		permission, ok := granted[permissionId]
+		if !ok {
+			permission = map[string]interface{}{"id": permissionId, "read": false, "edit": false}
+		}
+		permissions = append(permissions, permission)
+	}
+	role["permissions"] = map[string]interface{}{"demisto": permissions}
+	role["id"] = id
+	req.tenant.store("roles").put(id, role)
+	return http.StatusOK, role
+}
+
+func mockDeleteRole(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if !req.tenant.store("roles").remove(req.params[0]) {
+		return http.StatusNotFound, map[string]interface{}{"error": "role not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// users, whose id is the username; passwords are never returned
+
+func mockListUsers(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	users := []interface{}{}
+	for _, item := range req.tenant.store("users").list() {
+		user := map[string]interface{}{}
+		for key, value := range item.(map[string]interface{}) {
+			if key != "password" {
+				user[key] = value
+			}
+		}
+		users = append(users, user)
+	}
+	return http.StatusOK, users
+}
+
+func mockSaveUser(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	username, _ := req.body["username"].(string)
+	if username == "" {
+		return http.StatusBadRequest, map[string]interface{}{"error": "user has no username"}
+	}
+	id, _ := req.body["id"].(string)
+	previous := req.tenant.store("users").get(id)
+	if id == "" {
+		id = username
+		if req.tenant.store("users").get(id) != nil {
+			return http.StatusBadRequest, map[string]interface{}{"error": "user " + username + " already exists"}
+		}
+	} else if previous == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "user not found"}
+	}
+	user := map[string]interface{}{
+		"defaultAdmin": false,
+	}
+	for key, value := range previous {
+		user[key] = value
+	}
+	for key, value := range req.body {
+		user[key] = value
+	}
+	user["id"] = id
+	req.tenant.store("users").put(id, user)
+	response := map[string]interface{}{}
+	for key, value := range user {
+		if key != "password" {
+			response[key] = value
+		}
+	}
+	return http.StatusOK, response
+}
+
+func mockDeleteUsers(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	for _, id := range mockStrings(req.body["ids"]) {
+		if !req.tenant.store("users").remove(id.(string)) {
+			return http.StatusNotFound, map[string]interface{}{"error": "user not found"}
+		}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// API keys, which authenticate requests like the key of the mock; the key itself is never returned
+
+func mockListAPIKeys(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	keys := []interface{}{}
+	for _, item := range req.tenant.store("apikeys").list() {
+		key := map[string]interface{}{}
+		for k, v := range item.(map[string]interface{}) {
+			if k != "apikey" {
+				key[k] = v
+			}
+		}
+		keys = append(keys, key)
+	}
+	return http.StatusOK, keys
+}
+
+func mockCreateAPIKey(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if req.acc != "" {
+		return http.StatusNotFound, map[string]interface{}{"error": "api keys are only managed in the main tenant"}
+	}
+	key, _ := req.body["apikey"].(string)
+	if len(key) == 0 || req.tenant.store("apikeys").find("apikey", key) != nil {
+		return http.StatusBadRequest, map[string]interface{}{"error": "invalid api key"}
+	}
+	id := m.newId()
+	apiKey := map[string]interface{}{"id": id, "name": req.body["name"], "apikey": key}
+	if user, ok := req.body["user"]; ok {
+		if req.tenant.store("users").get(user.(string)) == nil {
+			return http.StatusBadRequest, map[string]interface{}{"error": "user not found"}
+		}
+		apiKey["user"] = user
+	}
+	req.tenant.store("apikeys").put(id, apiKey)
+	return http.StatusOK, map[string]interface{}{"id": id, "name": apiKey["name"]}
+}
+
+func mockRevokeAPIKey(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if !req.tenant.store("apikeys").remove(req.params[0]) {
+		return http.StatusNotFound, map[string]interface{}{"error": "api key not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// credentials, which XSOAR identifies by name; the password and certificate are never returned
+
+func mockCredentialResponse(credential map[string]interface{}) map[string]interface{} {
+	response := map[string]interface{}{}
+	for key, value := range credential {
+		if key != "password" && key != "certificate" {
+			response[key] = value
+		}
+	}
+	return response
+}
+
+func mockSearchCredentials(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	credentials := []interface{}{}
+	for _, item := range req.tenant.store("credentials").list() {
+		credentials = append(credentials, mockCredentialResponse(item.(map[string]interface{})))
+	}
+	return http.StatusOK, map[string]interface{}{"credentials": credentials, "total": len(credentials)}
+}
+
+func mockSaveCredential(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	name, _ := req.body["name"].(string)
+	if name == "" {
+		return http.StatusBadRequest, map[string]interface{}{"error": "missing credential name"}
+	}
+	id, _ := req.body["id"].(string)
+	if id == "" {
+		id = name
+	} else if req.tenant.store("credentials").get(id) == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "credential not found"}
+	}
+	credential := map[string]interface{}{}
+	for key, value := range req.body {
+		if key != "version" {
+			credential[key] = value
+		}
+	}
+	credential["id"] = id
+	req.tenant.store("credentials").put(id, credential)
+	return http.StatusOK, mockCredentialResponse(credential)
+}
+
+func mockDeleteCredentials(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	for _, id := range mockStrings(req.body["ids"]) {
+		if !req.tenant.store("credentials").remove(id.(string)) {
+			return http.StatusNotFound, map[string]interface{}{"error": "credential not found"}
+		}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// engines, which connect once the installer downloaded for them runs on the mock ssh server
+
+func mockListEngines(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	engines := req.tenant.store("engines").list()
+	return http.StatusOK, map[string]interface{}{"engines": engines, "total": len(engines)}
+}
+
+func mockCreateEngine(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if req.acc != "" {
+		return http.StatusNotFound, map[string]interface{}{"error": "engines are only managed in the main tenant"}
+	}
+	name, _ := req.body["name"].(string)
+	if name == "" || req.tenant.store("engines").find("name", name) != nil {
+		return http.StatusBadRequest, map[string]interface{}{"error": "invalid engine name"}
+	}
+	id := m.newId()
+	engine := map[string]interface{}{
+		"id":        id,
+		"name":      name,
+		"type":      req.body["type"],
+		"connected": false,
+	}
+	req.tenant.store("engines").put(id, engine)
+	return http.StatusOK, engine
+}
+
+func mockDownloadEngineInstaller(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if req.tenant.store("engines").get(req.params[0]) == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "engine not found"}
+	}
+	m.engineInstaller = req.params[0]
+	return http.StatusOK, []byte("#!/bin/sh\necho mock engine installer\n")
+}
+
+func mockDeleteEngines(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	for _, id := range mockStrings(req.body["ids"]) {
+		if !req.tenant.store("engines").remove(id.(string)) {
+			return http.StatusNotFound, map[string]interface{}{"error": "engine not found"}
+		}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// connectEngine marks the engine whose installer was last downloaded as connected
+func (m *mockXSOAR) connectEngine() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if engine := m.tenants[""].store("engines").get(m.engineInstaller); engine != nil {
+		engine["connected"] = true
+	}
+}
+
+func mockListEngineGroups(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	return http.StatusOK, req.tenant.store("engineGroups").list()
+}
+
+func mockSaveEngineGroup(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	id, _ := req.body["id"].(string)
+	if id == "" {
+		id = m.newId()
+	} else if req.tenant.store("engineGroups").get(id) == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "engine group not found"}
+	}
+	for _, engineId := range mockStrings(req.body["engineIds"]) {
+		if req.tenant.store("engines").get(engineId.(string)) == nil {
+			return http.StatusBadRequest, map[string]interface{}{"error": "unknown engine " + engineId.(string)}
+		}
+	}
+	group := map[string]interface{}{
+		"id":        id,
+		"name":      req.body["name"],
+		"engineIds": mockStrings(req.body["engineIds"]),
+	}
+	req.tenant.store("engineGroups").put(id, group)
+	return http.StatusOK, group
+}
+
+func mockDeleteEngineGroup(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	if !req.tenant.store("engineGroups").remove(req.params[0]) {
+		return http.StatusNotFound, map[string]interface{}{"error": "engine group not found"}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+// content packs, installed per tenant from the marketplace of the main tenant or from an uploaded zip
+
+func mockGetMarketplacePack(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	pack := m.tenants[""].store("marketplace").get(req.params[0])
+	if pack == nil {
+		return http.StatusNotFound, map[string]interface{}{"error": "pack not found in marketplace"}
+	}
+	return http.StatusOK, pack
+}
+
+func mockInstallPacks(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	for _, item := range mockStrings(req.body["packs"]) {
+		pack := item.(map[string]interface{})
+		id, _ := pack["id"].(string)
+		if m.tenants[""].store("marketplace").get(id) == nil {
+			return http.StatusNotFound, map[string]interface{}{"error": "pack not found in marketplace: " + id}
+		}
+		req.tenant.store("packs").put(id, map[string]interface{}{
+			"id":             id,
+			"currentVersion": pack["version"],
+		})
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
+
+func mockListInstalledPacks(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	return http.StatusOK, req.tenant.store("packs").list()
+}
+
+func mockUploadPack(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	content, err := req.file("file")
+	if err != nil {
+		return http.StatusBadRequest, map[string]interface{}{"error": err.Error()}
+	}
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return http.StatusBadRequest, map[string]interface{}{"error": "invalid pack zip: " + err.Error()}
+	}
+	for _, f := range archive.File {
+		if path.Base(f.Name) != "metadata.json" {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return http.StatusBadRequest, map[string]interface{}{"error": err.Error()}
+		}
+		var metadata map[string]interface{}
+		err = json.NewDecoder(r).Decode(&metadata)
+		r.Close()
+		if err != nil {
+			return http.StatusBadRequest, map[string]interface{}{"error": "invalid pack metadata: " + err.Error()}
+		}
+		id := path.Dir(f.Name)
+		req.tenant.store("packs").put(id, map[string]interface{}{
+			"id":             id,
+			"currentVersion": metadata["currentVersion"],
+		})
+		return http.StatusOK, map[string]interface{}{}
+	}
+	return http.StatusBadRequest, map[string]interface{}{"error": "pack zip has no metadata.json"}
+}
+
+func mockDeletePacks(m *mockXSOAR, req *mockRequest) (int, interface{}) {
+	for _, id := range mockStrings(req.body["IDs"]) {
+		if !req.tenant.store("packs").remove(id.(string)) {
+			return http.StatusNotFound, map[string]interface{}{"error": "pack not installed"}
+		}
+	}
+	return http.StatusOK, map[string]interface{}{}
+}
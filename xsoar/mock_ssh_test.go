@@ -0,0 +1,585 @@
+package xsoar
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// mockSSHServer stands in for a machine xsoar_host installs on. Commands are not executed, running
+// the installer registers the host with the fake XSOAR server instead.
+type mockSSHServer struct {
+	t        *testing.T
+	mock     *mockXSOAR
+	listener net.Listener
+	config   *ssh.ServerConfig
+	hostKey  ssh.Signer
+	// PEM encoded private key accepted for authentication
+	clientKey string
+
+	mu       sync.Mutex
+	commands []string
+	// commands matching hang do not return until they are interrupted
+	hang        *regexp.Regexp
+	interrupted []string
+	// addresses tunneled to, as a bastion
+	forwarded []string
+	// userCA signs certificates accepted for authentication
+	userCA ssh.Signer
+	// files written on the machine, by scp or a download of the installer
+	files map[string][]byte
+	// corruptUploads flips a byte of the files received by scp
+	corruptUploads bool
+	// locks are the install locks on the NFS volume of the machine
+	locks map[string]mockLock
+	// stdin holds what the commands reading stdin were given, by command
+	stdin map[string]string
+	// tempDirs counts the directories made by mktemp
+	tempDirs int
+	// agentKeys holds the keys the forwarded agent listed to the commands run with agent forwarding, by command
+	agentKeys map[string][]string
+}
+
+// mockLock is an install lock directory, with the content of its owner file if it has one
+type mockLock struct {
+	owner    string
+	modified time.Time
+}
+
+var (
+	mockDownloadRegexp        = regexp.MustCompile(`/host/download(/[^\s']+)?`)
+	mockExternalAddressRegexp = regexp.MustCompile(`-external-address='([^']*)'`)
+	mockElasticsearchRegexp   = regexp.MustCompile(`-elasticsearch-url='([^']*)'`)
+	mockCurlOutputRegexp      = regexp.MustCompile(`curl -s -o '([^']+)' `)
+	mockInstallRegexp         = regexp.MustCompile(`^sudo install -o root -g root -m 0700 '([^']+)' '([^']+)'$`)
+	mockEngineInstallRegexp   = regexp.MustCompile(`/d1_installer\.sh' -- -y|dpkg -i '[^']+/d1_installer|rpm -i '[^']+/d1_installer`)
+	mockLockAcquireRegexp     = regexp.MustCompile(`^sudo mkdir '([^']+)' && printf '[^']*' '([^']*)' '([^']*)' "\$\(date \+%s\)" \| sudo tee `)
+	mockLockInspectRegexp     = regexp.MustCompile(`^date \+%s; if sudo test -e '([^']+)'; then`)
+	mockLockTakeOverRegexp    = regexp.MustCompile(`^sudo mv -T '([^']+)' '[^']+' \|\| exit 1; if (?:sudo grep -qxF 'token=([^']*)'|! sudo grep -qs '\^token=') '[^']+'; then `)
+	mockLockReleaseRegexp     = regexp.MustCompile(`^if sudo grep -qxF 'token=([^']*)' '[^']+'; then sudo rm -rf '([^']+)'; fi$`)
+)
+
+func newMockSSHServer(t *testing.T, m *mockXSOAR) *mockSSHServer {
+	_, hostPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostKey, err := ssh.NewSignerFromKey(hostPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientPrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(clientPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientPublicKey, err := ssh.NewPublicKey(&clientPrivateKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &mockSSHServer{
+		t:         t,
+		mock:      m,
+		hostKey:   hostKey,
+		clientKey: string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})),
+		files:     map[string][]byte{},
+		locks:     map[string]mockLock{},
+		stdin:     map[string]string{},
+		agentKeys: map[string][]string{},
+	}
+	checker := &ssh.CertChecker{
+		IsUserAuthority: func(authority ssh.PublicKey) bool {
+			return s.userCA != nil && string(authority.Marshal()) == string(s.userCA.PublicKey().Marshal())
+		},
+		UserKeyFallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == string(clientPublicKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown public key")
+		},
+	}
+	s.config = &ssh.ServerConfig{
+		PublicKeyCallback: checker.Authenticate,
+	}
+	s.config.AddHostKey(hostKey)
+
+	s.listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.listener.Close() })
+	go s.serve()
+	return s
+}
+
+// Addr is the host:port to use as server_url
+func (s *mockSSHServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// HostKey is the public key of the server in authorized_keys format, as host_key takes it
+func (s *mockSSHServer) HostKey() string {
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(s.hostKey.PublicKey())))
+}
+
+// rotateHostKey has the server offer a new host key, as a reinstalled or impersonated machine would
+func (s *mockSSHServer) rotateHostKey() {
+	_, hostPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	s.hostKey, err = ssh.NewSignerFromKey(hostPrivateKey)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	s.config.AddHostKey(s.hostKey)
+}
+
+// trustUserCA has the server accept the keys certified by a new user CA, and returns a key and certificate it
+// signed for the user
+func (s *mockSSHServer) trustUserCA(user string) (string, string) {
+	_, caPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	s.userCA, err = ssh.NewSignerFromKey(caPrivateKey)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	publicKey, err := ssh.NewPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	certificate := &ssh.Certificate{
+		Key:             publicKey,
+		CertType:        ssh.UserCert,
+		KeyId:           user,
+		ValidPrincipals: []string{user},
+		ValidBefore:     ssh.CertTimeInfinity,
+	}
+	if err = certificate.SignCert(rand.Reader, s.userCA); err != nil {
+		s.t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})), string(ssh.MarshalAuthorizedKey(certificate))
+}
+
+// File returns the content of a file on the machine, or nil if there is none
+func (s *mockSSHServer) File(name string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.files[name]
+}
+
+// Files returns the names of the files on the machine
+func (s *mockSSHServer) Files() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	for name := range s.files {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Forwarded returns the addresses tunneled to so far
+func (s *mockSSHServer) Forwarded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.forwarded...)
+}
+
+// holdLock has an install lock on the NFS volume of the machine held, by a previous version of the provider when
+// token is empty
+func (s *mockSSHServer) holdLock(dir string, owner string, token string, acquired time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lock := mockLock{modified: acquired}
+	if len(token) > 0 {
+		lock.owner = fmt.Sprintf("owner=%s\ntoken=%s\nacquired=%d\n", owner, token, acquired.Unix())
+	}
+	s.locks[dir] = lock
+}
+
+// Locked returns the owner file of the install lock dir, and whether the lock is held
+func (s *mockSSHServer) Locked(dir string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lock, ok := s.locks[dir]
+	return lock.owner, ok
+}
+
+// Commands returns the commands run so far
+func (s *mockSSHServer) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.commands...)
+}
+
+// Stdin returns what a command was given on stdin
+func (s *mockSSHServer) Stdin(command string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stdin[command]
+}
+
+// AgentKeys returns the keys the forwarded agent listed to a command, and whether the agent was forwarded to it
+func (s *mockSSHServer) AgentKeys(command string) ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys, ok := s.agentKeys[command]
+	return keys, ok
+}
+
+// Interrupted returns the hanging commands that were interrupted so far
+func (s *mockSSHServer) Interrupted() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.interrupted...)
+}
+
+func (s *mockSSHServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleConn(conn)
+	}
+}
+
+func (s *mockSSHServer) handleConn(conn net.Conn) {
+	serverConn, channels, requests, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	defer serverConn.Close()
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() == "direct-tcpip" {
+			go s.forward(newChannel)
+			continue
+		}
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go s.handleSession(serverConn, channel, channelRequests)
+	}
+}
+
+func (s *mockSSHServer) handleSession(conn *ssh.ServerConn, channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer channel.Close()
+	forwardAgent := false
+	for req := range requests {
+		if req.Type == "auth-agent-req@openssh.com" {
+			forwardAgent = true
+			_ = req.Reply(true, nil)
+			continue
+		}
+		if req.Type != "exec" {
+			if req.WantReply {
+				_ = req.Reply(false, nil)
+			}
+			continue
+		}
+		var payload struct{ Command string }
+		if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		_ = req.Reply(true, nil)
+		if forwardAgent {
+			s.listAgentKeys(conn, payload.Command)
+		}
+		if s.hang != nil && s.hang.MatchString(payload.Command) {
+			s.wait(payload.Command, requests)
+			return
+		}
+		var output string
+		var status uint32
+		if strings.HasPrefix(payload.Command, "scp -qt ") {
+			status = s.receive(channel, payload.Command)
+		} else {
+			var stdin []byte
+			// curl reads headers from stdin with -H @-, and secrets are read into variables
+			if strings.Contains(payload.Command, " -H @- ") || strings.HasPrefix(payload.Command, "IFS= read -r ") {
+				stdin, _ = io.ReadAll(channel)
+				s.mu.Lock()
+				s.stdin[payload.Command] = string(stdin)
+				s.mu.Unlock()
+			}
+			output, status = s.run(payload.Command, string(stdin))
+		}
+		_, _ = channel.Write([]byte(output))
+		_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+		return
+	}
+}
+
+// listAgentKeys lists the keys of the agent the client forwards, as a command using it to authenticate would
+func (s *mockSSHServer) listAgentKeys(conn *ssh.ServerConn, command string) {
+	channel, requests, err := conn.OpenChannel("auth-agent@openssh.com", nil)
+	if err != nil {
+		s.t.Errorf("could not open a channel to the forwarded agent: %s", err)
+		return
+	}
+	defer channel.Close()
+	go ssh.DiscardRequests(requests)
+	keys, err := agent.NewClient(channel).List()
+	if err != nil {
+		s.t.Errorf("could not list the keys of the forwarded agent: %s", err)
+		return
+	}
+	listed := []string{}
+	for _, key := range keys {
+		listed = append(listed, string(ssh.MarshalAuthorizedKey(key)))
+	}
+	s.mu.Lock()
+	s.agentKeys[command] = listed
+	s.mu.Unlock()
+}
+
+// forward tunnels a channel to the address it was opened for, as a bastion does
+func (s *mockSSHServer) forward(newChannel ssh.NewChannel) {
+	var payload struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &payload); err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	address := net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port)))
+	target, err := net.Dial("tcp", address)
+	if err != nil {
+		_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	channel, requests, err := newChannel.Accept()
+	if err != nil {
+		_ = target.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	s.mu.Lock()
+	s.forwarded = append(s.forwarded, address)
+	s.mu.Unlock()
+	go func() {
+		_, _ = io.Copy(target, channel)
+		_ = target.Close()
+	}()
+	_, _ = io.Copy(channel, target)
+	_ = channel.Close()
+}
+
+// wait blocks a hanging command until the client signals it or closes the session
+func (s *mockSSHServer) wait(command string, requests <-chan *ssh.Request) {
+	for req := range requests {
+		if req.Type == "signal" {
+			break
+		}
+	}
+	s.mu.Lock()
+	s.interrupted = append(s.interrupted, command)
+	s.mu.Unlock()
+}
+
+// receive writes the file a client sends with the protocol of scp
+func (s *mockSSHServer) receive(channel ssh.Channel, command string) uint32 {
+	s.mu.Lock()
+	s.commands = append(s.commands, command)
+	s.mu.Unlock()
+
+	name := strings.Trim(strings.TrimPrefix(command, "scp -qt "), "'")
+	reader := bufio.NewReader(channel)
+	_, _ = channel.Write([]byte{0})
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return 1
+	}
+	var mode string
+	var size int
+	if _, err = fmt.Sscanf(header, "C%s %d", &mode, &size); err != nil {
+		_, _ = channel.Write([]byte("\x01scp: protocol error\n"))
+		return 1
+	}
+	_, _ = channel.Write([]byte{0})
+	content := make([]byte, size)
+	if _, err = io.ReadFull(reader, content); err != nil {
+		return 1
+	}
+	if end, err := reader.ReadByte(); err != nil || end != 0 {
+		return 1
+	}
+	if s.corruptUploads && size > 0 {
+		content[0] ^= 0xff
+	}
+	s.mu.Lock()
+	s.files[name] = content
+	s.mu.Unlock()
+	_, _ = channel.Write([]byte{0})
+	return 0
+}
+
+// run simulates the remote side of a command, given what it read from stdin
+func (s *mockSSHServer) run(command string, stdin string) (string, uint32) {
+	s.mu.Lock()
+	s.commands = append(s.commands, command)
+	s.mu.Unlock()
+
+	if output, status, ok := s.lock(command); ok {
+		return output, status
+	}
+	if mockEngineInstallRegexp.MatchString(command) {
+		s.mock.connectEngine()
+		return "", 0
+	}
+	if match := mockDownloadRegexp.FindStringSubmatch(command); match != nil {
+		if !strings.Contains(command, "Authorization: "+mockAPIKey) && !strings.Contains(stdin, "Authorization: "+mockAPIKey+"\n") {
+			return "", 22
+		}
+		s.mock.mu.Lock()
+		s.mock.installerGroup = strings.TrimPrefix(match[1], "/")
+		s.mock.mu.Unlock()
+		if output := mockCurlOutputRegexp.FindStringSubmatch(command); output != nil {
+			s.mu.Lock()
+			s.files[output[1]] = mockInstaller
+			s.mu.Unlock()
+		}
+		return "", 0
+	}
+	if command == "mktemp -d" || command == "sudo mktemp -d" {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.tempDirs++
+		owner := "user"
+		if strings.HasPrefix(command, "sudo ") {
+			owner = "root"
+		}
+		return fmt.Sprintf("/tmp/tmp.%s%d\n", owner, s.tempDirs), 0
+	}
+	if match := mockInstallRegexp.FindStringSubmatch(command); match != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		content, ok := s.files[match[1]]
+		if !ok {
+			return "", 1
+		}
+		s.files[match[2]] = content
+		return "", 0
+	}
+	if strings.HasPrefix(command, "sudo sha256sum ") {
+		name := strings.Trim(strings.TrimPrefix(command, "sudo sha256sum "), "'")
+		content := s.File(name)
+		if content == nil {
+			return "", 1
+		}
+		return fmt.Sprintf("%x  %s\n", sha256.Sum256(content), name), 0
+	}
+	if strings.HasPrefix(command, "sudo rm -rf ") {
+		s.mu.Lock()
+		for _, dir := range strings.Fields(strings.TrimPrefix(command, "sudo rm -rf ")) {
+			dir = strings.Trim(dir, "'")
+			for name := range s.files {
+				if strings.HasPrefix(name, dir+"/") {
+					delete(s.files, name)
+				}
+			}
+		}
+		s.mu.Unlock()
+		return "", 0
+	}
+	if strings.Contains(command, "installer.sh") && !strings.Contains(command, "-purge") {
+		if match := mockExternalAddressRegexp.FindStringSubmatch(command); match != nil {
+			var elasticsearchUrl string
+			if es := mockElasticsearchRegexp.FindStringSubmatch(command); es != nil {
+				elasticsearchUrl = es[1]
+			}
+			s.mock.installHost(match[1], elasticsearchUrl)
+		}
+	}
+	return "", 0
+}
+
+// lock simulates the commands on the install locks of the machine, returning false for other commands
+func (s *mockSSHServer) lock(command string) (string, uint32, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if match := mockLockAcquireRegexp.FindStringSubmatch(command); match != nil {
+		if _, held := s.locks[match[1]]; held {
+			return "", 1, true
+		}
+		s.locks[match[1]] = mockLock{
+			owner:    fmt.Sprintf("owner=%s\ntoken=%s\nacquired=%d\n", match[2], match[3], now.Unix()),
+			modified: now,
+		}
+		return "", 0, true
+	}
+	if match := mockLockInspectRegexp.FindStringSubmatch(command); match != nil {
+		output := fmt.Sprintf("%d\n", now.Unix())
+		if lock, held := s.locks[match[1]]; held && len(lock.owner) > 0 {
+			output += lock.owner
+		} else if held {
+			output += fmt.Sprintf("acquired=%d\n", lock.modified.Unix())
+		}
+		return output, 0, true
+	}
+	if match := mockLockTakeOverRegexp.FindStringSubmatch(command); match != nil {
+		// the lock is moved aside, and moved back when its owner is not the one expected
+		lock, held := s.locks[match[1]]
+		if len(match[2]) == 0 {
+			held = held && !strings.Contains(lock.owner, "token=")
+		} else {
+			held = held && strings.Contains(lock.owner, "token="+match[2]+"\n")
+		}
+		if !held {
+			return "", 1, true
+		}
+		delete(s.locks, match[1])
+		return "", 0, true
+	}
+	if match := mockLockReleaseRegexp.FindStringSubmatch(command); match != nil {
+		if lock, held := s.locks[match[2]]; held && strings.Contains(lock.owner, "token="+match[1]+"\n") {
+			delete(s.locks, match[2])
+		}
+		return "", 0, true
+	}
+	return "", 0, false
+}
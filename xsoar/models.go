@@ -14,6 +14,19 @@ type Account struct {
 	PropagationLabels types.Set    `tfsdk:"propagation_labels"`
 	Timeout           types.Int64  `tfsdk:"timeout"`
 	Concurrency       types.Int64  `tfsdk:"concurrency_limit"`
+	Timeouts          []Timeouts   `tfsdk:"timeouts"`
+}
+
+// AccountDataSource -
+type AccountDataSource struct {
+	Name              types.String `tfsdk:"name"`
+	Id                types.String `tfsdk:"id"`
+	HostGroupName     types.String `tfsdk:"host_group_name"`
+	HostGroupId       types.String `tfsdk:"host_group_id"`
+	AccountRoles      types.Set    `tfsdk:"account_roles"`
+	PropagationLabels types.Set    `tfsdk:"propagation_labels"`
+	Timeout           types.Int64  `tfsdk:"timeout"`
+	Concurrency       types.Int64  `tfsdk:"concurrency_limit"`
 }
 
 // Accounts -
@@ -29,6 +42,17 @@ type HAGroup struct {
 	ElasticIndexPrefix types.String `tfsdk:"elastic_index_prefix"`
 	AccountIds         types.Set    `tfsdk:"account_ids"`
 	HostIds            types.Set    `tfsdk:"host_ids"`
+	Timeouts           []Timeouts   `tfsdk:"timeouts"`
+}
+
+// HAGroupDataSource -
+type HAGroupDataSource struct {
+	Name               types.String `tfsdk:"name"`
+	Id                 types.String `tfsdk:"id"`
+	ElasticsearchUrl   types.String `tfsdk:"elasticsearch_url"`
+	ElasticIndexPrefix types.String `tfsdk:"elastic_index_prefix"`
+	AccountIds         types.Set    `tfsdk:"account_ids"`
+	HostIds            types.Set    `tfsdk:"host_ids"`
 }
 
 // HAGroups -
@@ -40,32 +64,142 @@ type HAGroups struct {
 
 // Host -
 type Host struct {
-	Name                types.String `tfsdk:"name"`
-	Id                  types.String `tfsdk:"id"`
-	HAGroupName         types.String `tfsdk:"ha_group_name"`
-	NFSMount            types.String `tfsdk:"nfs_mount"`
-	ElasticsearchUrl    types.String `tfsdk:"elasticsearch_url"`
-	ServerUrl           types.String `tfsdk:"server_url"`
-	SSHUser             types.String `tfsdk:"ssh_user"`
-	SSHKey              types.String `tfsdk:"ssh_key"`
-	InstallationTimeout types.Int64  `tfsdk:"installation_timeout"`
-	ExtraFlags          types.List   `tfsdk:"extra_flags"`
+	Name                  types.String `tfsdk:"name"`
+	Id                    types.String `tfsdk:"id"`
+	HAGroupName           types.String `tfsdk:"ha_group_name"`
+	NFSMount              types.String `tfsdk:"nfs_mount"`
+	NFSLockTTL            types.String `tfsdk:"nfs_lock_ttl"`
+	ElasticsearchUrl      types.String `tfsdk:"elasticsearch_url"`
+	ServerUrl             types.String `tfsdk:"server_url"`
+	SSHUser               types.String `tfsdk:"ssh_user"`
+	SSHKey                types.String `tfsdk:"ssh_key"`
+	SSHKeyPassphrase      types.String `tfsdk:"ssh_key_passphrase"`
+	SSHCertificate        types.String `tfsdk:"ssh_certificate"`
+	SSHAgent              types.Bool   `tfsdk:"ssh_agent"`
+	SSHAgentForwarding    types.Bool   `tfsdk:"ssh_agent_forwarding"`
+	Bastion               []Bastion    `tfsdk:"bastion"`
+	HostKey               types.String `tfsdk:"host_key"`
+	HostKeyFingerprint    types.String `tfsdk:"host_key_fingerprint"`
+	KnownHostsFile        types.String `tfsdk:"known_hosts_file"`
+	HostKeyChecking       types.String `tfsdk:"host_key_checking"`
+	ObservedHostKey       types.String `tfsdk:"observed_host_key"`
+	InstallationTimeout   types.Int64  `tfsdk:"installation_timeout"`
+	ExtraFlags            types.List   `tfsdk:"extra_flags"`
+	InstallerTransfer     types.String `tfsdk:"installer_transfer"`
+	ElasticsearchUsername types.String `tfsdk:"elasticsearch_username"`
+	ElasticsearchPassword types.String `tfsdk:"elasticsearch_password"`
+	TempFolder            types.String `tfsdk:"temp_folder"`
+	Timeouts              []Timeouts   `tfsdk:"timeouts"`
+}
+
+// HostDataSource -
+type HostDataSource struct {
+	Name                  types.String `tfsdk:"name"`
+	Id                    types.String `tfsdk:"id"`
+	HAGroupName           types.String `tfsdk:"ha_group_name"`
+	NFSMount              types.String `tfsdk:"nfs_mount"`
+	NFSLockTTL            types.String `tfsdk:"nfs_lock_ttl"`
+	ElasticsearchUrl      types.String `tfsdk:"elasticsearch_url"`
+	ServerUrl             types.String `tfsdk:"server_url"`
+	SSHUser               types.String `tfsdk:"ssh_user"`
+	SSHKey                types.String `tfsdk:"ssh_key"`
+	SSHKeyPassphrase      types.String `tfsdk:"ssh_key_passphrase"`
+	SSHCertificate        types.String `tfsdk:"ssh_certificate"`
+	SSHAgent              types.Bool   `tfsdk:"ssh_agent"`
+	SSHAgentForwarding    types.Bool   `tfsdk:"ssh_agent_forwarding"`
+	Bastion               []Bastion    `tfsdk:"bastion"`
+	HostKey               types.String `tfsdk:"host_key"`
+	HostKeyFingerprint    types.String `tfsdk:"host_key_fingerprint"`
+	KnownHostsFile        types.String `tfsdk:"known_hosts_file"`
+	HostKeyChecking       types.String `tfsdk:"host_key_checking"`
+	ObservedHostKey       types.String `tfsdk:"observed_host_key"`
+	InstallationTimeout   types.Int64  `tfsdk:"installation_timeout"`
+	ExtraFlags            types.List   `tfsdk:"extra_flags"`
+	InstallerTransfer     types.String `tfsdk:"installer_transfer"`
+	ElasticsearchUsername types.String `tfsdk:"elasticsearch_username"`
+	ElasticsearchPassword types.String `tfsdk:"elasticsearch_password"`
+	TempFolder            types.String `tfsdk:"temp_folder"`
+}
+
+// Bastion - the jump host a host is reached through over ssh
+type Bastion struct {
+	Host               types.String `tfsdk:"host"`
+	User               types.String `tfsdk:"user"`
+	SSHKey             types.String `tfsdk:"ssh_key"`
+	SSHKeyPassphrase   types.String `tfsdk:"ssh_key_passphrase"`
+	SSHCertificate     types.String `tfsdk:"ssh_certificate"`
+	SSHAgent           types.Bool   `tfsdk:"ssh_agent"`
+	HostKey            types.String `tfsdk:"host_key"`
+	HostKeyFingerprint types.String `tfsdk:"host_key_fingerprint"`
+	KnownHostsFile     types.String `tfsdk:"known_hosts_file"`
+	HostKeyChecking    types.String `tfsdk:"host_key_checking"`
+	ObservedHostKey    types.String `tfsdk:"observed_host_key"`
 }
 
 // IntegrationInstance -
 type IntegrationInstance struct {
-	Name              types.String `tfsdk:"name"`
-	Id                types.String `tfsdk:"id"`
-	IntegrationName   types.String `tfsdk:"integration_name"`
-	Account           types.String `tfsdk:"account"`
-	Enabled           types.Bool   `tfsdk:"enabled"`
-	PropagationLabels types.Set    `tfsdk:"propagation_labels"`
-	ConfigJson        types.String `tfsdk:"config_json"`
-	SecretConfigJson  types.String `tfsdk:"secret_config_json"`
-	IncomingMapperId  types.String `tfsdk:"incoming_mapper_id"`
-	OutgoingMapperId  types.String `tfsdk:"outgoing_mapper_id"`
-	MappingId         types.String `tfsdk:"mapping_id"`
-	EngineId          types.String `tfsdk:"engine_id"`
+	Name                types.String `tfsdk:"name"`
+	Id                  types.String `tfsdk:"id"`
+	IntegrationName     types.String `tfsdk:"integration_name"`
+	Account             types.String `tfsdk:"account"`
+	Enabled             types.Bool   `tfsdk:"enabled"`
+	PropagationLabels   types.Set    `tfsdk:"propagation_labels"`
+	ConfigJson          types.String `tfsdk:"config_json"`
+	SecretConfigJson    types.String `tfsdk:"secret_config_json"`
+	Config              types.Map    `tfsdk:"config"`
+	SecretConfig        types.Map    `tfsdk:"secret_config"`
+	IncomingMapperId    types.String `tfsdk:"incoming_mapper_id"`
+	OutgoingMapperId    types.String `tfsdk:"outgoing_mapper_id"`
+	MappingId           types.String `tfsdk:"mapping_id"`
+	EngineId            types.String `tfsdk:"engine_id"`
+	Credentials         types.Map    `tfsdk:"credentials"`
+	TestOnApply         types.Bool   `tfsdk:"test_on_apply"`
+	TestMode            types.String `tfsdk:"test_mode"`
+	LastTestResult      types.String `tfsdk:"last_test_result"`
+	IntegrationLogLevel types.String `tfsdk:"integration_log_level"`
+	IsLongRunning       types.Bool   `tfsdk:"is_long_running"`
+	EngineGroup         types.String `tfsdk:"engine_group"`
+	ResetContext        types.Bool   `tfsdk:"reset_context"`
+	PasswordProtected   types.Bool   `tfsdk:"password_protected"`
+	Byoi                types.Bool   `tfsdk:"byoi"`
+	DefaultIgnore       types.Bool   `tfsdk:"default_ignore"`
+	IsFetch             types.Bool   `tfsdk:"is_fetch"`
+	IncidentType        types.String `tfsdk:"incident_type"`
+	FetchInterval       types.Int64  `tfsdk:"fetch_interval"`
+	MirroringDirection  types.String `tfsdk:"mirroring_direction"`
+	Timeouts            []Timeouts   `tfsdk:"timeouts"`
+}
+
+// IntegrationInstanceDataSource -
+type IntegrationInstanceDataSource struct {
+	Name                types.String `tfsdk:"name"`
+	Id                  types.String `tfsdk:"id"`
+	IntegrationName     types.String `tfsdk:"integration_name"`
+	Account             types.String `tfsdk:"account"`
+	Enabled             types.Bool   `tfsdk:"enabled"`
+	PropagationLabels   types.Set    `tfsdk:"propagation_labels"`
+	ConfigJson          types.String `tfsdk:"config_json"`
+	SecretConfigJson    types.String `tfsdk:"secret_config_json"`
+	Config              types.Map    `tfsdk:"config"`
+	IncomingMapperId    types.String `tfsdk:"incoming_mapper_id"`
+	OutgoingMapperId    types.String `tfsdk:"outgoing_mapper_id"`
+	MappingId           types.String `tfsdk:"mapping_id"`
+	EngineId            types.String `tfsdk:"engine_id"`
+	Credentials         types.Map    `tfsdk:"credentials"`
+	TestOnApply         types.Bool   `tfsdk:"test_on_apply"`
+	TestMode            types.String `tfsdk:"test_mode"`
+	LastTestResult      types.String `tfsdk:"last_test_result"`
+	IntegrationLogLevel types.String `tfsdk:"integration_log_level"`
+	IsLongRunning       types.Bool   `tfsdk:"is_long_running"`
+	EngineGroup         types.String `tfsdk:"engine_group"`
+	ResetContext        types.Bool   `tfsdk:"reset_context"`
+	PasswordProtected   types.Bool   `tfsdk:"password_protected"`
+	Byoi                types.Bool   `tfsdk:"byoi"`
+	DefaultIgnore       types.Bool   `tfsdk:"default_ignore"`
+	IsFetch             types.Bool   `tfsdk:"is_fetch"`
+	IncidentType        types.String `tfsdk:"incident_type"`
+	FetchInterval       types.Int64  `tfsdk:"fetch_interval"`
+	MirroringDirection  types.String `tfsdk:"mirroring_direction"`
 }
 
 // Classifier -
@@ -77,6 +211,18 @@ type Classifier struct {
 	Transformer         types.String `tfsdk:"transformer"`
 	PropagationLabels   types.Set    `tfsdk:"propagation_labels"`
 	Account             types.String `tfsdk:"account"`
+	Timeouts            []Timeouts   `tfsdk:"timeouts"`
+}
+
+// ClassifierDataSource -
+type ClassifierDataSource struct {
+	Name                types.String `tfsdk:"name"`
+	Id                  types.String `tfsdk:"id"`
+	DefaultIncidentType types.String `tfsdk:"default_incident_type"`
+	KeyTypeMap          types.String `tfsdk:"key_type_map"`
+	Transformer         types.String `tfsdk:"transformer"`
+	PropagationLabels   types.Set    `tfsdk:"propagation_labels"`
+	Account             types.String `tfsdk:"account"`
 }
 
 // Mapper -
@@ -87,4 +233,271 @@ type Mapper struct {
 	PropagationLabels types.Set    `tfsdk:"propagation_labels"`
 	Account           types.String `tfsdk:"account"`
 	Direction         types.String `tfsdk:"direction"`
+	Timeouts          []Timeouts   `tfsdk:"timeouts"`
+}
+
+// MapperDataSource -
+type MapperDataSource struct {
+	Name              types.String `tfsdk:"name"`
+	Id                types.String `tfsdk:"id"`
+	Mapping           types.String `tfsdk:"mapping"`
+	PropagationLabels types.Set    `tfsdk:"propagation_labels"`
+	Account           types.String `tfsdk:"account"`
+	Direction         types.String `tfsdk:"direction"`
+}
+
+// Playbook -
+type Playbook struct {
+	Name     types.String `tfsdk:"name"`
+	Id       types.String `tfsdk:"id"`
+	Content  types.String `tfsdk:"content"`
+	Path     types.String `tfsdk:"path"`
+	Account  types.String `tfsdk:"account"`
+	Timeouts []Timeouts   `tfsdk:"timeouts"`
+}
+
+// Script -
+type Script struct {
+	Name              types.String     `tfsdk:"name"`
+	Id                types.String     `tfsdk:"id"`
+	Type              types.String     `tfsdk:"type"`
+	Script            types.String     `tfsdk:"script"`
+	DockerImage       types.String     `tfsdk:"docker_image"`
+	RunAs             types.String     `tfsdk:"run_as"`
+	Tags              types.Set        `tfsdk:"tags"`
+	Args              []ScriptArgument `tfsdk:"args"`
+	Outputs           []ScriptOutput   `tfsdk:"outputs"`
+	PropagationLabels types.Set        `tfsdk:"propagation_labels"`
+	Account           types.String     `tfsdk:"account"`
+	Timeouts          []Timeouts       `tfsdk:"timeouts"`
+}
+
+// ScriptArgument -
+type ScriptArgument struct {
+	Name         types.String `tfsdk:"name"`
+	Description  types.String `tfsdk:"description"`
+	Required     types.Bool   `tfsdk:"required"`
+	Default      types.Bool   `tfsdk:"default"`
+	IsArray      types.Bool   `tfsdk:"is_array"`
+	Secret       types.Bool   `tfsdk:"secret"`
+	DefaultValue types.String `tfsdk:"default_value"`
+	Predefined   types.List   `tfsdk:"predefined"`
+}
+
+// ScriptOutput -
+type ScriptOutput struct {
+	ContextPath types.String `tfsdk:"context_path"`
+	Description types.String `tfsdk:"description"`
+	Type        types.String `tfsdk:"type"`
+}
+
+// IncidentType -
+type IncidentType struct {
+	Name              types.String `tfsdk:"name"`
+	Id                types.String `tfsdk:"id"`
+	Color             types.String `tfsdk:"color"`
+	PlaybookId        types.String `tfsdk:"playbook_id"`
+	Autorun           types.Bool   `tfsdk:"autorun"`
+	AutoExtract       types.String `tfsdk:"auto_extract"`
+	Sla               types.Int64  `tfsdk:"sla"`
+	SlaReminder       types.Int64  `tfsdk:"sla_reminder"`
+	Hours             types.Int64  `tfsdk:"hours"`
+	Days              types.Int64  `tfsdk:"days"`
+	Weeks             types.Int64  `tfsdk:"weeks"`
+	ClosureScript     types.String `tfsdk:"closure_script"`
+	Layout            types.String `tfsdk:"layout"`
+	Disabled          types.Bool   `tfsdk:"disabled"`
+	PropagationLabels types.Set    `tfsdk:"propagation_labels"`
+	Account           types.String `tfsdk:"account"`
+	Timeouts          []Timeouts   `tfsdk:"timeouts"`
+}
+
+// Field - an incident or indicator field
+type Field struct {
+	Name                  types.String  `tfsdk:"name"`
+	Id                    types.String  `tfsdk:"id"`
+	CliName               types.String  `tfsdk:"cli_name"`
+	Type                  types.String  `tfsdk:"type"`
+	Description           types.String  `tfsdk:"description"`
+	SelectValues          types.List    `tfsdk:"select_values"`
+	AssociatedTypes       types.Set     `tfsdk:"associated_types"`
+	AssociatedToAll       types.Bool    `tfsdk:"associated_to_all"`
+	SystemAssociatedTypes types.Set     `tfsdk:"system_associated_types"`
+	Unsearchable          types.Bool    `tfsdk:"unsearchable"`
+	Required              types.Bool    `tfsdk:"required"`
+	CloseForm             types.Bool    `tfsdk:"close_form"`
+	Columns               []FieldColumn `tfsdk:"columns"`
+	Account               types.String  `tfsdk:"account"`
+	Timeouts              []Timeouts    `tfsdk:"timeouts"`
+}
+
+// FieldColumn - a column of a grid field
+type FieldColumn struct {
+	Key          types.String `tfsdk:"key"`
+	DisplayName  types.String `tfsdk:"display_name"`
+	Type         types.String `tfsdk:"type"`
+	Required     types.Bool   `tfsdk:"required"`
+	SelectValues types.List   `tfsdk:"select_values"`
+}
+
+// Layout -
+type Layout struct {
+	Name              types.String `tfsdk:"name"`
+	Id                types.String `tfsdk:"id"`
+	Group             types.String `tfsdk:"group"`
+	Content           types.String `tfsdk:"content"`
+	PropagationLabels types.Set    `tfsdk:"propagation_labels"`
+	Account           types.String `tfsdk:"account"`
+	Timeouts          []Timeouts   `tfsdk:"timeouts"`
+}
+
+// List -
+type List struct {
+	Name     types.String `tfsdk:"name"`
+	Id       types.String `tfsdk:"id"`
+	Type     types.String `tfsdk:"type"`
+	Data     types.String `tfsdk:"data"`
+	Tags     types.Set    `tfsdk:"tags"`
+	Account  types.String `tfsdk:"account"`
+	Timeouts []Timeouts   `tfsdk:"timeouts"`
+}
+
+// ListDataSource -
+type ListDataSource struct {
+	Name    types.String `tfsdk:"name"`
+	Id      types.String `tfsdk:"id"`
+	Type    types.String `tfsdk:"type"`
+	Data    types.String `tfsdk:"data"`
+	Tags    types.Set    `tfsdk:"tags"`
+	Account types.String `tfsdk:"account"`
+}
+
+// Job -
+type Job struct {
+	Name             types.String `tfsdk:"name"`
+	Id               types.String `tfsdk:"id"`
+	PlaybookId       types.String `tfsdk:"playbook_id"`
+	IncidentType     types.String `tfsdk:"incident_type"`
+	Cron             types.String `tfsdk:"cron"`
+	Interval         types.String `tfsdk:"interval"`
+	StartDate        types.String `tfsdk:"start_date"`
+	EndDate          types.String `tfsdk:"end_date"`
+	IsFeed           types.Bool   `tfsdk:"is_feed"`
+	SelectedFeeds    types.Set    `tfsdk:"selected_feeds"`
+	ShouldTriggerNew types.Bool   `tfsdk:"should_trigger_new"`
+	ClosePrevRun     types.Bool   `tfsdk:"close_prev_run"`
+	Tags             types.Set    `tfsdk:"tags"`
+	Account          types.String `tfsdk:"account"`
+	Timeouts         []Timeouts   `tfsdk:"timeouts"`
+}
+
+// PreprocessRule -
+type PreprocessRule struct {
+	Name               types.String          `tfsdk:"name"`
+	Id                 types.String          `tfsdk:"id"`
+	Enabled            types.Bool            `tfsdk:"enabled"`
+	Action             types.String          `tfsdk:"action"`
+	ScriptName         types.String          `tfsdk:"script_name"`
+	Index              types.Int64           `tfsdk:"index"`
+	Conditions         []PreprocessCondition `tfsdk:"condition"`
+	ExistingConditions []PreprocessCondition `tfsdk:"existing_condition"`
+	Account            types.String          `tfsdk:"account"`
+	Timeouts           []Timeouts            `tfsdk:"timeouts"`
+}
+
+// PreprocessCondition - a condition of a pre-process rule
+type PreprocessCondition struct {
+	Field    types.String `tfsdk:"field"`
+	Operator types.String `tfsdk:"operator"`
+	Value    types.String `tfsdk:"value"`
+}
+
+// Role -
+type Role struct {
+	Name              types.String `tfsdk:"name"`
+	Id                types.String `tfsdk:"id"`
+	Permissions       types.Map    `tfsdk:"permissions"`
+	PageAccess        types.Set    `tfsdk:"page_access"`
+	PropagationLabels types.Set    `tfsdk:"propagation_labels"`
+	Account           types.String `tfsdk:"account"`
+	Timeouts          []Timeouts   `tfsdk:"timeouts"`
+}
+
+// User -
+type User struct {
+	Username     types.String `tfsdk:"username"`
+	Id           types.String `tfsdk:"id"`
+	Name         types.String `tfsdk:"name"`
+	Email        types.String `tfsdk:"email"`
+	Phone        types.String `tfsdk:"phone"`
+	Password     types.String `tfsdk:"password"`
+	DefaultAdmin types.Bool   `tfsdk:"default_admin"`
+	Roles        []UserRoles  `tfsdk:"roles"`
+	Timeouts     []Timeouts   `tfsdk:"timeouts"`
+}
+
+// UserRoles - the roles of a user in the main tenant or an account
+type UserRoles struct {
+	Account types.String `tfsdk:"account"`
+	Names   types.Set    `tfsdk:"names"`
+}
+
+// APIKey -
+type APIKey struct {
+	Name     types.String `tfsdk:"name"`
+	Id       types.String `tfsdk:"id"`
+	Key      types.String `tfsdk:"key"`
+	User     types.String `tfsdk:"user"`
+	Keepers  types.Map    `tfsdk:"keepers"`
+	Timeouts []Timeouts   `tfsdk:"timeouts"`
+}
+
+// Credential -
+type Credential struct {
+	Name        types.String `tfsdk:"name"`
+	Id          types.String `tfsdk:"id"`
+	User        types.String `tfsdk:"user"`
+	Password    types.String `tfsdk:"password"`
+	Certificate types.String `tfsdk:"certificate"`
+	Workgroup   types.String `tfsdk:"workgroup"`
+	Account     types.String `tfsdk:"account"`
+	Timeouts    []Timeouts   `tfsdk:"timeouts"`
+}
+
+// Engine -
+type Engine struct {
+	Name                types.String `tfsdk:"name"`
+	Id                  types.String `tfsdk:"id"`
+	InstallerType       types.String `tfsdk:"installer_type"`
+	LoadBalancingGroup  types.String `tfsdk:"load_balancing_group"`
+	ServerUrl           types.String `tfsdk:"server_url"`
+	SSHUser             types.String `tfsdk:"ssh_user"`
+	SSHKey              types.String `tfsdk:"ssh_key"`
+	HostKey             types.String `tfsdk:"host_key"`
+	HostKeyFingerprint  types.String `tfsdk:"host_key_fingerprint"`
+	KnownHostsFile      types.String `tfsdk:"known_hosts_file"`
+	HostKeyChecking     types.String `tfsdk:"host_key_checking"`
+	ObservedHostKey     types.String `tfsdk:"observed_host_key"`
+	InstallationTimeout types.Int64  `tfsdk:"installation_timeout"`
+	Timeouts            []Timeouts   `tfsdk:"timeouts"`
+}
+
+// ContentPack -
+type ContentPack struct {
+	PackId           types.String `tfsdk:"pack_id"`
+	Id               types.String `tfsdk:"id"`
+	Version          types.String `tfsdk:"version"`
+	InstalledVersion types.String `tfsdk:"installed_version"`
+	File             types.String `tfsdk:"file"`
+	FileHash         types.String `tfsdk:"file_hash"`
+	Account          types.String `tfsdk:"account"`
+	Timeouts         []Timeouts   `tfsdk:"timeouts"`
+}
+
+// Timeouts - the timeouts block of a resource, durations such as "30m" per operation
+type Timeouts struct {
+	Create types.String `tfsdk:"create"`
+	Read   types.String `tfsdk:"read"`
+	Update types.String `tfsdk:"update"`
+	Delete types.String `tfsdk:"delete"`
 }
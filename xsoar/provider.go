@@ -163,6 +163,21 @@ func (p *provider) GetResources(_ context.Context) (map[string]tfsdk.ResourceTyp
 		"xsoar_integration_instance": resourceIntegrationInstanceType{},
 		"xsoar_classifier":           resourceClassifierType{},
 		"xsoar_mapper":               resourceMapperType{},
+		"xsoar_playbook":             resourcePlaybookType{},
+		"xsoar_script":               resourceScriptType{},
+		"xsoar_incident_type":        resourceIncidentTypeType{},
+		"xsoar_incident_field":       resourceIncidentFieldType{},
+		"xsoar_indicator_field":      resourceIndicatorFieldType{},
+		"xsoar_layout":               resourceLayoutType{},
+		"xsoar_list":                 resourceListType{},
+		"xsoar_job":                  resourceJobType{},
+		"xsoar_preprocess_rule":      resourcePreprocessRuleType{},
+		"xsoar_role":                 resourceRoleType{},
+		"xsoar_user":                 resourceUserType{},
+		"xsoar_api_key":              resourceAPIKeyType{},
+		"xsoar_credential":           resourceCredentialType{},
+		"xsoar_engine":               resourceEngineType{},
+		"xsoar_content_pack":         resourceContentPackType{},
 	}, nil
 }
 
@@ -177,5 +192,6 @@ func (p *provider) GetDataSources(_ context.Context) (map[string]tfsdk.DataSourc
 		"xsoar_integration_instance": dataSourceIntegrationInstanceType{},
 		"xsoar_classifier":           dataSourceClassifierType{},
 		"xsoar_mapper":               dataSourceMapperType{},
+		"xsoar_list":                 dataSourceListType{},
 	}, nil
 }
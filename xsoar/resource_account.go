@@ -57,14 +57,18 @@ func (r resourceAccountType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Di
 				Computed: true,
 			},
 			"timeout": {
-				Type:     types.Int64Type,
-				Optional: true,
+				Type:               types.Int64Type,
+				Optional:           true,
+				DeprecationMessage: "Use the create timeout of the timeouts block instead.",
 			},
 			"concurrency_limit": {
 				Type:     types.Int64Type,
 				Optional: true,
 			},
 		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
 	}, nil
 }
 
@@ -81,6 +85,13 @@ type resourceAccount struct {
 
 // Create a new resource
 func (r resourceAccount) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withCreateTimeout(ctx, req.Plan, "timeout", installTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if !r.p.configured {
 		resp.Diagnostics.AddError(
 			"Provider not configured",
@@ -91,7 +102,7 @@ func (r resourceAccount) Create(ctx context.Context, req tfsdk.CreateResourceReq
 
 	// Retrieve values from plan
 	var plan Account
-	diags := req.Plan.Get(ctx, &plan)
+	diags = req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		log.Printf("%+v\n", req.Plan)
@@ -126,14 +137,14 @@ func (r resourceAccount) Create(ctx context.Context, req tfsdk.CreateResourceReq
 	}
 	if !plan.PropagationLabels.Null && len(plan.PropagationLabels.Elems) > 0 {
 		var propagationLabels []string
-		plan.PropagationLabels.ElementsAs(ctx, propagationLabels, true)
+		plan.PropagationLabels.ElementsAs(ctx, &propagationLabels, true)
 		createAccountRequest.SetPropagationLabels(propagationLabels)
 	}
 	createAccountRequest.SetSyncOnCreation(true)
 
 	// Create new account
 	var accounts []map[string]interface{}
-	timeout := time.Duration(1800) * time.Second
+	timeout := untilDeadline(ctx)
 	if !plan.Timeout.Null && plan.Timeout.Value > 0 {
 		timeout = time.Duration(plan.Timeout.Value) * time.Second
 	}
@@ -148,7 +159,9 @@ func (r resourceAccount) Create(ctx context.Context, req tfsdk.CreateResourceReq
 		nrand := rand.New(randSource)
 		randomTimeToWait := nrand.Intn(90) + 1
 		log.Printf("sleeping for %d seconds\n", randomTimeToWait)
-		time.Sleep(time.Duration(randomTimeToWait) * time.Second)
+		if sleepErr := sleepContext(ctx, time.Duration(randomTimeToWait)*time.Second); sleepErr != nil {
+			return resource.NonRetryableError(sleepErr)
+		}
 		// wait until no other accounts are being created
 		accounts, httpResponse, err = r.p.client.DefaultApi.ListAccounts(ctx).Execute()
 		if httpResponse != nil {
@@ -168,7 +181,9 @@ func (r resourceAccount) Create(ctx context.Context, req tfsdk.CreateResourceReq
 				concurrencyLimit = plan.Concurrency.Value
 			}
 			if accountsBeingCreated >= concurrencyLimit {
-				time.Sleep(60 * time.Second)
+				if sleepErr := sleepContext(ctx, 60*time.Second); sleepErr != nil {
+					return resource.NonRetryableError(sleepErr)
+				}
 				return resource.RetryableError(fmt.Errorf("waiting for account %s to finish creation", account["name"].(string)))
 			}
 		}
@@ -183,7 +198,9 @@ func (r resourceAccount) Create(ctx context.Context, req tfsdk.CreateResourceReq
 		}
 		if err != nil {
 			log.Println(err.Error())
-			time.Sleep(60 * time.Second)
+			if sleepErr := sleepContext(ctx, 60*time.Second); sleepErr != nil {
+				return resource.NonRetryableError(sleepErr)
+			}
 			return resource.RetryableError(fmt.Errorf("error message: %s, http response: %s", err, body))
 		}
 
@@ -207,14 +224,26 @@ func (r resourceAccount) Create(ctx context.Context, req tfsdk.CreateResourceReq
 				"Error getting account",
 				"Could not read account "+accName+": "+err.Error(),
 			)
+			return resource.NonRetryableError(err)
 		}
 		if account["status"].(string) == "" {
-			time.Sleep(60 * time.Second)
+			if sleepErr := sleepContext(ctx, 60*time.Second); sleepErr != nil {
+				return resource.NonRetryableError(sleepErr)
+			}
 			return resource.RetryableError(fmt.Errorf("waiting for account %s to finish creation", account["name"].(string)))
 		}
 
 		return nil
 	})
+	if err != nil {
+		if !resp.Diagnostics.HasError() {
+			resp.Diagnostics.AddError(
+				"Error creating account",
+				"Could not verify account "+accName+": "+err.Error(),
+			)
+		}
+		return
+	}
 
 	// Map response body to resource schema attribute
 	var result Account
@@ -282,6 +311,7 @@ func (r resourceAccount) Create(ctx context.Context, req tfsdk.CreateResourceReq
 	}
 
 	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
 	diags = resp.State.Set(ctx, &result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -291,9 +321,16 @@ func (r resourceAccount) Create(ctx context.Context, req tfsdk.CreateResourceReq
 
 // Read resource information
 func (r resourceAccount) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get current state
 	var state Account
-	diags := req.State.Get(ctx, &state)
+	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -390,6 +427,7 @@ func (r resourceAccount) Read(ctx context.Context, req tfsdk.ReadResourceRequest
 		Id:          types.String{Value: account["id"].(string)},
 		Timeout:     state.Timeout,
 		Concurrency: state.Concurrency,
+		Timeouts:    state.Timeouts,
 	}
 
 	// Set state
@@ -402,9 +440,16 @@ func (r resourceAccount) Read(ctx context.Context, req tfsdk.ReadResourceRequest
 
 // Update resource
 func (r resourceAccount) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get plan values
 	var plan Account
-	diags := req.Plan.Get(ctx, &plan)
+	diags = req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -633,6 +678,7 @@ func (r resourceAccount) Update(ctx context.Context, req tfsdk.UpdateResourceReq
 	}
 
 	// Set state
+	result.Timeouts = plan.Timeouts
 	diags = resp.State.Set(ctx, &result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -642,8 +688,15 @@ func (r resourceAccount) Update(ctx context.Context, req tfsdk.UpdateResourceReq
 
 // Delete resource
 func (r resourceAccount) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var state Account
-	diags := req.State.Get(ctx, &state)
+	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -651,7 +704,7 @@ func (r resourceAccount) Delete(ctx context.Context, req tfsdk.DeleteResourceReq
 
 	accName := "acc_" + state.Name.Value
 
-	err := resource.RetryContext(ctx, 300*time.Second, func() *resource.RetryError {
+	err := resource.RetryContext(ctx, untilDeadline(ctx), func() *resource.RetryError {
 		// Get account current value
 		account, _, _ := r.p.client.DefaultApi.GetAccount(ctx, accName).Execute()
 		if account != nil {
@@ -762,6 +815,7 @@ func (r resourceAccount) ImportState(ctx context.Context, req tfsdk.ImportResour
 		Id:          types.String{Value: account["id"].(string)},
 		Timeout:     types.Int64{Value: 900},
 		Concurrency: types.Int64{Value: 1},
+		Timeouts:    []Timeouts{},
 	}
 
 	// Set state
@@ -3,11 +3,13 @@ package xsoar
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"hash/crc64"
+	"math/rand"
 	"strings"
 	"testing"
 )
@@ -18,7 +20,7 @@ func TestAccAccount_basic(t *testing.T) {
 		PreCheck: func() { testAccAccountResourcePreCheck(t) },
 		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
 			"xsoar": func() (tfprotov6.ProviderServer, error) {
-				return tfsdk.NewProtocol6Server(New()), nil
+				return providerserver.NewProtocol6WithError(New()())()
 			},
 		},
 		CheckDestroy: testAccCheckAccountResourceDestroy(rName),
@@ -37,6 +39,65 @@ func TestAccAccount_basic(t *testing.T) {
 	})
 }
 
+func TestAccount_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	groupId := m.addHAGroup("mockgroup")
+	otherGroupId := m.addHAGroup("othergroup")
+	tf := newMockTerraform(t, m)
+	name := mockAccountName("mockacc")
+
+	r := tf.resource("xsoar_account")
+	r.mustApply(map[string]interface{}{
+		"name":               name,
+		"host_group_name":    "mockgroup",
+		"propagation_labels": []string{"mocklabel"},
+	})
+	account := m.account(name)
+	if account == nil {
+		t.Fatal("account was not created")
+	}
+	if id := r.attrString("id"); id != account["id"] {
+		t.Fatalf("account ID created (%s) did not match state (%s)", account["id"], id)
+	}
+	if r.attrString("host_group_id") != groupId {
+		t.Fatalf("expected host group %s, got %s", groupId, r.attrString("host_group_id"))
+	}
+	if diffs := mockDiff("", []interface{}{"Administrator"}, r.attr("account_roles")); len(diffs) > 0 {
+		t.Fatalf("unexpected default roles: %s", diffs)
+	}
+
+	r.mustApply(map[string]interface{}{
+		"name":               name,
+		"host_group_name":    "othergroup",
+		"account_roles":      []string{"Analyst"},
+		"propagation_labels": []string{"mocklabel"},
+	})
+	if r.attrString("host_group_id") != otherGroupId {
+		t.Fatalf("account was not moved to host group %s", otherGroupId)
+	}
+	if diffs := mockDiff("", []interface{}{"Analyst"}, r.attr("account_roles")); len(diffs) > 0 {
+		t.Fatalf("roles were not updated: %s", diffs)
+	}
+
+	r.mustImport(name, "timeout", "concurrency_limit")
+	r.mustDestroy()
+	if m.account(name) != nil {
+		t.Fatal("account returned when it should be destroyed")
+	}
+}
+
+// mockAccountName returns a name for which account creation starts after the shortest random delay
+func mockAccountName(prefix string) string {
+	for i := 0; ; i++ {
+		name := fmt.Sprintf("%s%d", prefix, i)
+		seed := int64(crc64.Checksum([]byte(name), crc64.MakeTable(crc64.ISO)))
+		if rand.New(rand.NewSource(seed)).Intn(90) == 0 {
+			return name
+		}
+	}
+}
+
 func testAccAccountResourcePreCheck(t *testing.T) {}
 
 func testAccCheckAccountResourceExists(r string) resource.TestCheckFunc {
@@ -0,0 +1,296 @@
+package xsoar
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// generateAPIKey returns a random key in the format XSOAR generates
+func generateAPIKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(hex.EncodeToString(b)), nil
+}
+
+type resourceAPIKeyType struct{}
+
+// GetSchema Resource schema
+func (r resourceAPIKeyType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:          types.StringType,
+				Required:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+			"id": {
+				Type:          types.StringType,
+				Computed:      true,
+				Optional:      false,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"key": {
+				Type:          types.StringType,
+				Optional:      true,
+				Computed:      true,
+				Sensitive:     true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown(), tfsdk.RequiresReplace()),
+			},
+			"user": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+			"keepers": {
+				Type:          types.MapType{ElemType: types.StringType},
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourceAPIKeyType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceAPIKey{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type resourceAPIKey struct {
+	p provider
+}
+
+func (r resourceAPIKey) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config APIKey
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Key.Null && !config.Key.Unknown && len(config.Key.Value) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("key"),
+			"Invalid API key",
+			"The key must not be empty, leave it unset to have one generated.",
+		)
+	}
+}
+
+// getAPIKey returns the API key with the given id, or nil if there is none
+func (r resourceAPIKey) getAPIKey(ctx context.Context, id string) (map[string]interface{}, error) {
+	var keys []map[string]interface{}
+	_, err := r.p.doRequest(ctx, "GET", "/apikeys", nil, &keys)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if key["id"] == id {
+			return key, nil
+		}
+	}
+	return nil, nil
+}
+
+// Create a new resource
+func (r resourceAPIKey) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan APIKey
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	key := plan.Key.Value
+	if plan.Key.Null || plan.Key.Unknown {
+		var err error
+		key, err = generateAPIKey()
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error creating API key",
+				"Could not generate API key: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// Create
+	body := map[string]interface{}{
+		"name":   plan.Name.Value,
+		"apikey": key,
+	}
+	if !plan.User.Null && !plan.User.Unknown {
+		body["user"] = plan.User.Value
+	}
+	var apiKey map[string]interface{}
+	_, err := r.p.doRequest(ctx, "POST", "/apikeys", body, &apiKey)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error creating API key",
+			"Could not create API key: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	id, _ := apiKey["id"].(string)
+	result := APIKey{
+		Name:    plan.Name,
+		Id:      types.String{Value: id},
+		Key:     types.String{Value: key},
+		User:    plan.User,
+		Keepers: plan.Keepers,
+	}
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourceAPIKey) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state APIKey
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	apiKey, err := r.getAPIKey(ctx, state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting API key",
+			"Could not get API key: "+err.Error(),
+		)
+		return
+	}
+	if apiKey == nil {
+		log.Println("API key not found")
+		// Remove resource from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Map response body to resource schema attribute, the key itself is never returned
+	name, _ := apiKey["name"].(string)
+	user, _ := apiKey["user"].(string)
+	result := APIKey{
+		Name:    types.String{Value: name},
+		Id:      state.Id,
+		Key:     state.Key,
+		User:    optionalString(user, state.User),
+		Keepers: state.Keepers,
+	}
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourceAPIKey) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// every attribute forces a new key, so there is nothing to update on the server
+	var plan APIKey
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Set state
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourceAPIKey) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state APIKey
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Revoke
+	_, err := r.p.doRequest(ctx, "DELETE", "/apikeys/"+url.PathEscape(state.Id.Value), nil, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error revoking API key",
+			"Could not revoke API key: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
@@ -0,0 +1,115 @@
+package xsoar
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// mockAuthenticates reports whether the mock server accepts the given API key
+func mockAuthenticates(t *testing.T, m *mockXSOAR, key string) bool {
+	req, err := http.NewRequest("GET", m.Server.URL+"/apikeys", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", key)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+func TestAPIKey_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.putObject("", "users", map[string]interface{}{"id": "mockuser", "username": "mockuser"})
+	tf := newMockTerraform(t, m)
+
+	// a generated key
+	config := map[string]interface{}{
+		"name":    "mock-generated",
+		"keepers": map[string]interface{}{"rotation": "1"},
+	}
+	r := tf.resource("xsoar_api_key")
+	r.mustApply(config)
+	id := r.attrString("id")
+	key := r.attrString("key")
+	if len(key) != 32 {
+		t.Fatalf("expected a generated 32 character key, got %q", key)
+	}
+	if !mockAuthenticates(t, m, key) {
+		t.Fatal("generated key was not accepted")
+	}
+
+	// changing the keepers rotates the key
+	config["keepers"] = map[string]interface{}{"rotation": "2"}
+	r.mustApply(config)
+	if r.attrString("id") == id || r.attrString("key") == key {
+		t.Fatal("key was not rotated")
+	}
+	if mockAuthenticates(t, m, key) {
+		t.Fatal("rotated key was not revoked")
+	}
+	r.mustDestroy()
+
+	// a supplied key scoped to a user
+	supplied := tf.resource("xsoar_api_key")
+	supplied.mustApply(map[string]interface{}{
+		"name": "mock-supplied",
+		"key":  "0123456789ABCDEF0123456789ABCDEF",
+		"user": "mockuser",
+	})
+	apiKey := m.object("", "apikeys", supplied.attrString("id"))
+	if apiKey["user"] != "mockuser" || !mockAuthenticates(t, m, "0123456789ABCDEF0123456789ABCDEF") {
+		t.Fatalf("unexpected api key %v", apiKey)
+	}
+	supplied.mustDestroy()
+	if mockAuthenticates(t, m, "0123456789ABCDEF0123456789ABCDEF") {
+		t.Fatal("key was not revoked on destroy")
+	}
+
+	if err := tf.resource("xsoar_api_key").apply(map[string]interface{}{"name": "mock", "key": ""}); err == nil {
+		t.Fatal("expected an error for an empty key")
+	}
+}
+
+func TestAPIKey_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{"name": "mock-generated"}
+
+	m.fail("POST", "apikeys", http.StatusInternalServerError)
+	r := tf.resource("xsoar_api_key")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Could not create API key") {
+		t.Fatalf("expected the server error to fail the create, got %v", err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("a key that failed to create was stored in state")
+	}
+	m.fail("POST", "apikeys", 0)
+
+	r.mustApply(config)
+	m.fail("GET", "apikeys", http.StatusInternalServerError)
+	if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get API key") {
+		t.Fatalf("expected the server error to fail the read, got %v", err)
+	}
+	m.fail("GET", "apikeys", 0)
+	m.fail("DELETE", "apikeys/*", http.StatusInternalServerError)
+	if err = r.destroy(); err == nil || !strings.Contains(err.Error(), "Could not revoke API key") {
+		t.Fatalf("expected the server error to fail the revoke, got %v", err)
+	}
+	m.fail("DELETE", "apikeys/*", 0)
+
+	// a key revoked outside of terraform is removed from state
+	m.removeObject("", "apikeys", r.attrString("id"))
+	if err = r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the revoked key to be removed from state")
+	}
+}
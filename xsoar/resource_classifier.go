@@ -57,6 +57,9 @@ func (r resourceClassifierType) GetSchema(_ context.Context) (tfsdk.Schema, diag
 				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
 			},
 		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
 	}, nil
 }
 
@@ -73,6 +76,13 @@ type resourceClassifier struct {
 
 // Create a new resource
 func (r resourceClassifier) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if !r.p.configured {
 		resp.Diagnostics.AddError(
 			"Provider not configured",
@@ -83,7 +93,7 @@ func (r resourceClassifier) Create(ctx context.Context, req tfsdk.CreateResource
 
 	// Retrieve values from plan
 	var plan Classifier
-	diags := req.Plan.Get(ctx, &plan)
+	diags = req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -123,7 +133,7 @@ func (r resourceClassifier) Create(ctx context.Context, req tfsdk.CreateResource
 	}
 	if !plan.PropagationLabels.Unknown {
 		var props []string
-		plan.PropagationLabels.ElementsAs(ctx, props, true)
+		plan.PropagationLabels.ElementsAs(ctx, &props, true)
 		classifierRequest.SetPropagationLabels(props)
 	}
 	var classifier openapi.InstanceClassifier
@@ -153,14 +163,6 @@ func (r resourceClassifier) Create(ctx context.Context, req tfsdk.CreateResource
 	}
 
 	// Map response body to resource schema attribute
-	defaultIncidentType, err := json.Marshal(classifier.GetDefaultIncidentType())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error marshalling json",
-			"Could not marshal json: "+err.Error(),
-		)
-		return
-	}
 	keyTypeMap, err := json.Marshal(classifier.GetKeyTypeMap())
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -183,10 +185,10 @@ func (r resourceClassifier) Create(ctx context.Context, req tfsdk.CreateResource
 		PropagationLabels: types.Set{Elems: propLabels, ElemType: types.StringType},
 		Account:           plan.Account,
 	}
-	if v := string(defaultIncidentType); v == "null" {
-		result.DefaultIncidentType = types.String{Null: true}
+	if v, ok := classifier.GetDefaultIncidentTypeOk(); ok {
+		result.DefaultIncidentType = types.String{Value: *v}
 	} else {
-		result.DefaultIncidentType = types.String{Value: v}
+		result.DefaultIncidentType = types.String{Null: true}
 	}
 	if v := string(keyTypeMap); v == "null" {
 		result.KeyTypeMap = types.String{Null: true}
@@ -200,6 +202,7 @@ func (r resourceClassifier) Create(ctx context.Context, req tfsdk.CreateResource
 	}
 
 	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -209,9 +212,16 @@ func (r resourceClassifier) Create(ctx context.Context, req tfsdk.CreateResource
 
 // Read resource information
 func (r resourceClassifier) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get current state
 	var state Classifier
-	diags := req.State.Get(ctx, &state)
+	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -253,14 +263,6 @@ func (r resourceClassifier) Read(ctx context.Context, req tfsdk.ReadResourceRequ
 	}
 
 	// Map response body to resource schema attribute
-	defaultIncidentType, err := json.Marshal(classifier.GetDefaultIncidentType())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error marshalling json",
-			"Could not marshal json: "+err.Error(),
-		)
-		return
-	}
 	keyTypeMap, err := json.Marshal(classifier.GetKeyTypeMap())
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -283,10 +285,10 @@ func (r resourceClassifier) Read(ctx context.Context, req tfsdk.ReadResourceRequ
 		PropagationLabels: types.Set{Elems: propLabels, ElemType: types.StringType},
 		Account:           state.Account,
 	}
-	if v := string(defaultIncidentType); v == "null" {
-		result.DefaultIncidentType = types.String{Null: true}
+	if v, ok := classifier.GetDefaultIncidentTypeOk(); ok {
+		result.DefaultIncidentType = types.String{Value: *v}
 	} else {
-		result.DefaultIncidentType = types.String{Value: v}
+		result.DefaultIncidentType = types.String{Null: true}
 	}
 	if v := string(keyTypeMap); v == "null" {
 		result.KeyTypeMap = types.String{Null: true}
@@ -300,6 +302,7 @@ func (r resourceClassifier) Read(ctx context.Context, req tfsdk.ReadResourceRequ
 	}
 
 	// Generate resource state struct
+	result.Timeouts = state.Timeouts
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -309,9 +312,16 @@ func (r resourceClassifier) Read(ctx context.Context, req tfsdk.ReadResourceRequ
 
 // Update resource
 func (r resourceClassifier) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get plan values
 	var plan Classifier
-	diags := req.Plan.Get(ctx, &plan)
+	diags = req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -360,7 +370,7 @@ func (r resourceClassifier) Update(ctx context.Context, req tfsdk.UpdateResource
 	}
 	if !plan.PropagationLabels.Null {
 		var props []string
-		plan.PropagationLabels.ElementsAs(ctx, props, true)
+		plan.PropagationLabels.ElementsAs(ctx, &props, true)
 		classifierRequest.SetPropagationLabels(props)
 	}
 	var classifier openapi.InstanceClassifier
@@ -390,14 +400,6 @@ func (r resourceClassifier) Update(ctx context.Context, req tfsdk.UpdateResource
 	}
 
 	// Map response body to resource schema attribute
-	defaultIncidentType, err := json.Marshal(classifier.GetDefaultIncidentType())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error marshalling json",
-			"Could not marshal json: "+err.Error(),
-		)
-		return
-	}
 	keyTypeMap, err := json.Marshal(classifier.GetKeyTypeMap())
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -420,10 +422,10 @@ func (r resourceClassifier) Update(ctx context.Context, req tfsdk.UpdateResource
 		PropagationLabels: types.Set{Elems: propLabels, ElemType: types.StringType},
 		Account:           plan.Account,
 	}
-	if v := string(defaultIncidentType); v == "null" {
-		result.DefaultIncidentType = types.String{Null: true}
+	if v, ok := classifier.GetDefaultIncidentTypeOk(); ok {
+		result.DefaultIncidentType = types.String{Value: *v}
 	} else {
-		result.DefaultIncidentType = types.String{Value: v}
+		result.DefaultIncidentType = types.String{Null: true}
 	}
 	if v := string(keyTypeMap); v == "null" {
 		result.KeyTypeMap = types.String{Null: true}
@@ -437,6 +439,7 @@ func (r resourceClassifier) Update(ctx context.Context, req tfsdk.UpdateResource
 	}
 
 	// Set state
+	result.Timeouts = plan.Timeouts
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -446,9 +449,16 @@ func (r resourceClassifier) Update(ctx context.Context, req tfsdk.UpdateResource
 
 // Delete resource
 func (r resourceClassifier) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get state
 	var state Classifier
-	diags := req.State.Get(ctx, &state)
+	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -506,14 +516,6 @@ func (r resourceClassifier) ImportState(ctx context.Context, req tfsdk.ImportRes
 	}
 
 	// Map response body to resource schema attribute
-	defaultIncidentType, err := json.Marshal(classifier.GetDefaultIncidentType())
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error marshalling json",
-			"Could not marshal json: "+err.Error(),
-		)
-		return
-	}
 	keyTypeMap, err := json.Marshal(classifier.GetKeyTypeMap())
 	if err != nil {
 		resp.Diagnostics.AddError(
@@ -540,10 +542,10 @@ func (r resourceClassifier) ImportState(ctx context.Context, req tfsdk.ImportRes
 	} else {
 		result.Account = types.String{Value: acc}
 	}
-	if v := string(defaultIncidentType); v == "null" {
-		result.DefaultIncidentType = types.String{Null: true}
+	if v, ok := classifier.GetDefaultIncidentTypeOk(); ok {
+		result.DefaultIncidentType = types.String{Value: *v}
 	} else {
-		result.DefaultIncidentType = types.String{Value: v}
+		result.DefaultIncidentType = types.String{Null: true}
 	}
 	if v := string(keyTypeMap); v == "null" {
 		result.KeyTypeMap = types.String{Null: true}
@@ -557,6 +559,7 @@ func (r resourceClassifier) ImportState(ctx context.Context, req tfsdk.ImportRes
 	}
 
 	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
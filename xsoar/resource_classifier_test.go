@@ -3,7 +3,7 @@ package xsoar
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -18,7 +18,7 @@ func TestAccClassifier_basic(t *testing.T) {
 		PreCheck: func() { testAccClassifierResourcePreCheck(t) },
 		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
 			"xsoar": func() (tfprotov6.ProviderServer, error) {
-				return tfsdk.NewProtocol6Server(New()), nil
+				return providerserver.NewProtocol6WithError(New()())()
 			},
 		},
 		CheckDestroy: testAccCheckClassifierResourceDestroy(rName),
@@ -37,6 +37,44 @@ func TestAccClassifier_basic(t *testing.T) {
 	})
 }
 
+func TestClassifier_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{"name": "mockclassifier"}
+		importId := "mockclassifier"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".mockclassifier"
+		}
+		r := tf.resource("xsoar_classifier")
+		r.mustApply(config)
+		id := r.attrString("id")
+		if m.object(acc, "classifiers", id) == nil {
+			t.Fatalf("classifier %s was not created in account %q", id, acc)
+		}
+
+		config["default_incident_type"] = "Phishing"
+		config["key_type_map"] = `{"phishing":"Phishing"}`
+		r.mustApply(config)
+		if r.attrString("id") != id {
+			t.Fatal("classifier was replaced when it should have been updated")
+		}
+		if got := m.object(acc, "classifiers", id)["defaultIncidentType"]; got != "Phishing" {
+			t.Fatalf("default incident type was not updated, got %v", got)
+		}
+
+		r.mustImport(importId)
+		r.mustDestroy()
+		if m.object(acc, "classifiers", id) != nil {
+			t.Fatal("found classifier when none was expected")
+		}
+	}
+}
+
 func testAccClassifierResourcePreCheck(t *testing.T) {}
 
 func testAccCheckClassifierResourceExists(r string) resource.TestCheckFunc {
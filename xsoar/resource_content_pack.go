@@ -0,0 +1,496 @@
+package xsoar
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// latestPackVersion is the version that follows the newest version in the marketplace
+const latestPackVersion = "latest"
+
+// packFileHash returns the hex encoded SHA-256 of a local pack zip
+func packFileHash(name string) (string, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+type resourceContentPackType struct{}
+
+// GetSchema Resource schema
+func (r resourceContentPackType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"pack_id": {
+				Type:          types.StringType,
+				Required:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+			"id": {
+				Type:          types.StringType,
+				Computed:      true,
+				Optional:      false,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"version": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"installed_version": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"file": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"file_hash": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"account": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourceContentPackType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceContentPack{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type resourceContentPack struct {
+	p provider
+}
+
+func (r resourceContentPack) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config ContentPack
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.File.Null && !config.Version.Null {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("version"),
+			"Invalid Attribute Combination",
+			"The version of an uploaded pack is taken from its zip, version and file cannot both be set.",
+		)
+	}
+	if !config.Version.Null && !config.Version.Unknown && len(config.Version.Value) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("version"),
+			"Invalid pack version",
+			"The version must be \"latest\" or a version of the pack, e.g. 1.2.3.",
+		)
+	}
+}
+
+// ModifyPlan plans the version to install, following the marketplace when the version is latest and the
+// content of the zip when a file is uploaded
+func (r resourceContentPack) ModifyPlan(ctx context.Context, req tfsdk.ModifyResourcePlanRequest, resp *tfsdk.ModifyResourcePlanResponse) {
+	// nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan ContentPack
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	var state ContentPack
+	if !req.State.Raw.IsNull() {
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.PackId.Unknown || plan.Version.Unknown || plan.File.Unknown || plan.Account.Unknown {
+		return
+	}
+
+	if !plan.File.Null {
+		hash, err := packFileHash(plan.File.Value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("file"),
+				"Error reading pack file",
+				"Could not read pack zip: "+err.Error(),
+			)
+			return
+		}
+		plan.FileHash = types.String{Value: hash}
+		if !req.State.Raw.IsNull() && hash == state.FileHash.Value {
+			plan.InstalledVersion = state.InstalledVersion
+		} else {
+			plan.InstalledVersion = types.String{Unknown: true}
+		}
+	} else {
+		plan.FileHash = types.String{Null: true}
+		version := plan.Version.Value
+		if plan.Version.Null || version == latestPackVersion {
+			if !r.p.configured {
+				plan.InstalledVersion = types.String{Unknown: true}
+				diags = resp.Plan.Set(ctx, plan)
+				resp.Diagnostics.Append(diags...)
+				return
+			}
+			var err error
+			version, err = r.latestVersion(ctx, plan.Account, plan.PackId.Value)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("pack_id"),
+					"Error getting content pack",
+					"Could not get the latest version of the pack from the marketplace: "+err.Error(),
+				)
+				return
+			}
+		}
+		plan.InstalledVersion = types.String{Value: version}
+	}
+
+	diags = resp.Plan.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// latestVersion returns the newest version of a pack in the marketplace
+func (r resourceContentPack) latestVersion(ctx context.Context, account types.String, packId string) (string, error) {
+	var pack map[string]interface{}
+	_, err := r.p.doRequest(ctx, "GET", accountPath(account, "/contentpacks/marketplace/"+url.PathEscape(packId)), nil, &pack)
+	if err != nil {
+		return "", err
+	}
+	version, _ := pack["currentVersion"].(string)
+	if len(version) == 0 {
+		return "", fmt.Errorf("pack %s has no version in the marketplace", packId)
+	}
+	return version, nil
+}
+
+// getInstalledPack returns the installed pack with the given id, or nil if it is not installed
+func (r resourceContentPack) getInstalledPack(ctx context.Context, account types.String, packId string) (map[string]interface{}, error) {
+	var packs []map[string]interface{}
+	_, err := r.p.doRequest(ctx, "GET", accountPath(account, "/contentpacks/metadata/installed"), nil, &packs)
+	if err != nil {
+		return nil, err
+	}
+	for _, pack := range packs {
+		if pack["id"] == packId {
+			return pack, nil
+		}
+	}
+	return nil, nil
+}
+
+// installPack installs, upgrades or downgrades the pack to the planned version, or uploads its zip, and returns the
+// pack as installed
+func (r resourceContentPack) installPack(ctx context.Context, plan ContentPack) (ContentPack, error) {
+	if !plan.File.Null {
+		content, err := os.ReadFile(plan.File.Value)
+		if err != nil {
+			return ContentPack{}, fmt.Errorf("could not read pack zip: %w", err)
+		}
+		log.Printf("uploading content pack %s from %s", plan.PackId.Value, plan.File.Value)
+		_, err = r.p.doUpload(ctx, accountPath(plan.Account, "/contentpacks/installed/upload"), "file", filepath.Base(plan.File.Value), content, nil)
+		if err != nil {
+			return ContentPack{}, err
+		}
+	} else {
+		version := plan.InstalledVersion.Value
+		if plan.InstalledVersion.Null || plan.InstalledVersion.Unknown {
+			var err error
+			version, err = r.latestVersion(ctx, plan.Account, plan.PackId.Value)
+			if err != nil {
+				return ContentPack{}, err
+			}
+		}
+		log.Printf("installing content pack %s version %s", plan.PackId.Value, version)
+		body := map[string]interface{}{
+			"packs":          []map[string]interface{}{{"id": plan.PackId.Value, "version": version}},
+			"ignoreWarnings": true,
+		}
+		_, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/contentpacks/marketplace/install"), body, nil)
+		if err != nil {
+			return ContentPack{}, err
+		}
+	}
+
+	pack, err := r.getInstalledPack(ctx, plan.Account, plan.PackId.Value)
+	if err != nil {
+		return ContentPack{}, err
+	}
+	if pack == nil {
+		return ContentPack{}, fmt.Errorf("pack %s was not installed, check that the zip holds a pack with this id", plan.PackId.Value)
+	}
+	return contentPackFromAPI(pack, plan), nil
+}
+
+// contentPackFromAPI maps an installed pack returned by XSOAR onto the resource
+func contentPackFromAPI(pack map[string]interface{}, prior ContentPack) ContentPack {
+	id, _ := pack["id"].(string)
+	version, _ := pack["currentVersion"].(string)
+	result := ContentPack{
+		PackId:           types.String{Value: id},
+		Id:               types.String{Value: id},
+		Version:          prior.Version,
+		InstalledVersion: types.String{Value: version},
+		File:             prior.File,
+		FileHash:         prior.FileHash,
+		Account:          prior.Account,
+	}
+	if result.FileHash.Unknown {
+		result.FileHash = types.String{Null: true}
+		if !prior.File.Null {
+			if hash, err := packFileHash(prior.File.Value); err == nil {
+				result.FileHash = types.String{Value: hash}
+			}
+		}
+	}
+	return result
+}
+
+// Create a new resource
+func (r resourceContentPack) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan ContentPack
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create
+	result, err := r.installPack(ctx, plan)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error installing content pack",
+			"Could not install content pack: "+err.Error(),
+		)
+		return
+	}
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourceContentPack) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state ContentPack
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	pack, err := r.getInstalledPack(ctx, state.Account, state.PackId.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting content pack",
+			"Could not get content pack: "+err.Error(),
+		)
+		return
+	}
+	if pack == nil {
+		log.Println("Content pack not installed")
+		// Remove resource from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := contentPackFromAPI(pack, state)
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourceContentPack) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan ContentPack
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state ContentPack
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update, only reinstalling when the installed version or zip changes
+	result := plan
+	result.Id = state.Id
+	if !plan.InstalledVersion.Equal(state.InstalledVersion) || !plan.FileHash.Equal(state.FileHash) {
+		var err error
+		result, err = r.installPack(ctx, plan)
+		if err != nil {
+			log.Println(err.Error())
+			resp.Diagnostics.AddError(
+				"Error updating content pack",
+				"Could not update content pack: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourceContentPack) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state ContentPack
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Uninstall
+	_, err := r.p.doRequest(ctx, "POST", accountPath(state.Account, "/contentpacks/installed/delete"), map[string]interface{}{"IDs": []string{state.PackId.Value}}, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error uninstalling content pack",
+			"Could not uninstall content pack: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourceContentPack) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	accname := strings.SplitN(req.ID, ".", 2)
+	var packId string
+	account := types.String{Null: true}
+	if len(accname) == 1 {
+		packId = req.ID
+	} else {
+		account = types.String{Value: accname[0]}
+		packId = accname[1]
+	}
+	pack, err := r.getInstalledPack(ctx, account, packId)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing content pack",
+			"Could not import content pack: "+err.Error(),
+		)
+		return
+	}
+	if pack == nil {
+		resp.Diagnostics.AddError(
+			"Content pack not found",
+			fmt.Sprintf("Could not find installed content pack: %s", packId),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := contentPackFromAPI(pack, ContentPack{
+		Version:  types.String{Null: true},
+		File:     types.String{Null: true},
+		FileHash: types.String{Null: true},
+		Account:  account,
+	})
+	// an imported pack is pinned to the version it has installed
+	result.Version = result.InstalledVersion
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
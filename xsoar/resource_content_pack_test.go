@@ -0,0 +1,164 @@
+package xsoar
+
+import (
+	"archive/zip"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeMockPackZip writes a pack zip holding the metadata of a pack at the given version
+func writeMockPackZip(t *testing.T, name string, packId string, version string) {
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	metadata, err := w.Create(packId + "/metadata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = metadata.Write([]byte(`{"name":"` + packId + `","currentVersion":"` + version + `"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestContentPack_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	marketplace := map[string]interface{}{"id": "MockPack", "currentVersion": "1.2.0"}
+	m.putObject("", "marketplace", marketplace)
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		marketplace["currentVersion"] = "1.2.0"
+		config := map[string]interface{}{
+			"pack_id": "MockPack",
+		}
+		importId := "MockPack"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".MockPack"
+		}
+		r := tf.resource("xsoar_content_pack")
+		r.mustApply(config)
+		if pack := m.object(acc, "packs", "MockPack"); pack == nil || pack["currentVersion"] != "1.2.0" {
+			t.Fatalf("pack was not installed in account %q: %v", acc, pack)
+		}
+
+		// the latest version follows the marketplace
+		marketplace["currentVersion"] = "1.3.0"
+		r.mustApply(config)
+		if r.attrString("installed_version") != "1.3.0" || m.object(acc, "packs", "MockPack")["currentVersion"] != "1.3.0" {
+			t.Fatalf("pack was not upgraded, installed %s", r.attrString("installed_version"))
+		}
+
+		// a pinned version stays put
+		config["version"] = "1.2.0"
+		r.mustApply(config)
+		marketplace["currentVersion"] = "1.4.0"
+		r.mustApply(config)
+		if r.attrString("installed_version") != "1.2.0" || m.object(acc, "packs", "MockPack")["currentVersion"] != "1.2.0" {
+			t.Fatalf("pinned pack was not kept at 1.2.0, installed %s", r.attrString("installed_version"))
+		}
+
+		r.mustImport(importId)
+		r.mustDestroy()
+		if m.object(acc, "packs", "MockPack") != nil {
+			t.Fatal("found installed pack when none was expected")
+		}
+	}
+}
+
+func TestContentPack_mockUpload(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+	file := filepath.Join(t.TempDir(), "MockUploadPack.zip")
+	writeMockPackZip(t, file, "MockUploadPack", "0.1.0")
+
+	config := map[string]interface{}{
+		"pack_id": "MockUploadPack",
+		"file":    file,
+	}
+	r := tf.resource("xsoar_content_pack")
+	r.mustApply(config)
+	if r.attrString("installed_version") != "0.1.0" {
+		t.Fatalf("expected the uploaded pack at 0.1.0, got %s", r.attrString("installed_version"))
+	}
+
+	// a changed zip is uploaded again
+	writeMockPackZip(t, file, "MockUploadPack", "0.2.0")
+	r.mustApply(config)
+	if r.attrString("installed_version") != "0.2.0" || m.object("", "packs", "MockUploadPack")["currentVersion"] != "0.2.0" {
+		t.Fatalf("changed pack zip was not uploaded, installed %s", r.attrString("installed_version"))
+	}
+
+	// the version of an uploaded pack comes from its zip
+	config["version"] = "1.0.0"
+	if err := r.apply(config); err == nil {
+		t.Fatal("expected an error setting both version and file")
+	}
+	delete(config, "version")
+	r.mustDestroy()
+	if m.object("", "packs", "MockUploadPack") != nil {
+		t.Fatal("found installed pack when none was expected")
+	}
+}
+
+func TestContentPack_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.putObject("", "marketplace", map[string]interface{}{"id": "MockPack", "currentVersion": "1.2.0"})
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{"pack_id": "MockPack", "version": "1.2.0"}
+
+	m.fail("POST", "contentpacks/marketplace/install", http.StatusInternalServerError)
+	r := tf.resource("xsoar_content_pack")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Could not install content pack") {
+		t.Fatalf("expected the server error to fail the install, got %v", err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("a pack that failed to install was stored in state")
+	}
+	m.fail("POST", "contentpacks/marketplace/install", 0)
+
+	// the latest version cannot be planned while the marketplace fails
+	m.fail("GET", "contentpacks/marketplace/*", http.StatusBadGateway)
+	if err = r.planOnly(map[string]interface{}{"pack_id": "MockPack"}); err == nil || !strings.Contains(err.Error(), "Could not get the latest version") {
+		t.Fatalf("expected the server error to fail the plan, got %v", err)
+	}
+	m.fail("GET", "contentpacks/marketplace/*", 0)
+
+	r.mustApply(config)
+	m.fail("GET", "contentpacks/metadata/installed", http.StatusInternalServerError)
+	if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get content pack") {
+		t.Fatalf("expected the server error to fail the read, got %v", err)
+	}
+	m.fail("GET", "contentpacks/metadata/installed", 0)
+
+	// a pack uninstalled outside of terraform is removed from state and cannot be imported
+	m.removeObject("", "packs", "MockPack")
+	if err = r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the uninstalled pack to be removed from state")
+	}
+	if err = r.importState("MockPack"); err == nil || !strings.Contains(err.Error(), "Could not find installed content pack") {
+		t.Fatalf("expected importing a missing pack to fail, got %v", err)
+	}
+
+	config["version"] = ""
+	if err = r.apply(config); err == nil || !strings.Contains(err.Error(), "Invalid pack version") {
+		t.Fatalf("expected an error for an empty version, got %v", err)
+	}
+}
@@ -0,0 +1,376 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// credentialRequest builds the credential sent to XSOAR from the plan
+func credentialRequest(plan Credential, id string) map[string]interface{} {
+	credential := map[string]interface{}{
+		"name": plan.Name.Value,
+	}
+	if len(id) > 0 {
+		credential["id"] = id
+		// -1 overwrites whatever version is stored on the server
+		credential["version"] = -1
+	}
+	strs := map[string]types.String{
+		"user":        plan.User,
+		"password":    plan.Password,
+		"certificate": plan.Certificate,
+		"workgroup":   plan.Workgroup,
+	}
+	for key, value := range strs {
+		if !value.Null && !value.Unknown {
+			credential[key] = value.Value
+		}
+	}
+	return credential
+}
+
+// credentialFromAPI maps a credential returned by XSOAR onto the resource, the password and certificate
+// are never returned
+func credentialFromAPI(credential map[string]interface{}, prior Credential) Credential {
+	str := func(key string) string {
+		v, _ := credential[key].(string)
+		return v
+	}
+	return Credential{
+		Name:        types.String{Value: str("name")},
+		Id:          types.String{Value: str("id")},
+		User:        optionalString(str("user"), prior.User),
+		Password:    prior.Password,
+		Certificate: prior.Certificate,
+		Workgroup:   optionalString(str("workgroup"), prior.Workgroup),
+		Account:     prior.Account,
+	}
+}
+
+type resourceCredentialType struct{}
+
+// GetSchema Resource schema
+func (r resourceCredentialType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:          types.StringType,
+				Required:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+			"id": {
+				Type:          types.StringType,
+				Computed:      true,
+				Optional:      false,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"user": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"password": {
+				Type:      types.StringType,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"certificate": {
+				Type:      types.StringType,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"workgroup": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"account": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourceCredentialType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceCredential{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type resourceCredential struct {
+	p provider
+}
+
+// getCredential returns the credential with the given field value, or nil if there is none
+func (r resourceCredential) getCredential(ctx context.Context, account types.String, field string, value string) (map[string]interface{}, error) {
+	var search struct {
+		Credentials []map[string]interface{} `json:"credentials"`
+	}
+	query := map[string]interface{}{"page": 0, "size": 500, "query": ""}
+	_, err := r.p.doRequest(ctx, "POST", accountPath(account, "/settings/credentials"), query, &search)
+	if err != nil {
+		return nil, err
+	}
+	for _, credential := range search.Credentials {
+		if credential[field] == value {
+			return credential, nil
+		}
+	}
+	return nil, nil
+}
+
+// Create a new resource
+func (r resourceCredential) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan Credential
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// XSOAR silently overwrites a credential with the same name
+	existing, err := r.getCredential(ctx, plan.Account, "name", plan.Name.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error creating credential",
+			"Could not list credentials: "+err.Error(),
+		)
+		return
+	}
+	if existing != nil {
+		resp.Diagnostics.AddError(
+			"Error creating credential",
+			fmt.Sprintf("A credential named %s already exists, import it instead.", plan.Name.Value),
+		)
+		return
+	}
+
+	// Create
+	var credential map[string]interface{}
+	httpResponse, err := r.p.doRequest(ctx, "PUT", accountPath(plan.Account, "/settings/credentials"), credentialRequest(plan, ""), &credential)
+	if err != nil {
+		log.Println(err.Error())
+		if httpResponse != nil {
+			log.Println(httpResponse.Status)
+		}
+		resp.Diagnostics.AddError(
+			"Error creating credential",
+			"Could not create credential: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := credentialFromAPI(credential, plan)
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourceCredential) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Credential
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	credential, err := r.getCredential(ctx, state.Account, "id", state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting credential",
+			"Could not get credential: "+err.Error(),
+		)
+		return
+	}
+	if credential == nil {
+		log.Println("Credential not found")
+		// Remove resource from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := credentialFromAPI(credential, state)
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourceCredential) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan Credential
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Credential
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update
+	var credential map[string]interface{}
+	httpResponse, err := r.p.doRequest(ctx, "PUT", accountPath(plan.Account, "/settings/credentials"), credentialRequest(plan, state.Id.Value), &credential)
+	if err != nil {
+		log.Println(err.Error())
+		if httpResponse != nil {
+			log.Println(httpResponse.Status)
+		}
+		resp.Diagnostics.AddError(
+			"Error updating credential",
+			"Could not update credential: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := credentialFromAPI(credential, plan)
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourceCredential) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state Credential
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete
+	_, err := r.p.doRequest(ctx, "POST", accountPath(state.Account, "/settings/credentials/delete"), map[string]interface{}{"ids": []string{state.Id.Value}}, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error deleting credential",
+			"Could not delete credential: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourceCredential) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	accname := strings.Split(req.ID, ".")
+	var name string
+	account := types.String{Null: true}
+	if len(accname) == 1 {
+		name = req.ID
+	} else {
+		account = types.String{Value: accname[0]}
+		name = accname[1]
+	}
+	credential, err := r.getCredential(ctx, account, "name", name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing credential",
+			"Could not import credential: "+err.Error(),
+		)
+		return
+	}
+	if credential == nil {
+		resp.Diagnostics.AddError(
+			"Credential not found",
+			fmt.Sprintf("Could not find credential: %s", name),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := credentialFromAPI(credential, Credential{
+		User:        types.String{Null: true},
+		Password:    types.String{Null: true},
+		Certificate: types.String{Null: true},
+		Workgroup:   types.String{Null: true},
+		Account:     account,
+	})
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,101 @@
+package xsoar
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCredential_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{
+			"name":     "mockcredential",
+			"user":     "svc-mock",
+			"password": "hunter2",
+		}
+		importId := "mockcredential"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".mockcredential"
+		}
+		r := tf.resource("xsoar_credential")
+		r.mustApply(config)
+		id := r.attrString("id")
+		credential := m.object(acc, "credentials", id)
+		if credential == nil {
+			t.Fatalf("credential %s was not created in account %q", id, acc)
+		}
+		if credential["password"] != "hunter2" {
+			t.Fatalf("password was not sent, got %v", credential["password"])
+		}
+
+		config["password"] = "correct horse"
+		config["workgroup"] = "MOCK"
+		r.mustApply(config)
+		if r.attrString("id") != id {
+			t.Fatal("credential was replaced when it should have been updated")
+		}
+		credential = m.object(acc, "credentials", id)
+		if credential["password"] != "correct horse" || credential["workgroup"] != "MOCK" {
+			t.Fatalf("credential was not updated: %v", credential)
+		}
+
+		// a credential with the same name is not silently overwritten
+		duplicate := tf.resource("xsoar_credential")
+		if err := duplicate.apply(config); err == nil {
+			t.Fatal("expected an error creating a duplicate credential")
+		}
+
+		r.mustImport(importId, "password")
+		r.mustDestroy()
+		if m.object(acc, "credentials", id) != nil {
+			t.Fatal("found credential when none was expected")
+		}
+	}
+}
+
+func TestCredential_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{"name": "mockcredential", "user": "svc-mock", "password": "hunter2"}
+
+	m.fail("PUT", "settings/credentials", http.StatusInternalServerError)
+	r := tf.resource("xsoar_credential")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Could not create credential") {
+		t.Fatalf("expected the server error to fail the create, got %v", err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("a credential that failed to create was stored in state")
+	}
+	m.fail("PUT", "settings/credentials", 0)
+
+	r.mustApply(config)
+	m.fail("POST", "settings/credentials", http.StatusInternalServerError)
+	if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get credential") {
+		t.Fatalf("expected the server error to fail the read, got %v", err)
+	}
+	m.fail("POST", "settings/credentials", 0)
+
+	// a credential deleted outside of terraform is removed from state and cannot be imported
+	m.removeObject("", "credentials", r.attrString("id"))
+	if err = r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the removed credential to be removed from state")
+	}
+	if err = r.importState("mockcredential"); err == nil || !strings.Contains(err.Error(), "Could not find credential") {
+		t.Fatalf("expected importing a missing credential to fail, got %v", err)
+	}
+
+	if err = r.apply(map[string]interface{}{"user": "svc-mock", "password": "hunter2"}); err == nil {
+		t.Fatal("expected an error for a missing name")
+	}
+}
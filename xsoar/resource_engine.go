@@ -0,0 +1,682 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+var engineInstallerTypes = []string{"sh", "deb", "rpm"}
+
+// engineInstallCommands returns the commands installing and purging the d1 engine service with the installer of the
+// type at the path
+func engineInstallCommands(installerType string, installer string) (string, string) {
+	switch installerType {
+	case "deb":
+		return "sudo dpkg -i " + shellQuote(installer), "sudo dpkg -P d1"
+	case "rpm":
+		return "sudo rpm -i " + shellQuote(installer), "sudo rpm -e d1"
+	}
+	return "sudo " + shellQuote(installer) + " -- -y", "sudo " + shellQuote(installer) + " -- -purge -y"
+}
+
+type resourceEngineType struct{}
+
+// GetSchema Resource schema
+func (r resourceEngineType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:          types.StringType,
+				Required:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+			"id": {
+				Type:          types.StringType,
+				Computed:      true,
+				Optional:      false,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"installer_type": {
+				Type:          types.StringType,
+				Optional:      true,
+				Computed:      true,
+				Validators:    []tfsdk.AttributeValidator{isOneOf{values: engineInstallerTypes}},
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown(), tfsdk.RequiresReplace()),
+			},
+			"load_balancing_group": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"server_url": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"ssh_user": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"ssh_key": {
+				Type:      types.StringType,
+				Required:  true,
+				Sensitive: true,
+			},
+			"host_key": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"host_key_fingerprint": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"known_hosts_file": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"host_key_checking": {
+				Type:       types.StringType,
+				Optional:   true,
+				Validators: []tfsdk.AttributeValidator{isOneOf{values: hostKeyCheckingModes}},
+			},
+			"observed_host_key": {
+				Type:          types.StringType,
+				Computed:      true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"installation_timeout": {
+				Type:               types.Int64Type,
+				Optional:           true,
+				DeprecationMessage: "Use the create timeout of the timeouts block instead.",
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourceEngineType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceEngine{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type resourceEngine struct {
+	p provider
+}
+
+func (r resourceEngine) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config Engine
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateSSHSettings(path.Empty(), config.SSHKey, types.String{Null: true}, types.String{Null: true}, types.Bool{Null: true}, config.HostKey, config.HostKeyFingerprint, &resp.Diagnostics)
+	if !config.LoadBalancingGroup.Null && !config.LoadBalancingGroup.Unknown && len(config.LoadBalancingGroup.Value) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("load_balancing_group"),
+			"Invalid load-balancing group",
+			"The load-balancing group must not be empty, leave it unset to keep the engine out of any group.",
+		)
+	}
+}
+
+// engineTarget returns how the machine of an engine is connected to over ssh
+func engineTarget(engine Engine) sshTarget {
+	return sshTarget{
+		address:  engine.ServerUrl.Value,
+		user:     engine.SSHUser.Value,
+		key:      engine.SSHKey.Value,
+		hostKeys: newHostKeyVerifier(engine.HostKey, engine.HostKeyFingerprint, engine.KnownHostsFile, engine.HostKeyChecking, engine.ObservedHostKey),
+	}
+}
+
+// getEngine returns the engine with the given field value, or nil if there is none
+func (r resourceEngine) getEngine(ctx context.Context, field string, value string) (map[string]interface{}, error) {
+	var search struct {
+		Engines []map[string]interface{} `json:"engines"`
+	}
+	_, err := r.p.doRequest(ctx, "GET", "/engines", nil, &search)
+	if err != nil {
+		return nil, err
+	}
+	for _, engine := range search.Engines {
+		if engine[field] == value {
+			return engine, nil
+		}
+	}
+	return nil, nil
+}
+
+// listEngineGroups returns the load-balancing groups of engines
+func (r resourceEngine) listEngineGroups(ctx context.Context) ([]map[string]interface{}, error) {
+	var groups []map[string]interface{}
+	_, err := r.p.doRequest(ctx, "GET", "/engines/groups", nil, &groups)
+	return groups, err
+}
+
+// engineGroup returns the name of the load-balancing group the engine is in, or "" if there is none
+func (r resourceEngine) engineGroup(ctx context.Context, id string) (string, error) {
+	groups, err := r.listEngineGroups(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, group := range groups {
+		for _, engineId := range interfaceSlice(group["engineIds"]) {
+			if engineId == id {
+				name, _ := group["name"].(string)
+				return name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// setEngineGroup moves the engine into the named load-balancing group, creating it if needed, or out of any group
+// when name is empty. Groups left without engines are deleted.
+func (r resourceEngine) setEngineGroup(ctx context.Context, id string, name string) error {
+	groups, err := r.listEngineGroups(ctx)
+	if err != nil {
+		return err
+	}
+	var target map[string]interface{}
+	for _, group := range groups {
+		groupName, _ := group["name"].(string)
+		groupId, _ := group["id"].(string)
+		var engineIds []interface{}
+		member := false
+		for _, engineId := range interfaceSlice(group["engineIds"]) {
+			if engineId == id {
+				member = true
+			} else {
+				engineIds = append(engineIds, engineId)
+			}
+		}
+		if groupName == name {
+			target = group
+			continue
+		}
+		if !member {
+			continue
+		}
+		log.Printf("removing engine %s from load-balancing group %s", id, groupName)
+		if len(engineIds) == 0 {
+			_, err = r.p.doRequest(ctx, "DELETE", "/engines/groups/"+url.PathEscape(groupId), nil, nil)
+		} else {
+			group["engineIds"] = engineIds
+			_, err = r.p.doRequest(ctx, "POST", "/engines/groups", group, nil)
+		}
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+	if len(name) == 0 {
+		return nil
+	}
+	if target == nil {
+		target = map[string]interface{}{"name": name}
+	}
+	engineIds := interfaceSlice(target["engineIds"])
+	for _, engineId := range engineIds {
+		if engineId == id {
+			return nil
+		}
+	}
+	log.Printf("adding engine %s to load-balancing group %s", id, name)
+	target["engineIds"] = append(engineIds, id)
+	_, err = r.p.doRequest(ctx, "POST", "/engines/groups", target, nil)
+	return err
+}
+
+// uploadInstaller downloads the engine installer and uploads it to the machine, so that the API key never reaches
+// the machine
+func (r resourceEngine) uploadInstaller(ctx context.Context, installer *remoteInstaller, id string, installerType string) error {
+	var content []byte
+	_, err := r.p.doRequest(ctx, http.MethodGet, "/engines/download/"+url.PathEscape(id)+"/"+installerType, nil, &content)
+	if err != nil {
+		return err
+	}
+	return installer.upload(ctx, content)
+}
+
+// deleteEngine takes the engine out of its load-balancing group and deletes it from the main server
+func (r resourceEngine) deleteEngine(ctx context.Context, id string) error {
+	err := r.setEngineGroup(ctx, id, "")
+	if err != nil {
+		return fmt.Errorf("could not remove engine from load-balancing group: %w", err)
+	}
+	_, err = r.p.doRequest(ctx, "POST", "/engines/delete", map[string]interface{}{"ids": []string{id}}, nil)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// engineFromAPI maps an engine returned by XSOAR onto the resource
+func engineFromAPI(engine map[string]interface{}, group string, prior Engine) Engine {
+	name, _ := engine["name"].(string)
+	id, _ := engine["id"].(string)
+	installerType, _ := engine["type"].(string)
+	result := Engine{
+		Name:                types.String{Value: name},
+		Id:                  types.String{Value: id},
+		InstallerType:       types.String{Value: installerType},
+		LoadBalancingGroup:  optionalString(group, prior.LoadBalancingGroup),
+		ServerUrl:           prior.ServerUrl,
+		SSHUser:             prior.SSHUser,
+		SSHKey:              prior.SSHKey,
+		HostKey:             prior.HostKey,
+		HostKeyFingerprint:  prior.HostKeyFingerprint,
+		KnownHostsFile:      prior.KnownHostsFile,
+		HostKeyChecking:     prior.HostKeyChecking,
+		ObservedHostKey:     prior.ObservedHostKey,
+		InstallationTimeout: prior.InstallationTimeout,
+	}
+	if len(installerType) == 0 {
+		result.InstallerType = prior.InstallerType
+	}
+	return result
+}
+
+// Create a new resource
+func (r resourceEngine) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withCreateTimeout(ctx, req.Plan, "installation_timeout", installTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan Engine
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.InstallerType.Null || plan.InstallerType.Unknown {
+		plan.InstallerType = types.String{Value: "sh"}
+	}
+
+	// 1) connect to the engine machine over ssh
+	target := engineTarget(plan)
+	conn, err := dialSSH(ctx, target, nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating engine",
+			fmt.Sprintf("Error creating engine: %s", err.Error()),
+		)
+		return
+	}
+	defer conn.Close()
+	// the installer is removed last, a failed install being purged with it, and a failed upload may leave part of it
+	installer, err := newRemoteInstaller(ctx, conn, "d1_installer."+plan.InstallerType.Value)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating engine",
+			"Could not prepare engine installer: "+err.Error(),
+		)
+		return
+	}
+	defer installer.remove()
+	install, purge := engineInstallCommands(plan.InstallerType.Value, installer.path)
+
+	// 2) register the engine on the main server
+	var engine map[string]interface{}
+	body := map[string]interface{}{"name": plan.Name.Value, "type": plan.InstallerType.Value}
+	_, err = r.p.doRequest(ctx, "POST", "/engines/create", body, &engine)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error creating engine",
+			"Could not register engine: "+err.Error(),
+		)
+		return
+	}
+	id, _ := engine["id"].(string)
+	// an engine that fails to install is removed again, so that creating it can be retried
+	installing := false
+	defer func() {
+		if !resp.Diagnostics.HasError() {
+			return
+		}
+		// the context of the create may be done already
+		cleanupCtx, cancelCleanup := context.WithTimeout(context.Background(), time.Minute)
+		defer cancelCleanup()
+		if installing {
+			if err := runSSHCommand(cleanupCtx, conn, purge); err != nil {
+				log.Println("could not purge engine: " + err.Error())
+			}
+		}
+		if err := r.deleteEngine(cleanupCtx, id); err != nil {
+			resp.Diagnostics.AddWarning(
+				"Error removing engine",
+				fmt.Sprintf("Could not remove the engine registered before the failure, delete engine %s before creating it again: %s", plan.Name.Value, err.Error()),
+			)
+		}
+	}()
+
+	// 3) upload and run the installer
+	log.Println("Downloading engine installer")
+	err = r.uploadInstaller(ctx, installer, id, plan.InstallerType.Value)
+	if err == nil {
+		err = installer.verify(ctx)
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error downloading installer",
+			"Could not download engine installer: "+err.Error(),
+		)
+		return
+	}
+	log.Println("Executing engine install")
+	installing = true
+	err = runSSHCommand(ctx, conn, install)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error running installer",
+			"Could not run engine installer: "+err.Error(),
+		)
+		return
+	}
+
+	// 4) wait for the engine to connect
+	log.Println("Waiting for engine to connect")
+	timeout := untilDeadline(ctx)
+	if !plan.InstallationTimeout.Null {
+		timeout = time.Duration(plan.InstallationTimeout.Value) * time.Second
+	}
+	err = resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		var getErr error
+		engine, getErr = r.getEngine(ctx, "id", id)
+		if getErr != nil {
+			return resource.NonRetryableError(getErr)
+		}
+		if connected, _ := engine["connected"].(bool); !connected {
+			return resource.RetryableError(fmt.Errorf("engine %s has not connected", plan.Name.Value))
+		}
+		return nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting engine",
+			"Could not get connected engine before timeout: "+err.Error(),
+		)
+		return
+	}
+
+	// 5) join the load-balancing group
+	if !plan.LoadBalancingGroup.Null {
+		err = r.setEngineGroup(ctx, id, plan.LoadBalancingGroup.Value)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error setting load-balancing group",
+				"Could not add engine to load-balancing group: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// Map response body to resource schema attribute
+	result := engineFromAPI(engine, plan.LoadBalancingGroup.Value, plan)
+	result.ObservedHostKey = types.String{Value: target.hostKeys.observed}
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourceEngine) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Engine
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	engine, err := r.getEngine(ctx, "id", state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting engine",
+			"Could not get engine: "+err.Error(),
+		)
+		return
+	}
+	if engine == nil {
+		log.Println("Engine not found")
+		// Remove resource from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	group, err := r.engineGroup(ctx, state.Id.Value)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting engine",
+			"Could not get load-balancing groups: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := engineFromAPI(engine, group, state)
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourceEngine) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan Engine
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Engine
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only the load-balancing group is changed on the server, the other attributes in place are used over ssh
+	if !plan.LoadBalancingGroup.Equal(state.LoadBalancingGroup) {
+		err := r.setEngineGroup(ctx, state.Id.Value, plan.LoadBalancingGroup.Value)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error setting load-balancing group",
+				"Could not move engine to load-balancing group: "+err.Error(),
+			)
+			return
+		}
+	}
+	result := plan
+	result.Id = state.Id
+	result.InstallerType = state.InstallerType
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourceEngine) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state Engine
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 1) purge the engine from the machine
+	installerType := state.InstallerType.Value
+	if installerType != "deb" && installerType != "rpm" {
+		installerType = "sh"
+	}
+	if !state.ServerUrl.Null {
+		conn, err := dialSSH(ctx, engineTarget(state), nil)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error deleting engine",
+				"Could not delete engine: "+err.Error(),
+			)
+			return
+		}
+		defer conn.Close()
+		_, purge := engineInstallCommands(installerType, "")
+		// the shell installer also uninstalls, and is not kept on the machine once it has run
+		if installerType == "sh" {
+			installer, err := newRemoteInstaller(ctx, conn, "d1_installer."+installerType)
+			if err == nil {
+				defer installer.remove()
+				err = r.uploadInstaller(ctx, installer, state.Id.Value, installerType)
+			}
+			if err == nil {
+				err = installer.verify(ctx)
+			}
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error downloading installer",
+					"Could not download engine installer: "+err.Error(),
+				)
+				return
+			}
+			_, purge = engineInstallCommands(installerType, installer.path)
+		}
+		err = runSSHCommand(ctx, conn, purge)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error running installer",
+				"Could not purge engine: "+err.Error(),
+			)
+			return
+		}
+	}
+
+	// 2) leave the load-balancing group and delete the engine from main
+	err := r.deleteEngine(ctx, state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error deleting engine",
+			"Could not delete engine: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourceEngine) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	engine, err := r.getEngine(ctx, "name", req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing engine",
+			"Could not import engine: "+err.Error(),
+		)
+		return
+	}
+	if engine == nil {
+		resp.Diagnostics.AddError(
+			"Engine not found",
+			fmt.Sprintf("Could not find engine: %s", req.ID),
+		)
+		return
+	}
+	id, _ := engine["id"].(string)
+	group, err := r.engineGroup(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing engine",
+			"Could not get load-balancing groups: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := engineFromAPI(engine, group, Engine{
+		InstallerType:       types.String{Value: "sh"},
+		LoadBalancingGroup:  types.String{Null: true},
+		ServerUrl:           types.String{Null: true},
+		SSHUser:             types.String{Null: true},
+		SSHKey:              types.String{Null: true},
+		HostKey:             types.String{Null: true},
+		HostKeyFingerprint:  types.String{Null: true},
+		KnownHostsFile:      types.String{Null: true},
+		HostKeyChecking:     types.String{Null: true},
+		ObservedHostKey:     types.String{Null: true},
+		InstallationTimeout: types.Int64{Null: true},
+	})
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
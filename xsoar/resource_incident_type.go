@@ -0,0 +1,448 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// incidentTypeRequest builds the incident type object sent to XSOAR from the plan
+func incidentTypeRequest(ctx context.Context, plan IncidentType, id string) map[string]interface{} {
+	incidentType := map[string]interface{}{
+		"name": plan.Name.Value,
+		// -1 overwrites whatever version is stored on the server
+		"version": -1,
+	}
+	if len(id) > 0 {
+		incidentType["id"] = id
+	}
+	strs := map[string]types.String{
+		"color":         plan.Color,
+		"playbookId":    plan.PlaybookId,
+		"closureScript": plan.ClosureScript,
+		"layout":        plan.Layout,
+	}
+	for key, value := range strs {
+		if !value.Null && !value.Unknown {
+			incidentType[key] = value.Value
+		}
+	}
+	ints := map[string]types.Int64{
+		"sla":         plan.Sla,
+		"slaReminder": plan.SlaReminder,
+		"hours":       plan.Hours,
+		"days":        plan.Days,
+		"weeks":       plan.Weeks,
+	}
+	for key, value := range ints {
+		if !value.Null && !value.Unknown {
+			incidentType[key] = value.Value
+		}
+	}
+	if !plan.Autorun.Null && !plan.Autorun.Unknown {
+		incidentType["autorun"] = plan.Autorun.Value
+	}
+	if !plan.Disabled.Null && !plan.Disabled.Unknown {
+		incidentType["disabled"] = plan.Disabled.Value
+	}
+	if !plan.AutoExtract.Null && !plan.AutoExtract.Unknown {
+		incidentType["extractSettings"] = map[string]interface{}{"mode": plan.AutoExtract.Value}
+	}
+	if !plan.PropagationLabels.Null && !plan.PropagationLabels.Unknown {
+		var props []string
+		plan.PropagationLabels.ElementsAs(ctx, &props, true)
+		incidentType["propagationLabels"] = props
+	}
+	return incidentType
+}
+
+// incidentTypeFromAPI maps an incident type returned by XSOAR onto the resource
+func incidentTypeFromAPI(incidentType map[string]interface{}, account types.String) IncidentType {
+	str := func(key string) types.String {
+		if v, ok := incidentType[key].(string); ok {
+			return types.String{Value: v}
+		}
+		return types.String{Value: ""}
+	}
+	num := func(key string) types.Int64 {
+		if v, ok := incidentType[key].(float64); ok {
+			return types.Int64{Value: int64(v)}
+		}
+		return types.Int64{Value: 0}
+	}
+	autorun, _ := incidentType["autorun"].(bool)
+	disabled, _ := incidentType["disabled"].(bool)
+	result := IncidentType{
+		Name:              str("name"),
+		Id:                str("id"),
+		Color:             str("color"),
+		PlaybookId:        str("playbookId"),
+		Autorun:           types.Bool{Value: autorun},
+		AutoExtract:       types.String{Null: true},
+		Sla:               num("sla"),
+		SlaReminder:       num("slaReminder"),
+		Hours:             num("hours"),
+		Days:              num("days"),
+		Weeks:             num("weeks"),
+		ClosureScript:     str("closureScript"),
+		Layout:            str("layout"),
+		Disabled:          types.Bool{Value: disabled},
+		PropagationLabels: stringSet(interfaceSlice(incidentType["propagationLabels"])),
+		Account:           account,
+	}
+	if extractSettings, ok := incidentType["extractSettings"].(map[string]interface{}); ok {
+		if mode, ok := extractSettings["mode"].(string); ok {
+			result.AutoExtract = types.String{Value: mode}
+		}
+	}
+	return result
+}
+
+type resourceIncidentTypeType struct{}
+
+// GetSchema Resource schema
+func (r resourceIncidentTypeType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:          types.StringType,
+				Required:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+			"id": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"color": {
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"playbook_id": {
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"autorun": {
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+			},
+			"auto_extract": {
+				Type:       types.StringType,
+				Optional:   true,
+				Computed:   true,
+				Validators: []tfsdk.AttributeValidator{isOneOf{values: []string{"All", "Specific", "None"}}},
+			},
+			"sla": {
+				Type:     types.Int64Type,
+				Optional: true,
+				Computed: true,
+			},
+			"sla_reminder": {
+				Type:     types.Int64Type,
+				Optional: true,
+				Computed: true,
+			},
+			"hours": {
+				Type:     types.Int64Type,
+				Optional: true,
+				Computed: true,
+			},
+			"days": {
+				Type:     types.Int64Type,
+				Optional: true,
+				Computed: true,
+			},
+			"weeks": {
+				Type:     types.Int64Type,
+				Optional: true,
+				Computed: true,
+			},
+			"closure_script": {
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"layout": {
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"disabled": {
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+			},
+			"propagation_labels": {
+				Type:     types.SetType{ElemType: types.StringType},
+				Optional: true,
+				Computed: true,
+			},
+			"account": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourceIncidentTypeType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceIncidentType{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type resourceIncidentType struct {
+	p provider
+}
+
+// getIncidentType returns the incident type with the given field value, or nil if there is none
+func (r resourceIncidentType) getIncidentType(ctx context.Context, account types.String, field string, value string) (map[string]interface{}, error) {
+	var incidentTypes []map[string]interface{}
+	_, err := r.p.doRequest(ctx, "GET", accountPath(account, "/incidenttype"), nil, &incidentTypes)
+	if err != nil {
+		return nil, err
+	}
+	for _, incidentType := range incidentTypes {
+		if incidentType[field] == value {
+			return incidentType, nil
+		}
+	}
+	return nil, nil
+}
+
+// Create a new resource
+func (r resourceIncidentType) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan IncidentType
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create
+	var incidentType map[string]interface{}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/incidenttype"), incidentTypeRequest(ctx, plan, ""), &incidentType)
+	if err != nil {
+		log.Println(err.Error())
+		if httpResponse != nil {
+			b, _ := io.ReadAll(httpResponse.Body)
+			log.Println(string(b))
+		}
+		resp.Diagnostics.AddError(
+			"Error creating incident type",
+			"Could not create incident type: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := incidentTypeFromAPI(incidentType, plan.Account)
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourceIncidentType) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state IncidentType
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	incidentType, err := r.getIncidentType(ctx, state.Account, "id", state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting incident type",
+			"Could not get incident type: "+err.Error(),
+		)
+		return
+	}
+	if incidentType == nil {
+		log.Println("Incident type not found")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := incidentTypeFromAPI(incidentType, state.Account)
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourceIncidentType) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan IncidentType
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state IncidentType
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update
+	var incidentType map[string]interface{}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/incidenttype"), incidentTypeRequest(ctx, plan, state.Id.Value), &incidentType)
+	if err != nil {
+		log.Println(err.Error())
+		if httpResponse != nil {
+			b, _ := io.ReadAll(httpResponse.Body)
+			log.Println(string(b))
+		}
+		resp.Diagnostics.AddError(
+			"Error updating incident type",
+			"Could not update incident type: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := incidentTypeFromAPI(incidentType, plan.Account)
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourceIncidentType) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state IncidentType
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete
+	_, err := r.p.doRequest(ctx, "POST", accountPath(state.Account, "/incidenttype/delete"), map[string]interface{}{"id": state.Id.Value}, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error deleting incident type",
+			"Could not delete incident type: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourceIncidentType) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	accname := strings.Split(req.ID, ".")
+	var name string
+	account := types.String{Null: true}
+	if len(accname) == 1 {
+		name = req.ID
+	} else {
+		account = types.String{Value: accname[0]}
+		name = accname[1]
+	}
+	incidentType, err := r.getIncidentType(ctx, account, "name", name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing incident type",
+			"Could not import incident type: "+err.Error(),
+		)
+		return
+	}
+	if incidentType == nil {
+		resp.Diagnostics.AddError(
+			"Incident type not found",
+			fmt.Sprintf("Could not find incident type: %s", name),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := incidentTypeFromAPI(incidentType, account)
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
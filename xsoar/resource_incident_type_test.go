@@ -0,0 +1,119 @@
+package xsoar
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIncidentType_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{
+			"name":         "Mock Phishing",
+			"playbook_id":  "Phishing Investigation",
+			"auto_extract": "Specific",
+			"days":         1,
+		}
+		importId := "Mock Phishing"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".Mock Phishing"
+		}
+		r := tf.resource("xsoar_incident_type")
+		r.mustApply(config)
+		id := r.attrString("id")
+		incidentType := m.object(acc, "incidenttypes", id)
+		if incidentType == nil {
+			t.Fatalf("incident type %s was not created in account %q", id, acc)
+		}
+		if r.attrString("color") != "#32d296" {
+			t.Fatalf("expected the server default color, got %v", r.attr("color"))
+		}
+		if mode := mockObject(incidentType["extractSettings"])["mode"]; mode != "Specific" {
+			t.Fatalf("expected auto extract mode Specific, got %v", mode)
+		}
+
+		config["color"] = "#ff0000"
+		config["closure_script"] = "CloseScript"
+		config["disabled"] = true
+		config["hours"] = 4
+		config["propagation_labels"] = []interface{}{"all"}
+		r.mustApply(config)
+		if r.attrString("id") != id {
+			t.Fatal("incident type was replaced when it should have been updated")
+		}
+		incidentType = m.object(acc, "incidenttypes", id)
+		if incidentType["color"] != "#ff0000" || incidentType["disabled"] != true || incidentType["hours"] != float64(4) {
+			t.Fatalf("incident type was not updated, got %v", incidentType)
+		}
+
+		// classifiers reference incident types by name
+		classifier := tf.resource("xsoar_classifier")
+		classifierConfig := map[string]interface{}{
+			"name":                  "mockclassifier",
+			"default_incident_type": r.attrString("name"),
+		}
+		if acc != "" {
+			classifierConfig["account"] = acc
+		}
+		classifier.mustApply(classifierConfig)
+		classifier.mustDestroy()
+
+		r.mustImport(importId)
+		r.mustDestroy()
+		if m.object(acc, "incidenttypes", id) != nil {
+			t.Fatal("found incident type when none was expected")
+		}
+	}
+
+	r := tf.resource("xsoar_incident_type")
+	if err := r.apply(map[string]interface{}{"name": "Mock", "auto_extract": "Some"}); err == nil {
+		t.Fatal("expected an error for an invalid auto extract mode")
+	}
+}
+
+func TestIncidentType_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{"name": "Mock Phishing"}
+
+	m.fail("POST", "incidenttype", http.StatusInternalServerError)
+	r := tf.resource("xsoar_incident_type")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Could not create incident type") {
+		t.Fatalf("expected the server error to fail the create, got %v", err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("an incident type that failed to create was stored in state")
+	}
+	m.fail("POST", "incidenttype", 0)
+
+	r.mustApply(config)
+	m.fail("GET", "incidenttype", http.StatusForbidden)
+	if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get incident type") {
+		t.Fatalf("expected the server error to fail the read, got %v", err)
+	}
+	m.fail("GET", "incidenttype", 0)
+
+	// an incident type deleted outside of terraform is removed from state and cannot be imported
+	m.removeObject("", "incidenttypes", r.attrString("id"))
+	if err = r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the removed incident type to be removed from state")
+	}
+	if err = r.importState("Mock Phishing"); err == nil || !strings.Contains(err.Error(), "Could not find incident type") {
+		t.Fatalf("expected importing a missing incident type to fail, got %v", err)
+	}
+
+	if err = r.apply(map[string]interface{}{"auto_extract": "All"}); err == nil {
+		t.Fatal("expected an error for a missing name")
+	}
+}
@@ -0,0 +1,61 @@
+package xsoar
+
+import (
+	"testing"
+)
+
+func TestIndicatorField_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{
+			"name":             "Mock Owner",
+			"cli_name":         "mockowner",
+			"type":             "shortText",
+			"description":      "Owner of the indicator",
+			"associated_types": []interface{}{"IP", "Domain"},
+		}
+		importId := "mockowner"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".mockowner"
+		}
+		r := tf.resource("xsoar_indicator_field")
+		r.mustApply(config)
+		id := r.attrString("id")
+		field := m.object(acc, "fields", id)
+		if field == nil {
+			t.Fatalf("field %s was not created in account %q", id, acc)
+		}
+		if field["group"] != float64(indicatorFieldGroup) {
+			t.Fatalf("expected indicator field group, got %v", field["group"])
+		}
+
+		config["unsearchable"] = true
+		r.mustApply(config)
+		if m.object(acc, "fields", id)["unsearchable"] != true {
+			t.Fatal("field was not updated")
+		}
+
+		// an incident field with the same cli name is a different field
+		incidentField := tf.resource("xsoar_incident_field")
+		incidentConfig := map[string]interface{}{"name": "Mock Owner", "cli_name": "mockowner", "type": "shortText"}
+		if acc != "" {
+			incidentConfig["account"] = acc
+		}
+		incidentField.mustApply(incidentConfig)
+		if incidentField.attrString("id") == id {
+			t.Fatal("incident and indicator fields share an id")
+		}
+
+		r.mustImport(importId)
+		incidentField.mustDestroy()
+		r.mustDestroy()
+		if m.object(acc, "fields", id) != nil {
+			t.Fatal("found field when none was expected")
+		}
+	}
+}
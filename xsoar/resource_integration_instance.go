@@ -3,6 +3,7 @@ package xsoar
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
@@ -10,8 +11,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/badarsebard/xsoar-sdk-go/openapi"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
@@ -31,8 +34,10 @@ func getIntegrationsFromAPIResponse(ctx context.Context, integration map[string]
 				integrationConfig = s.Index(i).Interface().(map[string]interface{})
 				nameconf, ok := integrationConfig["name"].(string)
 				if ok {
-					_, ok := secretConfigs[nameconf]
-					if !ok {
+					display, _ := integrationConfig["display"].(string)
+					ok := isHeldParameter(secretConfigs, nameconf, display)
+					// parameters referencing a stored credential are held in credentials
+					if !ok && parameterCredential(integrationConfig["value"]) == "" {
 						integrationConfigs[nameconf] = integrationConfig["value"]
 					}
 				} else {
@@ -53,6 +58,514 @@ func getIntegrationsFromAPIResponse(ctx context.Context, integration map[string]
 	return string(integrationConfigsJson), nil
 }
 
+// credentialsParameterType is the type of the integration parameters that can take a credential from the
+// credentials store
+const credentialsParameterType = 9
+
+// parameterCredential returns the name of the stored credential a parameter value references, or "" if there is none
+func parameterCredential(value any) string {
+	v, _ := value.(map[string]any)
+	name, _ := v["credential"].(string)
+	return name
+}
+
+// addInstanceCredentials adds the parameters referencing a stored credential to the configs, XSOAR resolves the
+// identifier and password from the credentials store so the secret never leaves it
+func addInstanceCredentials(ctx context.Context, credentials types.Map, configs map[string]any, moduleConfiguration []interface{}) error {
+	if credentials.Null || credentials.Unknown {
+		return nil
+	}
+	var names map[string]string
+	diags := credentials.ElementsAs(ctx, &names, false)
+	if diags.HasError() {
+		return fmt.Errorf("could not read credentials: %v", diags)
+	}
+	for key, name := range names {
+		if _, ok := configs[key]; ok {
+			return fmt.Errorf("key: '%s' exists in 'credentials' and 'config_json' or 'secret_config_json'. Please choose 1", key)
+		}
+		var param map[string]interface{}
+		for _, parameter := range moduleConfiguration {
+			p := parameter.(map[string]interface{})
+			if p["display"] == key || p["name"] == key {
+				param = p
+				break
+			}
+		}
+		if param == nil {
+			return fmt.Errorf("the integration has no parameter '%s'", key)
+		}
+		if paramType, _ := param["type"].(float64); paramType != credentialsParameterType {
+			return fmt.Errorf("parameter '%s' does not accept credentials", key)
+		}
+		configs[key] = map[string]any{
+			"credential":      name,
+			"identifier":      "",
+			"password":        "",
+			"passwordChanged": false,
+		}
+	}
+	return nil
+}
+
+// getCredentialsFromAPIResponse returns the parameters of an instance that reference a stored credential
+func getCredentialsFromAPIResponse(integration map[string]any, prior types.Map) types.Map {
+	credentials := map[string]attr.Value{}
+	for _, item := range interfaceSlice(integration["data"]) {
+		param, _ := item.(map[string]interface{})
+		name, _ := param["name"].(string)
+		if credential := parameterCredential(param["value"]); len(name) > 0 && len(credential) > 0 {
+			credentials[name] = types.String{Value: credential}
+		}
+	}
+	if len(credentials) == 0 && (prior.Null || prior.Unknown) {
+		return types.Map{Null: true, ElemType: types.StringType}
+	}
+	return types.Map{Elems: credentials, ElemType: types.StringType}
+}
+
+// heldParameters returns the keys of the parameters held outside config_json and config, the secrets of
+// secret_config_json and secret_config, whose values are never read back from the API, and the common parameters
+// set by attributes of their own
+func heldParameters(secretConfigs map[string]any, instance IntegrationInstance) map[string]any {
+	held := map[string]any{}
+	for key := range secretConfigs {
+		held[key] = nil
+	}
+	for key := range instance.SecretConfig.Elems {
+		held[key] = nil
+	}
+	for attribute := range instanceParameterValues(instance) {
+		held[instanceParameters[attribute]] = nil
+	}
+	return held
+}
+
+// isHeldParameter returns whether a parameter is held outside config_json and config, by its name or display name
+func isHeldParameter(held map[string]any, name string, display string) bool {
+	if _, ok := held[name]; ok {
+		return true
+	}
+	_, ok := held[display]
+	return ok && len(display) > 0
+}
+
+// instanceParameters are the attributes setting a parameter common to the integrations that support it, by the name
+// of the parameter
+var instanceParameters = map[string]string{
+	"is_fetch":            "isFetch",
+	"incident_type":       "incidentType",
+	"fetch_interval":      "incidentFetchInterval",
+	"mirroring_direction": "mirror_direction",
+}
+
+// mirroringDirections are the values of the mirroring direction parameter
+var mirroringDirections = []string{"None", "Incoming", "Outgoing", "Incoming And Outgoing"}
+
+// instanceLogLevels are the values of integration_log_level, Off being sent as an empty level
+var instanceLogLevels = []string{"Off", "Debug", "Verbose"}
+
+// instanceParameterValues returns the values of the common parameters set on the instance, by attribute
+func instanceParameterValues(instance IntegrationInstance) map[string]any {
+	values := map[string]any{}
+	if !instance.IsFetch.Null && !instance.IsFetch.Unknown {
+		values["is_fetch"] = instance.IsFetch.Value
+	}
+	if !instance.IncidentType.Null && !instance.IncidentType.Unknown {
+		values["incident_type"] = instance.IncidentType.Value
+	}
+	if !instance.FetchInterval.Null && !instance.FetchInterval.Unknown {
+		values["fetch_interval"] = strconv.FormatInt(instance.FetchInterval.Value, 10)
+	}
+	if !instance.MirroringDirection.Null && !instance.MirroringDirection.Unknown {
+		values["mirroring_direction"] = instance.MirroringDirection.Value
+	}
+	return values
+}
+
+// addInstanceSettings adds the settings of the instance to the request, the common parameters to the configs and
+// the settings that are not parameters of the integration to the module instance
+func addInstanceSettings(plan IntegrationInstance, moduleInstance map[string]interface{}, configs map[string]any) error {
+	for attribute, value := range instanceParameterValues(plan) {
+		key := instanceParameters[attribute]
+		if _, ok := configs[key]; ok {
+			return fmt.Errorf("key: '%s' is set by '%s' and in the parameters of the instance. Please choose 1", key, attribute)
+		}
+		configs[key] = value
+	}
+	if !plan.IntegrationLogLevel.Null && !plan.IntegrationLogLevel.Unknown {
+		level := plan.IntegrationLogLevel.Value
+		if level == "Off" {
+			level = ""
+		}
+		moduleInstance["integrationLogLevel"] = level
+	}
+	if !plan.EngineGroup.Null && !plan.EngineGroup.Unknown {
+		moduleInstance["engineGroup"] = plan.EngineGroup.Value
+	}
+	for key, value := range map[string]types.Bool{
+		"isLongRunning":       plan.IsLongRunning,
+		"resetContext":        plan.ResetContext,
+		"passwordProtected":   plan.PasswordProtected,
+		"isIntegrationScript": plan.Byoi,
+		"defaultIgnore":       plan.DefaultIgnore,
+	} {
+		if !value.Null && !value.Unknown {
+			moduleInstance[key] = value.Value
+		}
+	}
+	return nil
+}
+
+// getInstanceSettings maps the settings of an instance back to result. The common parameters are only read when
+// they are set in prior, an empty prior reading all of them.
+func getInstanceSettings(integration map[string]any, prior IntegrationInstance, result *IntegrationInstance) {
+	level, _ := integration["integrationLogLevel"].(string)
+	if len(level) == 0 {
+		level = "Off"
+	}
+	result.IntegrationLogLevel = types.String{Value: level}
+	engineGroup, _ := integration["engineGroup"].(string)
+	result.EngineGroup = types.String{Value: engineGroup}
+	isLongRunning, _ := integration["isLongRunning"].(bool)
+	result.IsLongRunning = types.Bool{Value: isLongRunning}
+	resetContext, _ := integration["resetContext"].(bool)
+	result.ResetContext = types.Bool{Value: resetContext}
+	passwordProtected, _ := integration["passwordProtected"].(bool)
+	result.PasswordProtected = types.Bool{Value: passwordProtected}
+	byoi, _ := integration["isIntegrationScript"].(bool)
+	result.Byoi = types.Bool{Value: byoi}
+	defaultIgnore, _ := integration["defaultIgnore"].(bool)
+	result.DefaultIgnore = types.Bool{Value: defaultIgnore}
+
+	values := map[string]any{}
+	for _, item := range interfaceSlice(integration["data"]) {
+		param, _ := item.(map[string]interface{})
+		if name, ok := param["name"].(string); ok && param["value"] != nil {
+			values[name] = param["value"]
+		}
+	}
+	result.IsFetch = types.Bool{Null: true}
+	if value, ok := values["isFetch"]; ok && !prior.IsFetch.Null {
+		isFetch, err := strconv.ParseBool(parameterString(value))
+		result.IsFetch = types.Bool{Value: isFetch, Null: err != nil}
+	}
+	result.IncidentType = types.String{Null: true}
+	if value, ok := values["incidentType"]; ok && !prior.IncidentType.Null {
+		result.IncidentType = types.String{Value: parameterString(value)}
+	}
+	result.FetchInterval = types.Int64{Null: true}
+	if value, ok := values["incidentFetchInterval"]; ok && !prior.FetchInterval.Null {
+		interval, err := strconv.ParseInt(parameterString(value), 10, 64)
+		result.FetchInterval = types.Int64{Value: interval, Null: err != nil}
+	}
+	result.MirroringDirection = types.String{Null: true}
+	if value, ok := values["mirror_direction"]; ok && !prior.MirroringDirection.Null {
+		result.MirroringDirection = types.String{Value: parameterString(value)}
+	}
+}
+
+// parameterValue converts a value of the config or secret_config maps to the type of the integration parameter
+func parameterValue(param map[string]interface{}, value string) any {
+	paramType, _ := param["type"].(float64)
+	switch int(paramType) {
+	case parameterTypeBoolean:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case parameterTypeMultiSelect:
+		if len(value) == 0 {
+			return []string{}
+		}
+		return strings.Split(value, ",")
+	}
+	return value
+}
+
+// parameterString formats a parameter value returned by the API as a value of the config map
+func parameterString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				break
+			}
+			values = append(values, s)
+		}
+		if len(values) == len(v) {
+			return strings.Join(values, ",")
+		}
+	}
+	b, _ := json.Marshal(value)
+	return string(b)
+}
+
+// addInstanceParameters adds the parameters of the config and secret_config maps to the configs
+func addInstanceParameters(ctx context.Context, config types.Map, secretConfig types.Map, configs map[string]any, moduleConfiguration []interface{}) error {
+	for attribute, m := range map[string]types.Map{"config": config, "secret_config": secretConfig} {
+		if m.Null || m.Unknown {
+			continue
+		}
+		var values map[string]string
+		diags := m.ElementsAs(ctx, &values, false)
+		if diags.HasError() {
+			return fmt.Errorf("could not read %s: %v", attribute, diags)
+		}
+		for key, value := range values {
+			if _, ok := configs[key]; ok {
+				return fmt.Errorf("key: '%s' is set more than once in 'config', 'secret_config', 'config_json' or 'secret_config_json'. Please choose 1", key)
+			}
+			param := integrationParameter(moduleConfiguration, key)
+			if param == nil {
+				return fmt.Errorf("the integration has no parameter '%s'", key)
+			}
+			configs[key] = parameterValue(param, value)
+		}
+	}
+	return nil
+}
+
+// getConfigFromAPIResponse maps the parameters of an instance back to the keys of the config map, so that a drifted
+// setting shows as a change of its own key. Only the configured keys are read, and a configured value the server
+// returns in another form, such as "True" for "true", is kept. Parameters that are held or reference a stored
+// credential are set by other attributes and left out.
+func getConfigFromAPIResponse(integration map[string]any, prior types.Map, held map[string]any) types.Map {
+	if prior.Null || prior.Unknown {
+		return types.Map{Null: true, ElemType: types.StringType}
+	}
+	config := map[string]attr.Value{}
+	for _, item := range interfaceSlice(integration["data"]) {
+		param, _ := item.(map[string]interface{})
+		name, _ := param["name"].(string)
+		display, _ := param["display"].(string)
+		if len(name) == 0 || isHeldParameter(held, name, display) || parameterCredential(param["value"]) != "" {
+			continue
+		}
+		// keep the key the parameter is configured by, its name or its display name
+		key := name
+		if _, ok := prior.Elems[display]; ok && len(display) > 0 {
+			key = display
+		}
+		priorValue, configured := prior.Elems[key].(types.String)
+		if !configured {
+			continue
+		}
+		value := parameterString(param["value"])
+		if !priorValue.Null && !priorValue.Unknown && sameParameterValue(param, priorValue.Value, value) {
+			value = priorValue.Value
+		}
+		config[key] = types.String{Value: value}
+	}
+	return types.Map{Elems: config, ElemType: types.StringType}
+}
+
+// plannedConfig returns the config map to store after a create or update, which must be the planned one. A
+// configured parameter the server stored with another value is warned about, the next refresh showing it as a change.
+func plannedConfig(integration map[string]any, plan types.Map, held map[string]any, diags *diag.Diagnostics) types.Map {
+	for key, value := range getConfigFromAPIResponse(integration, plan, held).Elems {
+		planned, _ := plan.Elems[key].(types.String)
+		if stored := value.(types.String); stored.Value != planned.Value {
+			diags.AddAttributeWarning(
+				path.Root("config").AtMapKey(key),
+				"Parameter stored with another value",
+				fmt.Sprintf("The server stored the parameter %q as %q rather than %q, the next refresh shows it as a change.", key, stored.Value, planned.Value),
+			)
+		}
+	}
+	return plan
+}
+
+// getAllConfigFromAPIResponse reads every parameter set on an instance by name, for the data source. Parameters that
+// reference a stored credential are left out.
+func getAllConfigFromAPIResponse(integration map[string]any) types.Map {
+	config := map[string]attr.Value{}
+	for _, item := range interfaceSlice(integration["data"]) {
+		param, _ := item.(map[string]interface{})
+		name, _ := param["name"].(string)
+		if hasValue, _ := param["hasvalue"].(bool); len(name) == 0 || !hasValue || parameterCredential(param["value"]) != "" {
+			continue
+		}
+		config[name] = types.String{Value: parameterString(param["value"])}
+	}
+	return types.Map{Elems: config, ElemType: types.StringType}
+}
+
+// sameParameterValue reports whether two values of the config map set a parameter to the same value, which differ
+// only in how a boolean, number or list is written
+func sameParameterValue(param map[string]interface{}, a string, b string) bool {
+	if a == b {
+		return true
+	}
+	paramType, _ := param["type"].(float64)
+	switch int(paramType) {
+	case parameterTypeBoolean:
+		x, errX := strconv.ParseBool(a)
+		y, errY := strconv.ParseBool(b)
+		return errX == nil && errY == nil && x == y
+	case parameterTypeNumber:
+		x, errX := strconv.ParseFloat(strings.TrimSpace(a), 64)
+		y, errY := strconv.ParseFloat(strings.TrimSpace(b), 64)
+		return errX == nil && errY == nil && x == y
+	case parameterTypeMultiSelect:
+		x, y := strings.Split(a, ","), strings.Split(b, ",")
+		if len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if strings.TrimSpace(x[i]) != strings.TrimSpace(y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// instanceTestModes are how a failed test_on_apply is reported, as an error failing the apply or as a warning
+var instanceTestModes = []string{"error", "warn"}
+
+// instanceTestSuccess is the last_test_result of an instance that passed its test
+const instanceTestSuccess = "success"
+
+// testInstance runs the test of the integration, the Test button of the UI, on a saved instance and returns its
+// result for last_test_result. A failed test is reported as an error, or as a warning when test_mode is warn.
+func (r resourceIntegrationInstance) testInstance(ctx context.Context, plan IntegrationInstance, moduleInstance map[string]interface{}, id string, diags *diag.Diagnostics) types.String {
+	moduleInstance["id"] = id
+	var result map[string]interface{}
+	_, err := r.p.doRequest(ctx, http.MethodPost, accountPath(plan.Account, "/settings/integration/test"), moduleInstance, &result)
+	if err != nil {
+		diags.AddError(
+			"Error testing integration instance",
+			"Could not test integration instance: "+err.Error(),
+		)
+		return types.String{Null: true}
+	}
+	if success, _ := result["success"].(bool); success {
+		return types.String{Value: instanceTestSuccess}
+	}
+	message, _ := result["message"].(string)
+	if len(message) == 0 {
+		message = "the test failed without a message"
+	}
+	detail := "The test of integration instance " + plan.Name.Value + " failed: " + message
+	if plan.TestMode.Value == "warn" {
+		diags.AddWarning("Integration instance test failed", detail)
+	} else {
+		diags.AddError("Integration instance test failed", detail)
+	}
+	return types.String{Value: "failure: " + message}
+}
+
+// instanceClassifierTypes are the types of the classifier and mappers the attributes of an instance reference
+var instanceClassifierTypes = map[string]string{
+	"mapping_id":         "classification",
+	"incoming_mapper_id": "mapping-incoming",
+	"outgoing_mapper_id": "mapping-outgoing",
+}
+
+// classifierKind names a type of classifier for messages
+func classifierKind(classifierType string) string {
+	switch classifierType {
+	case "classification":
+		return "classifier"
+	case "mapping-incoming":
+		return "incoming mapper"
+	case "mapping-outgoing":
+		return "outgoing mapper"
+	}
+	return classifierType
+}
+
+// classifierTypeError is returned when a classifier or mapper exists but is not of the type its attribute takes
+type classifierTypeError struct {
+	attribute      string
+	reference      string
+	classifierType string
+}
+
+func (e classifierTypeError) Error() string {
+	return fmt.Sprintf("%s takes the type %s, %q is of type %s", e.attribute, instanceClassifierTypes[e.attribute], e.reference, e.classifierType)
+}
+
+// resolveClassifier returns the ID of the classifier or mapper an attribute references by ID or name, checking that
+// it is of the type the attribute takes
+func (r resourceIntegrationInstance) resolveClassifier(ctx context.Context, account types.String, attribute string, reference string) (string, error) {
+	var classifier openapi.InstanceClassifier
+	var err error
+	if account.Null || len(account.Value) == 0 {
+		classifier, _, err = r.p.client.DefaultApi.GetClassifier(ctx).SetIdentifier(reference).Execute()
+	} else {
+		classifier, _, err = r.p.client.DefaultApi.GetClassifierAccount(ctx, "acc_"+account.Value).SetIdentifier(reference).Execute()
+	}
+	if err != nil {
+		return "", err
+	}
+	if classifier.GetType() != instanceClassifierTypes[attribute] {
+		return "", classifierTypeError{attribute, reference, classifier.GetType()}
+	}
+	return classifier.GetId(), nil
+}
+
+// resolveInstanceClassifiers returns the IDs of the classifier and mappers of an instance by attribute, an unset
+// attribute taking none. When planning, a classifier or mapper that cannot be resolved only warns, as it may be
+// created in the same apply.
+func (r resourceIntegrationInstance) resolveInstanceClassifiers(ctx context.Context, instance IntegrationInstance, planning bool, diags *diag.Diagnostics) map[string]string {
+	ids := map[string]string{}
+	for attribute, reference := range map[string]types.String{
+		"mapping_id":         instance.MappingId,
+		"incoming_mapper_id": instance.IncomingMapperId,
+		"outgoing_mapper_id": instance.OutgoingMapperId,
+	} {
+		ids[attribute] = ""
+		if reference.Null || reference.Unknown || len(reference.Value) == 0 {
+			continue
+		}
+		id, err := r.resolveClassifier(ctx, instance.Account, attribute, reference.Value)
+		if _, mismatch := err.(classifierTypeError); err != nil && planning && !mismatch {
+			diags.AddAttributeWarning(
+				path.Root(attribute),
+				"Unknown "+classifierKind(instanceClassifierTypes[attribute]),
+				"Could not resolve "+classifierKind(instanceClassifierTypes[attribute])+", it must exist when the instance is applied: "+err.Error(),
+			)
+			continue
+		}
+		if err != nil {
+			diags.AddAttributeError(
+				path.Root(attribute),
+				"Invalid "+classifierKind(instanceClassifierTypes[attribute]),
+				"Could not resolve "+classifierKind(instanceClassifierTypes[attribute])+": "+err.Error(),
+			)
+			continue
+		}
+		ids[attribute] = id
+	}
+	return ids
+}
+
+// classifierReference returns the value of a classifier or mapper attribute for the ID set on the instance, keeping
+// the prior reference, an ID or a name, as long as it still resolves to that ID
+func (r resourceIntegrationInstance) classifierReference(ctx context.Context, account types.String, attribute string, prior types.String, id string) types.String {
+	if prior.Null || prior.Unknown || prior.Value == id {
+		return optionalString(id, prior)
+	}
+	if len(id) > 0 && len(prior.Value) > 0 {
+		if resolved, err := r.resolveClassifier(ctx, account, attribute, prior.Value); err == nil && resolved == id {
+			return prior
+		}
+	}
+	return types.String{Value: id}
+}
+
 // GetSchema Resource schema
 func (r resourceIntegrationInstanceType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
 	var planModifiers []tfsdk.AttributePlanModifier
@@ -87,6 +600,15 @@ func (r resourceIntegrationInstanceType) GetSchema(_ context.Context) (tfsdk.Sch
 				Optional: true,
 				Computed: true,
 			},
+			"config": {
+				Type:     types.MapType{ElemType: types.StringType},
+				Optional: true,
+			},
+			"secret_config": {
+				Type:      types.MapType{ElemType: types.StringType},
+				Optional:  true,
+				Sensitive: true,
+			},
 			"propagation_labels": {
 				Type:     types.SetType{ElemType: types.StringType},
 				Computed: true,
@@ -99,21 +621,101 @@ func (r resourceIntegrationInstanceType) GetSchema(_ context.Context) (tfsdk.Sch
 			},
 			"incoming_mapper_id": {
 				Type:     types.StringType,
-				Required: true,
+				Optional: true,
 			},
 			"outgoing_mapper_id": {
 				Type:     types.StringType,
-				Required: true,
+				Optional: true,
 			},
 			// aka classifier
 			"mapping_id": {
 				Type:     types.StringType,
-				Required: true,
+				Optional: true,
 			},
 			"engine_id": {
 				Type:     types.StringType,
-				Required: true,
+				Optional: true,
+			},
+			"credentials": {
+				Type:     types.MapType{ElemType: types.StringType},
+				Optional: true,
+			},
+			"integration_log_level": {
+				Type:          types.StringType,
+				Optional:      true,
+				Computed:      true,
+				Validators:    []tfsdk.AttributeValidator{isOneOf{values: instanceLogLevels}},
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"is_long_running": {
+				Type:          types.BoolType,
+				Optional:      true,
+				Computed:      true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"engine_group": {
+				Type:          types.StringType,
+				Optional:      true,
+				Computed:      true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"reset_context": {
+				Type:          types.BoolType,
+				Optional:      true,
+				Computed:      true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"password_protected": {
+				Type:          types.BoolType,
+				Optional:      true,
+				Computed:      true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
 			},
+			"byoi": {
+				Type:          types.BoolType,
+				Optional:      true,
+				Computed:      true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"default_ignore": {
+				Type:          types.BoolType,
+				Optional:      true,
+				Computed:      true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"is_fetch": {
+				Type:     types.BoolType,
+				Optional: true,
+			},
+			"incident_type": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"fetch_interval": {
+				Type:     types.Int64Type,
+				Optional: true,
+			},
+			"mirroring_direction": {
+				Type:       types.StringType,
+				Optional:   true,
+				Validators: []tfsdk.AttributeValidator{isOneOf{values: mirroringDirections}},
+			},
+			"test_on_apply": {
+				Type:     types.BoolType,
+				Optional: true,
+			},
+			"test_mode": {
+				Type:       types.StringType,
+				Optional:   true,
+				Validators: []tfsdk.AttributeValidator{isOneOf{values: instanceTestModes}},
+			},
+			"last_test_result": {
+				Type:     types.StringType,
+				Computed: true,
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
 		},
 	}, nil
 }
@@ -129,8 +731,161 @@ type resourceIntegrationInstance struct {
 	p provider
 }
 
+func (r resourceIntegrationInstance) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config IntegrationInstance
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Config.Null && !config.ConfigJson.Null {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("config"),
+			"Invalid Attribute Combination",
+			"The parameters are either set per key with config or as a whole with config_json, config and config_json cannot both be set.",
+		)
+	}
+	if !config.SecretConfig.Null && !config.SecretConfigJson.Null {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("secret_config"),
+			"Invalid Attribute Combination",
+			"The secret parameters are either set per key with secret_config or as a whole with secret_config_json, secret_config and secret_config_json cannot both be set.",
+		)
+	}
+}
+
+// parseParameters parses a JSON object of integration parameters, a null or empty attribute holding none
+func parseParameters(value types.String) (map[string]any, error) {
+	params := map[string]any{}
+	if value.Null || value.Unknown || len(value.Value) == 0 {
+		return params, nil
+	}
+	err := json.Unmarshal([]byte(value.Value), &params)
+	return params, err
+}
+
+// listIntegrations lists the integrations installed in the tenant of the account, or in the main tenant
+func (r resourceIntegrationInstance) listIntegrations(ctx context.Context, account types.String) (map[string]interface{}, error) {
+	var integrations map[string]interface{}
+	var err error
+	if account.Null || len(account.Value) == 0 {
+		integrations, _, err = r.p.client.DefaultApi.ListIntegrations(ctx).Execute()
+	} else {
+		integrations, _, err = r.p.client.DefaultApi.ListIntegrationsAccount(ctx, "acc_"+account.Value).Execute()
+	}
+	return integrations, err
+}
+
+// ModifyPlan validates the instance against the integration catalog of the server, so that an unknown integration
+// or misconfigured parameters are reported before anything is applied
+func (r resourceIntegrationInstance) ModifyPlan(ctx context.Context, req tfsdk.ModifyResourcePlanRequest, resp *tfsdk.ModifyResourcePlanResponse) {
+	// nothing to check on destroy, or before the server can be reached
+	if req.Plan.Raw.IsNull() || !r.p.configured {
+		return
+	}
+
+	var config IntegrationInstance
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	// the integrations are those of the tenant of the account
+	if resp.Diagnostics.HasError() || config.IntegrationName.Unknown || config.Account.Unknown {
+		return
+	}
+
+	integrations, err := r.listIntegrations(ctx, config.Account)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Could not validate integration instance",
+			"Could not list integrations: "+err.Error(),
+		)
+		return
+	}
+	// the pack providing the integration must be installed before the instance is planned, by an xsoar_content_pack
+	// applied first
+	integration := findIntegration(integrations, config.IntegrationName.Value)
+	if integration == nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("integration_name"),
+			"Unknown integration",
+			unknownIntegrationDetail(integrations, config.IntegrationName.Value),
+		)
+		return
+	}
+
+	attributes := map[string]map[string]interface{}{}
+	complete := true
+	for name, value := range map[string]types.String{"config_json": config.ConfigJson, "secret_config_json": config.SecretConfigJson} {
+		complete = complete && !value.Unknown
+		params, err := parseParameters(value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Invalid integration parameters",
+				"Could not parse integration instance parameters: "+err.Error(),
+			)
+			return
+		}
+		attributes[name] = params
+	}
+	for name, value := range map[string]types.Map{"config": config.Config, "secret_config": config.SecretConfig} {
+		complete = complete && !value.Unknown
+		if value.Null || value.Unknown {
+			continue
+		}
+		attributes[name] = map[string]interface{}{}
+		for key, element := range value.Elems {
+			// an unknown value only counts as set
+			attributes[name][key] = nil
+			if s, ok := element.(types.String); ok && !s.Null && !s.Unknown {
+				attributes[name][key] = s.Value
+			}
+		}
+	}
+	for attribute, value := range instanceParameterValues(config) {
+		attributes[attribute] = map[string]interface{}{instanceParameters[attribute]: value}
+	}
+	complete = complete && !config.Credentials.Unknown
+	if !config.Credentials.Null && !config.Credentials.Unknown {
+		attributes["credentials"] = map[string]interface{}{}
+		for key := range config.Credentials.Elems {
+			attributes["credentials"][key] = nil
+		}
+	}
+	// encrypted parameters set in config would show in plain text in every plan
+	for key := range attributes["config"] {
+		param := integrationParameter(interfaceSlice(integration["configuration"]), key)
+		if paramType, _ := param["type"].(float64); paramType == parameterTypeEncrypted || paramType == parameterTypeTextAreaEncrypted {
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("config"),
+				"Encrypted integration parameter",
+				fmt.Sprintf("Parameter %q is encrypted, set it in secret_config so that its value is masked.", key),
+			)
+		}
+	}
+	for _, problem := range validateIntegrationParameters(interfaceSlice(integration["configuration"]), attributes, complete) {
+		if len(problem.attribute) == 0 {
+			resp.Diagnostics.AddError(problem.summary, problem.detail)
+		} else {
+			resp.Diagnostics.AddAttributeError(path.Root(problem.attribute), problem.summary, problem.detail)
+		}
+	}
+
+	// the classifier and mappers must exist and be of the type their attribute takes
+	if !config.Account.Unknown {
+		r.resolveInstanceClassifiers(ctx, config, true, &resp.Diagnostics)
+	}
+}
+
 // Create a new resource
 func (r resourceIntegrationInstance) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if !r.p.configured {
 		resp.Diagnostics.AddError(
 			"Provider not configured",
@@ -142,7 +897,7 @@ func (r resourceIntegrationInstance) Create(ctx context.Context, req tfsdk.Creat
 	// Retrieve values from plan
 	var plan IntegrationInstance
 	var tf_config IntegrationInstance
-	diags := req.Plan.Get(ctx, &plan)
+	diags = req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	diags = req.Config.Get(ctx, &tf_config)
 	resp.Diagnostics.Append(diags...)
@@ -152,7 +907,7 @@ func (r resourceIntegrationInstance) Create(ctx context.Context, req tfsdk.Creat
 
 	// Create
 	// list integrations
-	integrations, _, err := r.p.client.DefaultApi.ListIntegrations(ctx).Execute()
+	integrations, err := r.listIntegrations(ctx, plan.Account)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error listing integration",
@@ -180,8 +935,6 @@ func (r resourceIntegrationInstance) Create(ctx context.Context, req tfsdk.Creat
 				Enabled = strconv.FormatBool(plan.Enabled.Value)
 			}
 			moduleInstance["enabled"] = Enabled
-			// todo: add this as a config option
-
 			var EngineId string
 			if ok := plan.EngineId.Value; ok != "" {
 				EngineId = plan.EngineId.Value
@@ -189,54 +942,39 @@ func (r resourceIntegrationInstance) Create(ctx context.Context, req tfsdk.Creat
 				EngineId = ""
 			}
 			moduleInstance["engine"] = EngineId
-
-			// moduleInstance["engine"] = ""
-			//moduleInstance["engineGroup"] = ""
-			//moduleInstance["id"] = ""
-			var IncomingMapperId string
-			if ok := plan.IncomingMapperId.Value; ok != "" {
-				IncomingMapperId = plan.IncomingMapperId.Value
-			} else {
-				IncomingMapperId = ""
-			}
-			moduleInstance["incomingMapperId"] = IncomingMapperId
-			var MappingId string
-			if ok := plan.MappingId.Value; ok != "" {
-				MappingId = plan.MappingId.Value
-			} else {
-				MappingId = ""
-			}
-			// outgoing mapperid
-			var OutgoingMapperId string
-			if ok := plan.OutgoingMapperId.Value; ok != "" {
-				OutgoingMapperId = plan.OutgoingMapperId.Value
-			} else {
-				OutgoingMapperId = ""
-			}
-			moduleInstance["outgoingMapperId"] = OutgoingMapperId
-
-			moduleInstance["mappingId"] = MappingId
-			//moduleInstance["integrationLogLevel"] = ""
-			// todo: add this as a config option (byoi)
 			var isIntegrationScript bool
 			if val, ok := config["integrationScript"]; ok && val != nil {
 				isIntegrationScript = true
 			}
+			// byoi, engine_group, integration_log_level and the other settings are added by addInstanceSettings
 			moduleInstance["isIntegrationScript"] = isIntegrationScript
-			//moduleInstance["isLongRunning"] = false
 			moduleInstance["name"] = plan.Name.Value
-			//moduleInstance["outgoingMapperId"] = ""
-			//moduleInstance["passwordProtected"] = false
 			var propLabels []string
 			plan.PropagationLabels.ElementsAs(ctx, &propLabels, false)
 			moduleInstance["propagationLabels"] = propLabels
-			//moduleInstance["resetContext"] = false
 			moduleInstance["version"] = -1
 			break
 		}
 	}
+	if len(moduleInstance) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("integration_name"),
+			"Error creating integration instance",
+			unknownIntegrationDetail(integrations, plan.IntegrationName.Value),
+		)
+		return
+	}
+
+	// the classifier and mappers can be referenced by name, the instance takes their IDs
+	classifierIds := r.resolveInstanceClassifiers(ctx, plan, false, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	moduleInstance["mappingId"] = classifierIds["mapping_id"]
+	moduleInstance["incomingMapperId"] = classifierIds["incoming_mapper_id"]
+	moduleInstance["outgoingMapperId"] = classifierIds["outgoing_mapper_id"]
 	var configs map[string]any
-	if plan.ConfigJson.Null {
+	if plan.ConfigJson.Null || plan.ConfigJson.Unknown {
 		configs = map[string]any{}
 	} else {
 		err = json.Unmarshal([]byte(plan.ConfigJson.Value), &configs)
@@ -274,6 +1012,30 @@ func (r resourceIntegrationInstance) Create(ctx context.Context, req tfsdk.Creat
 		}
 		configs[key] = element
 	}
+	err = addInstanceParameters(ctx, plan.Config, plan.SecretConfig, configs, moduleConfiguration)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating integration instance",
+			"Could not set integration instance parameters: "+err.Error(),
+		)
+		return
+	}
+	err = addInstanceCredentials(ctx, plan.Credentials, configs, moduleConfiguration)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating integration instance",
+			"Could not set integration instance credentials: "+err.Error(),
+		)
+		return
+	}
+	err = addInstanceSettings(plan, moduleInstance, configs)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating integration instance",
+			"Could not set integration instance settings: "+err.Error(),
+		)
+		return
+	}
 	for _, parameter := range moduleConfiguration {
 		param := parameter.(map[string]interface{})
 		param["hasvalue"] = false
@@ -320,7 +1082,8 @@ func (r resourceIntegrationInstance) Create(ctx context.Context, req tfsdk.Creat
 		}
 	}
 
-	integrationConfigsJson, err := getIntegrationsFromAPIResponse(ctx, integration, secretConfigs, resp.Diagnostics)
+	held := heldParameters(secretConfigs, plan)
+	integrationConfigsJson, err := getIntegrationsFromAPIResponse(ctx, integration, held, resp.Diagnostics)
 	if err != nil {
 		return
 	}
@@ -334,6 +1097,9 @@ func (r resourceIntegrationInstance) Create(ctx context.Context, req tfsdk.Creat
 		PropagationLabels: types.Set{Elems: propagationLabels, ElemType: types.StringType},
 		ConfigJson:        types.String{Value: integrationConfigsJson},
 		SecretConfigJson:  types.String{Value: secretConfigJson},
+		Config:            plannedConfig(integration, plan.Config, held, &resp.Diagnostics),
+		SecretConfig:      plan.SecretConfig,
+		Credentials:       getCredentialsFromAPIResponse(integration, plan.Credentials),
 	}
 
 	Enabled, err := strconv.ParseBool(integration["enabled"].(string))
@@ -343,36 +1109,32 @@ func (r resourceIntegrationInstance) Create(ctx context.Context, req tfsdk.Creat
 		result.Enabled = types.Bool{Null: true}
 	}
 
-	IncomingMapperId, ok := integration["incomingMapperId"].(string)
-	if ok {
-		result.IncomingMapperId = types.String{Value: IncomingMapperId}
-	} else {
-		result.IncomingMapperId = types.String{Null: true}
-	}
-	OutgoingMapperId, ok := integration["outgoingMapperId"].(string)
-	if ok {
-		result.OutgoingMapperId = types.String{Value: OutgoingMapperId}
-	} else {
-		result.OutgoingMapperId = types.String{Null: true}
-	}
+	IncomingMapperId, _ := integration["incomingMapperId"].(string)
+	result.IncomingMapperId = r.classifierReference(ctx, plan.Account, "incoming_mapper_id", plan.IncomingMapperId, IncomingMapperId)
+	OutgoingMapperId, _ := integration["outgoingMapperId"].(string)
+	result.OutgoingMapperId = r.classifierReference(ctx, plan.Account, "outgoing_mapper_id", plan.OutgoingMapperId, OutgoingMapperId)
+	MappingId, _ := integration["mappingId"].(string)
+	result.MappingId = r.classifierReference(ctx, plan.Account, "mapping_id", plan.MappingId, MappingId)
+	EngineId, _ := integration["engine"].(string)
+	result.EngineId = optionalString(EngineId, plan.EngineId)
 
-	MappingId, ok := integration["mappingId"].(string)
-	if ok {
-		result.MappingId = types.String{Value: MappingId}
-	} else {
-		result.MappingId = types.String{Null: true}
-	}
+	getInstanceSettings(integration, plan, &result)
 
-	EngineId, ok := integration["engine"].(string)
-	if ok {
-		result.EngineId = types.String{Value: EngineId}
-	} else {
-		result.EngineId = types.String{Null: true}
+	// Test
+	// the instance is saved even when its test fails, so the state is set before a failure is reported
+	result.TestOnApply = plan.TestOnApply
+	result.TestMode = plan.TestMode
+	result.LastTestResult = types.String{Null: true}
+	var testDiags diag.Diagnostics
+	if plan.TestOnApply.Value {
+		result.LastTestResult = r.testInstance(ctx, plan, moduleInstance, result.Id.Value, &testDiags)
 	}
 
 	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(testDiags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -380,9 +1142,16 @@ func (r resourceIntegrationInstance) Create(ctx context.Context, req tfsdk.Creat
 
 // Read resource information
 func (r resourceIntegrationInstance) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get current state
 	var state IntegrationInstance
-	diags := req.State.Get(ctx, &state)
+	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -464,7 +1233,8 @@ func (r resourceIntegrationInstance) Read(ctx context.Context, req tfsdk.ReadRes
 			return
 		}
 	}
-	integrationConfigsJson, err := getIntegrationsFromAPIResponse(ctx, integration, secretConfigs, resp.Diagnostics)
+	held := heldParameters(secretConfigs, state)
+	integrationConfigsJson, err := getIntegrationsFromAPIResponse(ctx, integration, held, resp.Diagnostics)
 	if err != nil {
 		return
 	}
@@ -478,6 +1248,12 @@ func (r resourceIntegrationInstance) Read(ctx context.Context, req tfsdk.ReadRes
 		PropagationLabels: types.Set{Elems: propagationLabels, ElemType: types.StringType},
 		ConfigJson:        types.String{Value: integrationConfigsJson},
 		SecretConfigJson:  types.String{Value: secretConfigJson},
+		Config:            getConfigFromAPIResponse(integration, state.Config, held),
+		SecretConfig:      state.SecretConfig,
+		Credentials:       getCredentialsFromAPIResponse(integration, state.Credentials),
+		TestOnApply:       state.TestOnApply,
+		TestMode:          state.TestMode,
+		LastTestResult:    state.LastTestResult,
 	}
 
 	Enabled, err := strconv.ParseBool(integration["enabled"].(string))
@@ -486,35 +1262,19 @@ func (r resourceIntegrationInstance) Read(ctx context.Context, req tfsdk.ReadRes
 	} else {
 		result.Enabled = types.Bool{Null: true}
 	}
-	IncomingMapperId, ok := integration["incomingMapperId"].(string)
-	if ok {
-		result.IncomingMapperId = types.String{Value: IncomingMapperId}
-	} else {
-		result.IncomingMapperId = types.String{Null: true}
-	}
-
-	OutgoingMapperId, ok := integration["outgoingMapperId"].(string)
-	if ok {
-		result.OutgoingMapperId = types.String{Value: OutgoingMapperId}
-	} else {
-		result.OutgoingMapperId = types.String{Null: true}
-	}
-
-	MappingId, ok := integration["mappingId"].(string)
-	if ok {
-		result.MappingId = types.String{Value: MappingId}
-	} else {
-		result.MappingId = types.String{Null: true}
-	}
+	IncomingMapperId, _ := integration["incomingMapperId"].(string)
+	result.IncomingMapperId = r.classifierReference(ctx, state.Account, "incoming_mapper_id", state.IncomingMapperId, IncomingMapperId)
+	OutgoingMapperId, _ := integration["outgoingMapperId"].(string)
+	result.OutgoingMapperId = r.classifierReference(ctx, state.Account, "outgoing_mapper_id", state.OutgoingMapperId, OutgoingMapperId)
+	MappingId, _ := integration["mappingId"].(string)
+	result.MappingId = r.classifierReference(ctx, state.Account, "mapping_id", state.MappingId, MappingId)
+	EngineId, _ := integration["engine"].(string)
+	result.EngineId = optionalString(EngineId, state.EngineId)
 
-	EngineId, ok := integration["engine"].(string)
-	if ok {
-		result.EngineId = types.String{Value: EngineId}
-	} else {
-		result.EngineId = types.String{Null: true}
-	}
+	getInstanceSettings(integration, state, &result)
 
 	// Generate resource state struct
+	result.Timeouts = state.Timeouts
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -524,9 +1284,16 @@ func (r resourceIntegrationInstance) Read(ctx context.Context, req tfsdk.ReadRes
 
 // Update resource
 func (r resourceIntegrationInstance) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get plan values
 	var plan IntegrationInstance
-	diags := req.Plan.Get(ctx, &plan)
+	diags = req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -542,7 +1309,7 @@ func (r resourceIntegrationInstance) Update(ctx context.Context, req tfsdk.Updat
 
 	// Build request
 	// list integrations
-	integrations, _, err := r.p.client.DefaultApi.ListIntegrations(ctx).Execute()
+	integrations, err := r.listIntegrations(ctx, plan.Account)
 	if err != nil {
 		resp.Diagnostics.AddError(
 			"Error listing integration",
@@ -570,8 +1337,6 @@ func (r resourceIntegrationInstance) Update(ctx context.Context, req tfsdk.Updat
 				Enabled = strconv.FormatBool(plan.Enabled.Value)
 			}
 			moduleInstance["enabled"] = Enabled
-			// todo: add this as a config option
-
 			var EngineId string
 			if ok := plan.EngineId.Value; ok != "" {
 				EngineId = plan.EngineId.Value
@@ -579,53 +1344,41 @@ func (r resourceIntegrationInstance) Update(ctx context.Context, req tfsdk.Updat
 				EngineId = ""
 			}
 			moduleInstance["engine"] = EngineId
-
-			//moduleInstance["engine"] = ""
-			//moduleInstance["engineGroup"] = ""
 			moduleInstance["id"] = state.Id.Value
-			var IncomingMapperId string
-			if ok := plan.IncomingMapperId.Value; ok != "" {
-				IncomingMapperId = plan.IncomingMapperId.Value
-			} else {
-				IncomingMapperId = ""
-			}
-			moduleInstance["incomingMapperId"] = IncomingMapperId
-			var OutgoingMapperId string
-			if ok := plan.OutgoingMapperId.Value; ok != "" {
-				OutgoingMapperId = plan.OutgoingMapperId.Value
-			} else {
-				OutgoingMapperId = ""
-			}
-			moduleInstance["outgoingMapperId"] = OutgoingMapperId
-			var MappingId string
-			if ok := plan.MappingId.Value; ok != "" {
-				MappingId = plan.MappingId.Value
-			} else {
-				MappingId = ""
-			}
-			moduleInstance["mappingId"] = MappingId
-			//moduleInstance["integrationLogLevel"] = ""
-			// todo: add this as a config option (byoi)
 			var isIntegrationScript bool
 			if val, ok := config["integrationScript"]; ok && val != nil {
 				isIntegrationScript = true
 			}
+			// byoi, engine_group, integration_log_level and the other settings are added by addInstanceSettings
 			moduleInstance["isIntegrationScript"] = isIntegrationScript
-			//moduleInstance["isLongRunning"] = false
 			moduleInstance["name"] = plan.Name.Value
-			//moduleInstance["outgoingMapperId"] = ""
-			//moduleInstance["passwordProtected"] = false
 			var propLabels []string
 			plan.PropagationLabels.ElementsAs(ctx, &propLabels, false)
 			moduleInstance["propagationLabels"] = propLabels
-			//moduleInstance["resetContext"] = false
 			moduleInstance["version"] = -1
 			break
 		}
 	}
+	if len(moduleInstance) == 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("integration_name"),
+			"Error updating integration instance",
+			unknownIntegrationDetail(integrations, plan.IntegrationName.Value),
+		)
+		return
+	}
+
+	// the classifier and mappers can be referenced by name, the instance takes their IDs
+	classifierIds := r.resolveInstanceClassifiers(ctx, plan, false, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	moduleInstance["mappingId"] = classifierIds["mapping_id"]
+	moduleInstance["incomingMapperId"] = classifierIds["incoming_mapper_id"]
+	moduleInstance["outgoingMapperId"] = classifierIds["outgoing_mapper_id"]
 
 	var configs map[string]any
-	if plan.ConfigJson.Null {
+	if plan.ConfigJson.Null || plan.ConfigJson.Unknown {
 		configs = map[string]any{}
 	} else {
 		err = json.Unmarshal([]byte(plan.ConfigJson.Value), &configs)
@@ -663,6 +1416,30 @@ func (r resourceIntegrationInstance) Update(ctx context.Context, req tfsdk.Updat
 		}
 		configs[key] = element
 	}
+	err = addInstanceParameters(ctx, plan.Config, plan.SecretConfig, configs, moduleConfiguration)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating integration instance",
+			"Could not set integration instance parameters: "+err.Error(),
+		)
+		return
+	}
+	err = addInstanceCredentials(ctx, plan.Credentials, configs, moduleConfiguration)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating integration instance",
+			"Could not set integration instance credentials: "+err.Error(),
+		)
+		return
+	}
+	err = addInstanceSettings(plan, moduleInstance, configs)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating integration instance",
+			"Could not set integration instance settings: "+err.Error(),
+		)
+		return
+	}
 	for _, parameter := range moduleConfiguration {
 		param := parameter.(map[string]interface{})
 		param["hasvalue"] = false
@@ -712,7 +1489,8 @@ func (r resourceIntegrationInstance) Update(ctx context.Context, req tfsdk.Updat
 		}
 	}
 
-	integrationConfigsJson, err := getIntegrationsFromAPIResponse(ctx, integration, secretConfigs, resp.Diagnostics)
+	held := heldParameters(secretConfigs, plan)
+	integrationConfigsJson, err := getIntegrationsFromAPIResponse(ctx, integration, held, resp.Diagnostics)
 	if err != nil {
 		return
 	}
@@ -726,6 +1504,9 @@ func (r resourceIntegrationInstance) Update(ctx context.Context, req tfsdk.Updat
 		PropagationLabels: types.Set{Elems: propagationLabels, ElemType: types.StringType},
 		ConfigJson:        types.String{Value: integrationConfigsJson},
 		SecretConfigJson:  types.String{Value: secretConfigJson},
+		Config:            plannedConfig(integration, plan.Config, held, &resp.Diagnostics),
+		SecretConfig:      plan.SecretConfig,
+		Credentials:       getCredentialsFromAPIResponse(integration, plan.Credentials),
 	}
 
 	Enabled, err := strconv.ParseBool(integration["enabled"].(string))
@@ -735,36 +1516,31 @@ func (r resourceIntegrationInstance) Update(ctx context.Context, req tfsdk.Updat
 		result.Enabled = types.Bool{Null: true}
 	}
 
-	IncomingMapperId, ok := integration["incomingMapperId"].(string)
-	if ok {
-		result.IncomingMapperId = types.String{Value: IncomingMapperId}
-	} else {
-		result.IncomingMapperId = types.String{Null: true}
-	}
-	OutgoingMapperId, ok := integration["outgoingMapperId"].(string)
-	if ok {
-		result.OutgoingMapperId = types.String{Value: OutgoingMapperId}
-	} else {
-		result.OutgoingMapperId = types.String{Null: true}
-	}
+	IncomingMapperId, _ := integration["incomingMapperId"].(string)
+	result.IncomingMapperId = r.classifierReference(ctx, plan.Account, "incoming_mapper_id", plan.IncomingMapperId, IncomingMapperId)
+	OutgoingMapperId, _ := integration["outgoingMapperId"].(string)
+	result.OutgoingMapperId = r.classifierReference(ctx, plan.Account, "outgoing_mapper_id", plan.OutgoingMapperId, OutgoingMapperId)
+	MappingId, _ := integration["mappingId"].(string)
+	result.MappingId = r.classifierReference(ctx, plan.Account, "mapping_id", plan.MappingId, MappingId)
+	EngineId, _ := integration["engine"].(string)
+	result.EngineId = optionalString(EngineId, plan.EngineId)
 
-	MappingId, ok := integration["mappingId"].(string)
-	if ok {
-		result.MappingId = types.String{Value: MappingId}
-	} else {
-		result.MappingId = types.String{Null: true}
-	}
+	getInstanceSettings(integration, plan, &result)
 
-	EngineId, ok := integration["engine"].(string)
-	if ok {
-		result.EngineId = types.String{Value: EngineId}
-	} else {
-		result.EngineId = types.String{Null: true}
+	// Test
+	result.TestOnApply = plan.TestOnApply
+	result.TestMode = plan.TestMode
+	result.LastTestResult = state.LastTestResult
+	var testDiags diag.Diagnostics
+	if plan.TestOnApply.Value {
+		result.LastTestResult = r.testInstance(ctx, plan, moduleInstance, result.Id.Value, &testDiags)
 	}
 
 	// Set state
+	result.Timeouts = plan.Timeouts
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(testDiags...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
@@ -772,9 +1548,16 @@ func (r resourceIntegrationInstance) Update(ctx context.Context, req tfsdk.Updat
 
 // Delete resource
 func (r resourceIntegrationInstance) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get state
 	var state IntegrationInstance
-	diags := req.State.Get(ctx, &state)
+	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -840,19 +1623,19 @@ func (r resourceIntegrationInstance) ImportState(ctx context.Context, req tfsdk.
 		}
 	}
 
-	integrationConfigsJson, err := getIntegrationsFromAPIResponse(ctx, integration, map[string]any{}, resp.Diagnostics)
-	if err != nil {
-		return
-	}
-
 	// Map response body to resource schema attribute
 	result := IntegrationInstance{
 		Name:              types.String{Value: integration["name"].(string)},
 		Id:                types.String{Value: integration["id"].(string)},
 		IntegrationName:   types.String{Value: integration["brand"].(string)},
 		PropagationLabels: types.Set{Elems: propagationLabels, ElemType: types.StringType},
-		ConfigJson:        types.String{Value: integrationConfigsJson},
 		SecretConfigJson:  types.String{Value: "{}"},
+		Config:            types.Map{Null: true, ElemType: types.StringType},
+		SecretConfig:      types.Map{Null: true, ElemType: types.StringType},
+		Credentials:       getCredentialsFromAPIResponse(integration, types.Map{Null: true}),
+		TestOnApply:       types.Bool{Null: true},
+		TestMode:          types.String{Null: true},
+		LastTestResult:    types.String{Null: true},
 	}
 
 	Enabled, err := strconv.ParseBool(integration["enabled"].(string))
@@ -862,32 +1645,22 @@ func (r resourceIntegrationInstance) ImportState(ctx context.Context, req tfsdk.
 		result.Enabled = types.Bool{Null: true}
 	}
 
-	IncomingMapperId, ok := integration["incomingMapperId"].(string)
-	if ok {
-		result.IncomingMapperId = types.String{Value: IncomingMapperId}
-	} else {
-		result.IncomingMapperId = types.String{Null: true}
-	}
-	OutgoingMapperId, ok := integration["outgoingMapperId"].(string)
-	if ok {
-		result.OutgoingMapperId = types.String{Value: OutgoingMapperId}
-	} else {
-		result.OutgoingMapperId = types.String{Null: true}
-	}
+	IncomingMapperId, _ := integration["incomingMapperId"].(string)
+	result.IncomingMapperId = r.classifierReference(ctx, types.String{Null: true}, "incoming_mapper_id", types.String{Null: true}, IncomingMapperId)
+	OutgoingMapperId, _ := integration["outgoingMapperId"].(string)
+	result.OutgoingMapperId = r.classifierReference(ctx, types.String{Null: true}, "outgoing_mapper_id", types.String{Null: true}, OutgoingMapperId)
+	MappingId, _ := integration["mappingId"].(string)
+	result.MappingId = r.classifierReference(ctx, types.String{Null: true}, "mapping_id", types.String{Null: true}, MappingId)
+	EngineId, _ := integration["engine"].(string)
+	result.EngineId = optionalString(EngineId, types.String{Null: true})
 
-	MappingId, ok := integration["mappingId"].(string)
-	if ok {
-		result.MappingId = types.String{Value: MappingId}
-	} else {
-		result.MappingId = types.String{Null: true}
-	}
-
-	EngineId, ok := integration["engine"].(string)
-	if ok {
-		result.EngineId = types.String{Value: EngineId}
-	} else {
-		result.EngineId = types.String{Null: true}
+	// an empty prior reads every common parameter the instance has, which are then left out of config_json
+	getInstanceSettings(integration, IntegrationInstance{}, &result)
+	integrationConfigsJson, err := getIntegrationsFromAPIResponse(ctx, integration, heldParameters(map[string]any{}, result), resp.Diagnostics)
+	if err != nil {
+		return
 	}
+	result.ConfigJson = types.String{Value: integrationConfigsJson}
 
 	if acc != "" {
 		result.Account = types.String{Value: acc}
@@ -896,6 +1669,7 @@ func (r resourceIntegrationInstance) ImportState(ctx context.Context, req tfsdk.
 	}
 
 	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
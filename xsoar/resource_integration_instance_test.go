@@ -3,7 +3,7 @@ package xsoar
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -18,7 +18,7 @@ func TestAccIntegrationInstance_basic(t *testing.T) {
 		PreCheck: func() { testAccIntegrationInstanceResourcePreCheck(t) },
 		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
 			"xsoar": func() (tfprotov6.ProviderServer, error) {
-				return tfsdk.NewProtocol6Server(New()), nil
+				return providerserver.NewProtocol6WithError(New()())()
 			},
 		},
 		CheckDestroy: testAccCheckIntegrationInstanceResourceDestroy(rName),
@@ -38,6 +38,462 @@ func TestAccIntegrationInstance_basic(t *testing.T) {
 	})
 }
 
+func TestIntegrationInstance_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{
+			"name":               "mockinstance",
+			"integration_name":   "MockIntegration",
+			"propagation_labels": []string{"all"},
+			"config_json":        `{"insecure":false,"url":"https://mock.local/api"}`,
+			"secret_config_json": `{"apikey":"123"}`,
+		}
+		importId := "mockinstance"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".mockinstance"
+		}
+		r := tf.resource("xsoar_integration_instance")
+		r.mustApply(config)
+		id := r.attrString("id")
+		instance := m.object(acc, "instances", id)
+		if instance == nil {
+			t.Fatalf("integration instance %s was not created in account %q", id, acc)
+		}
+		if got := mockParameter(instance, "apikey"); got != "123" {
+			t.Fatalf("secret parameter was not sent, got %v", got)
+		}
+
+		config["enabled"] = false
+		config["config_json"] = `{"insecure":true,"url":"https://mock.local/api"}`
+		r.mustApply(config)
+		if r.attrString("id") != id {
+			t.Fatal("integration instance was replaced when it should have been updated")
+		}
+		instance = m.object(acc, "instances", id)
+		if instance["enabled"] != "false" || mockParameter(instance, "insecure") != true {
+			t.Fatalf("integration instance was not updated: %v", instance)
+		}
+
+		r.mustImport(importId, "config_json", "secret_config_json")
+		r.mustDestroy()
+		if m.object(acc, "instances", id) != nil {
+			t.Fatal("found integration instance when none was expected")
+		}
+	}
+}
+
+func TestIntegrationInstance_mockCredentials(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	credential := tf.resource("xsoar_credential")
+	credential.mustApply(map[string]interface{}{
+		"name":     "mockcredential",
+		"user":     "svc-mock",
+		"password": "hunter2",
+	})
+
+	config := map[string]interface{}{
+		"name":             "mockinstance",
+		"integration_name": "MockCredentialIntegration",
+		"config_json":      `{"url":"https://mock.local/api"}`,
+		"credentials":      map[string]interface{}{"credentials": "mockcredential"},
+	}
+	r := tf.resource("xsoar_integration_instance")
+	r.mustApply(config)
+	instance := m.object("", "instances", r.attrString("id"))
+	value, _ := mockParameter(instance, "credentials").(map[string]interface{})
+	if value["credential"] != "mockcredential" || value["password"] != "" {
+		t.Fatalf("credential was not referenced by name, got %v", value)
+	}
+	if r.attrString("config_json") != `{"url":"https://mock.local/api"}` {
+		t.Fatalf("credential reference leaked into config_json: %s", r.attrString("config_json"))
+	}
+	r.mustImport("mockinstance", "config_json", "secret_config_json")
+
+	// only parameters of the credentials type can reference a credential
+	config["credentials"] = map[string]interface{}{"url": "mockcredential"}
+	config["config_json"] = `{}`
+	if err := r.apply(config); err == nil {
+		t.Fatal("expected an error referencing a credential from a parameter of another type")
+	}
+}
+
+func TestIntegrationInstance_mockConfig(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	config := map[string]interface{}{
+		"name":             "mockinstance",
+		"integration_name": "MockIntegration",
+		"config": map[string]interface{}{
+			"url":                   "https://mock.local/api",
+			"Trust any certificate": "true",
+		},
+		"secret_config": map[string]interface{}{"apikey": "123"},
+	}
+	r := tf.resource("xsoar_integration_instance")
+	r.mustApply(config)
+	id := r.attrString("id")
+	instance := m.object("", "instances", id)
+	if mockParameter(instance, "insecure") != true || mockParameter(instance, "apikey") != "123" {
+		t.Fatalf("parameters were not sent with the types of the integration: %v", instance["data"])
+	}
+	if strings.Contains(r.attrString("config_json"), "apikey") {
+		t.Fatalf("secret parameter leaked into config_json: %s", r.attrString("config_json"))
+	}
+
+	// a parameter changed on the server drifts on its own key
+	for _, item := range mockSlice(instance["data"]) {
+		if param := item.(map[string]interface{}); param["name"] == "url" {
+			param["value"] = "https://drifted.local/api"
+		}
+	}
+	if err := r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	got := r.attr("config").(map[string]interface{})
+	if got["url"] != "https://drifted.local/api" || got["Trust any certificate"] != "true" {
+		t.Fatalf("drift was not mapped back per parameter, got %v", got)
+	}
+	r.mustApply(config)
+	if mockParameter(m.object("", "instances", id), "url") != "https://mock.local/api" {
+		t.Fatal("drift on the server was not reverted")
+	}
+
+	// a value the server writes differently is not drift, and parameters that are not configured are not read
+	instance = m.object("", "instances", id)
+	for _, item := range mockSlice(instance["data"]) {
+		if param := item.(map[string]interface{}); param["name"] == "insecure" {
+			param["value"] = "True"
+		}
+	}
+	instance["data"] = append(mockSlice(instance["data"]), map[string]interface{}{"name": "proxy", "display": "Use system proxy", "value": true, "hasvalue": true, "type": float64(8)})
+	if err := r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	got = r.attr("config").(map[string]interface{})
+	if len(got) != 2 || got["Trust any certificate"] != "true" {
+		t.Fatalf("expected only the configured keys as configured, got %v", got)
+	}
+
+	// a value the server saves differently is kept as planned
+	config["name"] = "mocknormalizedinstance"
+	m.storedParameters = map[string]interface{}{"insecure": "True"}
+	r = tf.resource("xsoar_integration_instance")
+	r.mustApply(config)
+	if len(r.warnings) != 0 || r.attr("config").(map[string]interface{})["Trust any certificate"] != "true" {
+		t.Fatalf("expected the planned value to be kept, got %v and warnings %v", r.attr("config"), r.warnings)
+	}
+	r.mustDestroy()
+
+	// a value the server saves as another value is warned about and shows as drift, rather than failing the apply
+	m.storedParameters = map[string]interface{}{"url": "https://mock.local/api/"}
+	r = tf.resource("xsoar_integration_instance")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "plan was not empty after apply") {
+		t.Fatalf("expected the changed value to show as drift, got %v", err)
+	}
+	if len(r.warnings) != 1 || !strings.Contains(r.warnings[0], `stored the parameter "url" as "https://mock.local/api/"`) {
+		t.Fatalf("expected a warning about the changed value, got %v", r.warnings)
+	}
+	m.storedParameters = nil
+	r.mustDestroy()
+
+	// config and config_json cannot both be set
+	config["config_json"] = `{"url":"https://mock.local/api"}`
+	if err := r.apply(config); err == nil || !strings.Contains(err.Error(), "cannot both be set") {
+		t.Fatalf("expected an error setting config and config_json, got %v", err)
+	}
+}
+
+func TestIntegrationInstance_mockTestOnApply(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	config := map[string]interface{}{
+		"name":               "mockinstance",
+		"integration_name":   "MockIntegration",
+		"config_json":        `{"insecure":false,"url":"https://mock.local/api"}`,
+		"secret_config_json": `{"apikey":"bad"}`,
+		"test_on_apply":      true,
+		"test_mode":          "warn",
+	}
+	r := tf.resource("xsoar_integration_instance")
+	r.mustApply(config)
+	if got := r.attrString("last_test_result"); got != "failure: Invalid API key" {
+		t.Fatalf("failed test was not recorded, got %q", got)
+	}
+
+	config["secret_config_json"] = `{"apikey":"123"}`
+	delete(config, "test_mode")
+	r.mustApply(config)
+	if got := r.attrString("last_test_result"); got != "success" {
+		t.Fatalf("passed test was not recorded, got %q", got)
+	}
+
+	// a failed test fails the apply, the instance itself is saved
+	config["secret_config_json"] = `{"apikey":"bad"}`
+	if err := r.apply(config); err == nil || !strings.Contains(err.Error(), "Invalid API key") {
+		t.Fatalf("expected the apply to fail with the message of the test, got %v", err)
+	}
+	if mockParameter(m.object("", "instances", r.attrString("id")), "apikey") != "bad" {
+		t.Fatal("integration instance was not saved before its test")
+	}
+}
+
+func TestIntegrationInstance_mockSettings(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	config := map[string]interface{}{
+		"name":                  "mockinstance",
+		"integration_name":      "MockFetchIntegration",
+		"config_json":           `{"url":"https://mock.local/api"}`,
+		"integration_log_level": "Debug",
+		"is_long_running":       true,
+		"engine_group":          "mockgroup",
+		"reset_context":         true,
+		"password_protected":    true,
+		"default_ignore":        true,
+		"is_fetch":              true,
+		"incident_type":         "Phishing",
+		"fetch_interval":        5,
+		"mirroring_direction":   "Incoming",
+	}
+	r := tf.resource("xsoar_integration_instance")
+	r.mustApply(config)
+	id := r.attrString("id")
+	instance := m.object("", "instances", id)
+	if instance["integrationLogLevel"] != "Debug" || instance["engineGroup"] != "mockgroup" || instance["isLongRunning"] != true {
+		t.Fatalf("instance settings were not sent: %v", instance)
+	}
+	if mockParameter(instance, "isFetch") != true || mockParameter(instance, "incidentFetchInterval") != "5" || mockParameter(instance, "mirror_direction") != "Incoming" {
+		t.Fatalf("fetch parameters were not sent: %v", instance["data"])
+	}
+	if r.attrString("config_json") != `{"url":"https://mock.local/api"}` {
+		t.Fatalf("fetch parameters leaked into config_json: %s", r.attrString("config_json"))
+	}
+
+	// settings changed in the UI are detected and reverted
+	instance["integrationLogLevel"] = ""
+	for _, item := range mockSlice(instance["data"]) {
+		if param := item.(map[string]interface{}); param["name"] == "isFetch" {
+			param["value"] = false
+		}
+	}
+	if err := r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if r.attrString("integration_log_level") != "Off" || r.attr("is_fetch") != false {
+		t.Fatal("drift on the server was not detected")
+	}
+	r.mustApply(config)
+	instance = m.object("", "instances", id)
+	if instance["integrationLogLevel"] != "Debug" || mockParameter(instance, "isFetch") != true {
+		t.Fatal("drift on the server was not reverted")
+	}
+	r.mustImport("mockinstance", "secret_config_json")
+
+	// a common parameter is only accepted by the integrations that have it
+	other := tf.resource("xsoar_integration_instance")
+	err := other.apply(map[string]interface{}{
+		"name":               "otherinstance",
+		"integration_name":   "MockIntegration",
+		"config_json":        `{"url":"https://mock.local/api"}`,
+		"secret_config_json": `{"apikey":"123"}`,
+		"is_fetch":           true,
+	})
+	if err == nil || !strings.Contains(err.Error(), `no parameter "isFetch"`) {
+		t.Fatalf("expected an error fetching with an integration that cannot fetch, got %v", err)
+	}
+}
+
+func TestIntegrationInstance_mockClassifiers(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	incoming := m.putObject("", "classifiers", map[string]interface{}{"name": "mockincoming", "type": "mapping-incoming"})
+	outgoing := m.putObject("", "classifiers", map[string]interface{}{"name": "mockoutgoing", "type": "mapping-outgoing"})
+	classifier := m.putObject("", "classifiers", map[string]interface{}{"name": "mockclassifier", "type": "classification"})
+
+	// the classifier and mappers are referenced by name or ID
+	config := map[string]interface{}{
+		"name":               "mockinstance",
+		"integration_name":   "MockIntegration",
+		"config_json":        `{"insecure":false,"url":"https://mock.local/api"}`,
+		"secret_config_json": `{"apikey":"123"}`,
+		"incoming_mapper_id": "mockincoming",
+		"outgoing_mapper_id": outgoing,
+		"mapping_id":         "mockclassifier",
+	}
+	r := tf.resource("xsoar_integration_instance")
+	r.mustApply(config)
+	id := r.attrString("id")
+	instance := m.object("", "instances", id)
+	if instance["incomingMapperId"] != incoming || instance["outgoingMapperId"] != outgoing || instance["mappingId"] != classifier {
+		t.Fatalf("classifier and mappers were not resolved to their IDs: %v", instance)
+	}
+	if r.attrString("incoming_mapper_id") != "mockincoming" {
+		t.Fatalf("reference by name was not kept, got %q", r.attrString("incoming_mapper_id"))
+	}
+
+	// a mapper changed on the server is detected
+	instance["incomingMapperId"] = ""
+	if err := r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if r.attrString("incoming_mapper_id") != "" {
+		t.Fatal("drift on the server was not detected")
+	}
+	r.mustApply(config)
+
+	// a mapper of the other direction is rejected
+	config["incoming_mapper_id"] = "mockoutgoing"
+	if err := r.apply(config); err == nil || !strings.Contains(err.Error(), `"mockoutgoing" is of type mapping-outgoing`) {
+		t.Fatalf("expected an error referencing an outgoing mapper as incoming, got %v", err)
+	}
+	// a mapper that does not exist yet may be created in the same apply, and only warns at plan time
+	config["incoming_mapper_id"] = "nosuchmapper"
+	if err := r.planOnly(config); err != nil {
+		t.Fatalf("planning a mapper that does not exist yet: %s", err)
+	}
+	if len(r.warnings) != 1 || !strings.Contains(r.warnings[0], "nosuchmapper") {
+		t.Fatalf("expected an unknown mapper warning, got %v", r.warnings)
+	}
+	if err := r.apply(config); err == nil {
+		t.Fatal("expected an error referencing a mapper that does not exist")
+	}
+}
+
+func TestIntegrationInstance_mockValidation(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	for _, c := range []struct {
+		name  string
+		patch map[string]interface{}
+		want  string
+	}{
+		{"unknown integration", map[string]interface{}{"integration_name": "MockIntegratoin"}, `Did you mean "MockIntegration"`},
+		{"unknown parameter", map[string]interface{}{"config_json": `{"insecure":false,"urll":"https://mock.local/api"}`}, `no parameter "urll". Did you mean "url"`},
+		{"missing parameter", map[string]interface{}{"secret_config_json": `{}`}, `requires parameter "apikey"`},
+		{"type mismatch", map[string]interface{}{"config_json": `{"insecure":"maybe","url":"https://mock.local/api"}`}, `Parameter "insecure": expected a boolean`},
+		{"credentials type", map[string]interface{}{"credentials": map[string]interface{}{"url": "mockcredential"}}, `"url" does not accept credentials`},
+	} {
+		config := map[string]interface{}{
+			"name":               "mockinstance",
+			"integration_name":   "MockIntegration",
+			"config_json":        `{"insecure":false,"url":"https://mock.local/api"}`,
+			"secret_config_json": `{"apikey":"123"}`,
+		}
+		for key, value := range c.patch {
+			config[key] = value
+		}
+		r := tf.resource("xsoar_integration_instance")
+		err := r.apply(config)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Fatalf("%s: expected an error containing %q, got %v", c.name, c.want, err)
+		}
+		if len(m.tenants[""].store("instances").list()) != 0 {
+			t.Fatalf("%s: integration instance was created despite the invalid configuration", c.name)
+		}
+	}
+}
+
+func TestIntegrationInstance_mockUnknownConfig(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	// values taken from other resources are unknown until apply, and are only checked to be set
+	config := map[string]interface{}{
+		"name":             "mockinstance",
+		"integration_name": "MockIntegration",
+		"config":           map[string]interface{}{"url": mockUnknown, "insecure": "false"},
+		"secret_config":    map[string]interface{}{"apikey": mockUnknown},
+	}
+	if err := tf.resource("xsoar_integration_instance").planOnly(config); err != nil {
+		t.Fatalf("planning unknown parameters: %s", err)
+	}
+	delete(config["secret_config"].(map[string]interface{}), "apikey")
+	err := tf.resource("xsoar_integration_instance").planOnly(config)
+	if err == nil || !strings.Contains(err.Error(), `requires parameter "apikey"`) {
+		t.Fatalf("expected a missing parameter error, got %v", err)
+	}
+	config["secret_config"] = map[string]interface{}{"apikey": mockUnknown}
+	config["config"] = map[string]interface{}{"url": mockUnknown, "insecure": "maybe"}
+	err = tf.resource("xsoar_integration_instance").planOnly(config)
+	if err == nil || !strings.Contains(err.Error(), `Parameter "insecure": expected a boolean`) {
+		t.Fatalf("expected a type mismatch error, got %v", err)
+	}
+}
+
+func TestIntegrationInstance_mockAccountIntegration(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+	m.addAccount("mockacc", "")
+	m.putObject("mockacc", "integrations", map[string]interface{}{
+		"name":              "MockTenantIntegration",
+		"category":          "Utilities",
+		"canGetSamples":     false,
+		"integrationScript": nil,
+		"configuration": []interface{}{
+			map[string]interface{}{"name": "url", "display": "Server URL", "type": float64(0), "required": true, "defaultValue": ""},
+		},
+	})
+
+	// an integration that is not installed fails the plan
+	r := tf.resource("xsoar_integration_instance")
+	err := r.planOnly(map[string]interface{}{"name": "mockinstance", "integration_name": "MockIntegratoin"})
+	if err == nil || !strings.Contains(err.Error(), `Did you mean "MockIntegration"`) {
+		t.Fatalf("expected an unknown integration error when planning, got %v", err)
+	}
+
+	// an integration installed in an account only is checked against the integrations of the account
+	config := map[string]interface{}{
+		"name":             "mockinstance",
+		"integration_name": "MockTenantIntegration",
+		"config":           map[string]interface{}{"url": "https://mock.local/api"},
+	}
+	if err = r.planOnly(config); err == nil || !strings.Contains(err.Error(), `No installed integration is named "MockTenantIntegration"`) {
+		t.Fatalf("expected the integration of an account to be unknown to the main tenant, got %v", err)
+	}
+	config["account"] = "mockacc"
+	config["config"] = map[string]interface{}{"urll": "https://mock.local/api"}
+	if err = r.planOnly(config); err == nil || !strings.Contains(err.Error(), `no parameter "urll". Did you mean "url"`) {
+		t.Fatalf("expected the parameters to be checked against the integration of the account, got %v", err)
+	}
+	config["config"] = map[string]interface{}{"url": "https://mock.local/api"}
+	r.mustApply(config)
+	if mockParameter(m.object("mockacc", "instances", r.attrString("id")), "url") != "https://mock.local/api" {
+		t.Fatal("instance was not created in the account")
+	}
+	r.mustDestroy()
+}
+
+// mockParameter returns the value of a configuration parameter stored on a mock integration instance
+func mockParameter(instance map[string]interface{}, name string) interface{} {
+	for _, item := range mockSlice(instance["data"]) {
+		if param := item.(map[string]interface{}); param["name"] == name {
+			return param["value"]
+		}
+	}
+	return nil
+}
+
 func testAccIntegrationInstanceResourcePreCheck(t *testing.T) {}
 
 func testAccCheckIntegrationInstanceResourceExists(r string) resource.TestCheckFunc {
@@ -0,0 +1,545 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// jobIntervalRegexp matches intervals like 30m, 4h, 1d or 2w
+var jobIntervalRegexp = regexp.MustCompile(`^([1-9][0-9]*)([mhdw])$`)
+
+var jobIntervalUnits = map[string]string{
+	"m": "minutes",
+	"h": "hours",
+	"d": "days",
+	"w": "weeks",
+}
+
+// jobDate maps a date returned by XSOAR onto the resource, keeping the prior value when it is the
+// same instant and leaving dates that were not configured null
+func jobDate(value string, prior types.String) types.String {
+	if prior.Null || prior.Unknown {
+		return types.String{Null: true}
+	}
+	priorTime, err := time.Parse(time.RFC3339, prior.Value)
+	if err != nil {
+		return types.String{Value: value}
+	}
+	valueTime, err := time.Parse(time.RFC3339, value)
+	if err == nil && valueTime.Equal(priorTime) {
+		return prior
+	}
+	return types.String{Value: value}
+}
+
+// jobRequest builds the job sent to XSOAR from the plan
+func jobRequest(ctx context.Context, plan Job, id string) map[string]interface{} {
+	job := map[string]interface{}{
+		"name":      plan.Name.Value,
+		"scheduled": false,
+		"recurrent": false,
+	}
+	if len(id) > 0 {
+		job["id"] = id
+		// -1 overwrites whatever version is stored on the server
+		job["version"] = -1
+	}
+	strs := map[string]types.String{
+		"playbookId": plan.PlaybookId,
+		"type":       plan.IncidentType,
+		"startDate":  plan.StartDate,
+		"endDate":    plan.EndDate,
+	}
+	for key, value := range strs {
+		if !value.Null && !value.Unknown {
+			job[key] = value.Value
+		}
+	}
+	if !plan.Cron.Null && !plan.Cron.Unknown {
+		job["scheduled"] = true
+		job["recurrent"] = true
+		job["cron"] = plan.Cron.Value
+		job["humanCron"] = map[string]interface{}{}
+	}
+	if !plan.Interval.Null && !plan.Interval.Unknown {
+		match := jobIntervalRegexp.FindStringSubmatch(plan.Interval.Value)
+		if match != nil {
+			period, _ := strconv.Atoi(match[1])
+			job["scheduled"] = true
+			job["recurrent"] = true
+			job["humanCron"] = map[string]interface{}{
+				"timePeriodType": jobIntervalUnits[match[2]],
+				"timePeriod":     period,
+			}
+		}
+	}
+	if plan.IsFeed.Value {
+		var selectedFeeds []string
+		if !plan.SelectedFeeds.Null && !plan.SelectedFeeds.Unknown {
+			plan.SelectedFeeds.ElementsAs(ctx, &selectedFeeds, true)
+		}
+		job["selectedFeeds"] = selectedFeeds
+		job["isAllFeeds"] = len(selectedFeeds) == 0
+	}
+	bools := map[string]types.Bool{
+		"isFeed":           plan.IsFeed,
+		"shouldTriggerNew": plan.ShouldTriggerNew,
+		"closePrevRun":     plan.ClosePrevRun,
+	}
+	for key, value := range bools {
+		if !value.Null && !value.Unknown {
+			job[key] = value.Value
+		}
+	}
+	if !plan.Tags.Null && !plan.Tags.Unknown {
+		var tags []string
+		plan.Tags.ElementsAs(ctx, &tags, true)
+		job["tags"] = tags
+	}
+	return job
+}
+
+// jobFromAPI maps a job returned by XSOAR onto the resource
+func jobFromAPI(job map[string]interface{}, prior Job) Job {
+	str := func(key string) string {
+		v, _ := job[key].(string)
+		return v
+	}
+	boolean := func(key string) types.Bool {
+		v, _ := job[key].(bool)
+		return types.Bool{Value: v}
+	}
+	result := Job{
+		Name:             types.String{Value: str("name")},
+		Id:               types.String{Value: str("id")},
+		PlaybookId:       optionalString(str("playbookId"), prior.PlaybookId),
+		IncidentType:     types.String{Value: str("type")},
+		Cron:             types.String{Null: true},
+		Interval:         types.String{Null: true},
+		StartDate:        jobDate(str("startDate"), prior.StartDate),
+		EndDate:          jobDate(str("endDate"), prior.EndDate),
+		IsFeed:           boolean("isFeed"),
+		SelectedFeeds:    stringSet(interfaceSlice(job["selectedFeeds"])),
+		ShouldTriggerNew: boolean("shouldTriggerNew"),
+		ClosePrevRun:     boolean("closePrevRun"),
+		Tags:             stringSet(interfaceSlice(job["tags"])),
+		Account:          prior.Account,
+	}
+	// no selected feeds means the job is triggered by all feeds
+	if len(result.SelectedFeeds.Elems) == 0 && (prior.SelectedFeeds.Null || prior.SelectedFeeds.Unknown) {
+		result.SelectedFeeds = types.Set{Null: true, ElemType: types.StringType}
+	}
+	if scheduled, _ := job["scheduled"].(bool); scheduled {
+		humanCron, _ := job["humanCron"].(map[string]interface{})
+		periodType, _ := humanCron["timePeriodType"].(string)
+		period, _ := humanCron["timePeriod"].(float64)
+		if len(periodType) > 0 && period > 0 {
+			for unit, name := range jobIntervalUnits {
+				if name == periodType {
+					result.Interval = types.String{Value: fmt.Sprintf("%d%s", int(period), unit)}
+				}
+			}
+		} else {
+			result.Cron = types.String{Value: str("cron")}
+		}
+	}
+	return result
+}
+
+type resourceJobType struct{}
+
+// GetSchema Resource schema
+func (r resourceJobType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"id": {
+				Type:          types.StringType,
+				Computed:      true,
+				Optional:      false,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"playbook_id": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"incident_type": {
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"cron": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"interval": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"start_date": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"end_date": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"is_feed": {
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+			},
+			"selected_feeds": {
+				Type:     types.SetType{ElemType: types.StringType},
+				Optional: true,
+			},
+			"should_trigger_new": {
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+			},
+			"close_prev_run": {
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+			},
+			"tags": {
+				Type:     types.SetType{ElemType: types.StringType},
+				Optional: true,
+				Computed: true,
+			},
+			"account": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourceJobType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceJob{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type resourceJob struct {
+	p provider
+}
+
+func (r resourceJob) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config Job
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Cron.Null && !config.Interval.Null {
+		resp.Diagnostics.AddError(
+			"Invalid job schedule",
+			"Only one of cron or interval can be set.",
+		)
+	}
+	if !config.Interval.Null && !config.Interval.Unknown && !jobIntervalRegexp.MatchString(config.Interval.Value) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("interval"),
+			"Invalid job interval",
+			"The interval must be a number followed by m, h, d or w, e.g. 30m or 4h, got: "+config.Interval.Value+".",
+		)
+	}
+	for name, value := range map[string]types.String{"start_date": config.StartDate, "end_date": config.EndDate} {
+		if value.Null || value.Unknown {
+			continue
+		}
+		if _, err := time.Parse(time.RFC3339, value.Value); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Invalid job date",
+				"The date must be in RFC 3339 format, e.g. 2022-08-01T00:00:00Z: "+err.Error(),
+			)
+		}
+	}
+	if !config.SelectedFeeds.Null && !config.SelectedFeeds.Unknown && len(config.SelectedFeeds.Elems) > 0 &&
+		!config.IsFeed.Unknown && !config.IsFeed.Value {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("selected_feeds"),
+			"Invalid Attribute Combination",
+			"Selected feeds can only be set on jobs with is_feed set to true.",
+		)
+	}
+}
+
+// searchJob returns the job with the given field value, or nil if there is none
+func (r resourceJob) searchJob(ctx context.Context, account types.String, field string, value string) (map[string]interface{}, error) {
+	var search struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	query := map[string]interface{}{"page": 0, "size": 100, "query": fmt.Sprintf("%s:%q", field, value)}
+	_, err := r.p.doRequest(ctx, "POST", accountPath(account, "/jobs/search"), query, &search)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range search.Data {
+		if job[field] == value {
+			return job, nil
+		}
+	}
+	return nil, nil
+}
+
+// Create a new resource
+func (r resourceJob) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan Job
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create
+	var job map[string]interface{}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/jobs"), jobRequest(ctx, plan, ""), &job)
+	if err != nil {
+		log.Println(err.Error())
+		if httpResponse != nil {
+			log.Println(httpResponse.Status)
+		}
+		resp.Diagnostics.AddError(
+			"Error creating job",
+			"Could not create job: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := jobFromAPI(job, plan)
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourceJob) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Job
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	job, err := r.searchJob(ctx, state.Account, "id", state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting job",
+			"Could not get job: "+err.Error(),
+		)
+		return
+	}
+	if job == nil {
+		log.Println("Job not found")
+		// Remove resource from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := jobFromAPI(job, state)
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourceJob) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan Job
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Job
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update
+	var job map[string]interface{}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/jobs"), jobRequest(ctx, plan, state.Id.Value), &job)
+	if err != nil {
+		log.Println(err.Error())
+		if httpResponse != nil {
+			log.Println(httpResponse.Status)
+		}
+		resp.Diagnostics.AddError(
+			"Error updating job",
+			"Could not update job: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := jobFromAPI(job, plan)
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourceJob) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state Job
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete
+	_, err := r.p.doRequest(ctx, "DELETE", accountPath(state.Account, "/jobs/"+url.PathEscape(state.Id.Value)), nil, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error deleting job",
+			"Could not delete job: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourceJob) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	accname := strings.Split(req.ID, ".")
+	var name string
+	account := types.String{Null: true}
+	if len(accname) == 1 {
+		name = req.ID
+	} else {
+		account = types.String{Value: accname[0]}
+		name = accname[1]
+	}
+	job, err := r.searchJob(ctx, account, "name", name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing job",
+			"Could not import job: "+err.Error(),
+		)
+		return
+	}
+	if job == nil {
+		resp.Diagnostics.AddError(
+			"Job not found",
+			fmt.Sprintf("Could not find job: %s", name),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := jobFromAPI(job, Job{
+		PlaybookId:    types.String{Null: true},
+		SelectedFeeds: types.Set{Null: true, ElemType: types.StringType},
+		StartDate:     types.String{Null: true},
+		EndDate:       types.String{Null: true},
+		Account:       account,
+	})
+	// XSOAR fills in dates that were never set with the zero time
+	for _, date := range []struct {
+		key   string
+		value *types.String
+	}{{"startDate", &result.StartDate}, {"endDate", &result.EndDate}} {
+		v, _ := job[date.key].(string)
+		if t, err := time.Parse(time.RFC3339, v); err == nil && !t.IsZero() {
+			*date.value = types.String{Value: v}
+		}
+	}
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
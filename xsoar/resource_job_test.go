@@ -0,0 +1,141 @@
+package xsoar
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestJob_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{
+			"name":          "MockJob",
+			"playbook_id":   "MockPlaybook",
+			"incident_type": "Mock Job",
+			"interval":      "4h",
+			"start_date":    "2022-08-01T02:00:00+02:00",
+			"tags":          []interface{}{"mock"},
+		}
+		importId := "MockJob"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".MockJob"
+		}
+		r := tf.resource("xsoar_job")
+		r.mustApply(config)
+		id := r.attrString("id")
+		job := m.object(acc, "jobs", id)
+		if job == nil {
+			t.Fatalf("job %s was not created in account %q", id, acc)
+		}
+		humanCron := mockObject(job["humanCron"])
+		if job["scheduled"] != true || humanCron["timePeriodType"] != "hours" || humanCron["timePeriod"] != float64(4) {
+			t.Fatalf("job was not scheduled every 4 hours, got %v", job)
+		}
+
+		// the server reports dates in UTC
+		job["startDate"] = "2022-08-01T00:00:00Z"
+		r.mustApply(config)
+
+		delete(config, "interval")
+		config["cron"] = "0 6 * * 1-5"
+		config["close_prev_run"] = true
+		config["end_date"] = "2023-08-01T00:00:00Z"
+		r.mustApply(config)
+		if r.attrString("id") != id {
+			t.Fatal("job was replaced when it should have been updated")
+		}
+		job = m.object(acc, "jobs", id)
+		if job["cron"] != "0 6 * * 1-5" || job["closePrevRun"] != true || job["endDate"] != "2023-08-01T00:00:00Z" {
+			t.Fatalf("job was not updated, got %v", job)
+		}
+
+		r.mustImport(importId, "start_date")
+		r.mustDestroy()
+		if m.object(acc, "jobs", id) != nil {
+			t.Fatal("found job when none was expected")
+		}
+	}
+}
+
+func TestJob_mockFeed(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	config := map[string]interface{}{
+		"name":               "MockFeedJob",
+		"playbook_id":        "MockPlaybook",
+		"is_feed":            true,
+		"selected_feeds":     []interface{}{"MockFeed_instance_1"},
+		"should_trigger_new": true,
+	}
+	r := tf.resource("xsoar_job")
+	r.mustApply(config)
+	job := m.object("", "jobs", r.attrString("id"))
+	if job["isFeed"] != true || job["isAllFeeds"] != false || job["scheduled"] != false {
+		t.Fatalf("unexpected feed job %v", job)
+	}
+	r.mustImport("MockFeedJob", "start_date")
+
+	delete(config, "selected_feeds")
+	r.mustApply(config)
+	if m.object("", "jobs", r.attrString("id"))["isAllFeeds"] != true {
+		t.Fatal("job was not triggered by all feeds")
+	}
+	r.mustDestroy()
+
+	invalid := []map[string]interface{}{
+		{"name": "MockJob", "cron": "* * * * *", "interval": "1h"},
+		{"name": "MockJob", "interval": "90s"},
+		{"name": "MockJob", "start_date": "tomorrow"},
+		{"name": "MockJob", "selected_feeds": []interface{}{"MockFeed_instance_1"}},
+	}
+	for _, config := range invalid {
+		if err := tf.resource("xsoar_job").apply(config); err == nil {
+			t.Fatalf("expected an error for %v", config)
+		}
+	}
+}
+
+func TestJob_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{"name": "MockJob", "playbook_id": "MockPlaybook", "interval": "1h"}
+
+	m.fail("POST", "jobs", http.StatusInternalServerError)
+	r := tf.resource("xsoar_job")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Could not create job") {
+		t.Fatalf("expected the server error to fail the create, got %v", err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("a job that failed to create was stored in state")
+	}
+	m.fail("POST", "jobs", 0)
+
+	r.mustApply(config)
+	m.fail("POST", "jobs/search", http.StatusInternalServerError)
+	if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get job") {
+		t.Fatalf("expected the server error to fail the read, got %v", err)
+	}
+	m.fail("POST", "jobs/search", 0)
+
+	// a job deleted outside of terraform is removed from state and cannot be imported
+	m.removeObject("", "jobs", r.attrString("id"))
+	if err = r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the removed job to be removed from state")
+	}
+	if err = r.importState("MockJob"); err == nil || !strings.Contains(err.Error(), "Could not find job") {
+		t.Fatalf("expected importing a missing job to fail, got %v", err)
+	}
+}
@@ -0,0 +1,458 @@
+package xsoar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// layoutServerKeys are managed through their own attributes or set by XSOAR on every save, and are
+// left out of the layout content
+var layoutServerKeys = []string{"id", "name", "group", "propagationLabels", "version", "modified", "fromServerVersion"}
+
+// layoutRequest builds the layouts container sent to XSOAR from the plan
+func layoutRequest(ctx context.Context, plan Layout, id string) (map[string]interface{}, error) {
+	var layout map[string]interface{}
+	if err := json.Unmarshal([]byte(plan.Content.Value), &layout); err != nil {
+		return nil, err
+	}
+	for _, key := range layoutServerKeys {
+		delete(layout, key)
+	}
+	layout["name"] = plan.Name.Value
+	layout["group"] = plan.Group.Value
+	// -1 overwrites whatever version is stored on the server
+	layout["version"] = -1
+	if len(id) > 0 {
+		layout["id"] = id
+	}
+	if !plan.PropagationLabels.Null && !plan.PropagationLabels.Unknown {
+		var propagationLabels []string
+		plan.PropagationLabels.ElementsAs(ctx, &propagationLabels, true)
+		layout["propagationLabels"] = propagationLabels
+	}
+	return layout, nil
+}
+
+// layoutFromAPI maps a layouts container returned by XSOAR onto the resource, keeping the prior
+// content when it only differs in formatting
+func layoutFromAPI(layout map[string]interface{}, prior Layout) (Layout, error) {
+	name, _ := layout["name"].(string)
+	id, _ := layout["id"].(string)
+	group, _ := layout["group"].(string)
+	b, err := json.Marshal(layout)
+	if err != nil {
+		return Layout{}, err
+	}
+	content, err := normalizeJSON(string(b), layoutServerKeys...)
+	if err != nil {
+		return Layout{}, err
+	}
+	result := Layout{
+		Name:              types.String{Value: name},
+		Id:                types.String{Value: id},
+		Group:             types.String{Value: group},
+		Content:           types.String{Value: content},
+		PropagationLabels: stringSet(interfaceSlice(layout["propagationLabels"])),
+		Account:           prior.Account,
+	}
+	if !prior.Content.Null && !prior.Content.Unknown && equalJSON(content, prior.Content.Value, layoutServerKeys...) {
+		result.Content = prior.Content
+	}
+	return result, nil
+}
+
+type resourceLayoutType struct{}
+
+// GetSchema Resource schema
+func (r resourceLayoutType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"id": {
+				Type:          types.StringType,
+				Computed:      true,
+				Optional:      false,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"group": {
+				Type:          types.StringType,
+				Optional:      true,
+				Computed:      true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown(), tfsdk.RequiresReplace()),
+				Validators:    []tfsdk.AttributeValidator{isOneOf{values: []string{"incident", "indicator"}}},
+			},
+			"content": {
+				// computed so that the plan can keep the state value when only formatting changed
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"propagation_labels": {
+				Type:          types.SetType{ElemType: types.StringType},
+				Optional:      true,
+				Computed:      true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"account": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourceLayoutType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceLayout{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type resourceLayout struct {
+	p provider
+}
+
+func (r resourceLayout) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config Layout
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Content.Unknown {
+		return
+	}
+	if config.Content.Null {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("content"),
+			"Missing layout content",
+			"The content of the layout must be set.",
+		)
+		return
+	}
+	var layout map[string]interface{}
+	if err := json.Unmarshal([]byte(config.Content.Value), &layout); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("content"),
+			"Invalid layout JSON",
+			"Could not parse layout: "+err.Error(),
+		)
+	}
+}
+
+func (r resourceLayout) ModifyPlan(ctx context.Context, req tfsdk.ModifyResourcePlanRequest, resp *tfsdk.ModifyResourcePlanResponse) {
+	// nothing to do on create or destroy
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan Layout
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	var state Layout
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Content.Unknown && equalJSON(plan.Content.Value, state.Content.Value, layoutServerKeys...) {
+		plan.Content = state.Content
+		diags = resp.Plan.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+// getLayout returns the layouts container with the given id, or nil if there is none
+func (r resourceLayout) getLayout(ctx context.Context, account types.String, id string) (map[string]interface{}, error) {
+	var layouts []map[string]interface{}
+	_, err := r.p.doRequest(ctx, "GET", accountPath(account, "/layouts"), nil, &layouts)
+	if err != nil {
+		return nil, err
+	}
+	for _, layout := range layouts {
+		if layout["id"] == id {
+			return layout, nil
+		}
+	}
+	return nil, nil
+}
+
+// saveLayout creates the layout, or updates it when id is set
+func (r resourceLayout) saveLayout(ctx context.Context, plan Layout, id string) (map[string]interface{}, error) {
+	if plan.Group.Null || plan.Group.Unknown {
+		plan.Group = types.String{Value: "incident"}
+	}
+	body, err := layoutRequest(ctx, plan, id)
+	if err != nil {
+		return nil, err
+	}
+	var layout map[string]interface{}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/layouts/save"), body, &layout)
+	if err != nil {
+		if httpResponse != nil {
+			log.Println(httpResponse.Status)
+		}
+		return nil, err
+	}
+	return layout, nil
+}
+
+// Create a new resource
+func (r resourceLayout) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan Layout
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create
+	layout, err := r.saveLayout(ctx, plan, "")
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error creating layout",
+			"Could not create layout: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result, err := layoutFromAPI(layout, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error parsing layout",
+			"Could not parse layout returned by the server: "+err.Error(),
+		)
+		return
+	}
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourceLayout) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Layout
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	layout, err := r.getLayout(ctx, state.Account, state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting layout",
+			"Could not get layout: "+err.Error(),
+		)
+		return
+	}
+	if layout == nil {
+		log.Println("Layout not found")
+		// Remove resource from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result, err := layoutFromAPI(layout, state)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error parsing layout",
+			"Could not parse layout returned by the server: "+err.Error(),
+		)
+		return
+	}
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourceLayout) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan Layout
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Layout
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update
+	layout, err := r.saveLayout(ctx, plan, state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error updating layout",
+			"Could not update layout: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result, err := layoutFromAPI(layout, plan)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error parsing layout",
+			"Could not parse layout returned by the server: "+err.Error(),
+		)
+		return
+	}
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourceLayout) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state Layout
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete
+	_, err := r.p.doRequest(ctx, "POST", accountPath(state.Account, "/layout/"+url.PathEscape(state.Id.Value)+"/remove"), nil, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error deleting layout",
+			"Could not delete layout: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourceLayout) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	accid := strings.SplitN(req.ID, ".", 2)
+	var id string
+	account := types.String{Null: true}
+	if len(accid) == 1 {
+		id = req.ID
+	} else {
+		account = types.String{Value: accid[0]}
+		id = accid[1]
+	}
+	layout, err := r.getLayout(ctx, account, id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing layout",
+			"Could not import layout: "+err.Error(),
+		)
+		return
+	}
+	if layout == nil {
+		resp.Diagnostics.AddError(
+			"Layout not found",
+			fmt.Sprintf("Could not find layout: %s", id),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result, err := layoutFromAPI(layout, Layout{Content: types.String{Null: true}, Account: account})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error parsing layout",
+			"Could not parse layout returned by the server: "+err.Error(),
+		)
+		return
+	}
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,120 @@
+package xsoar
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLayout_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	content := `{
+  "detailsV2": {"tabs": [{"id": "summary", "name": "Summary", "type": "summary"}]},
+  "quickView": {"sections": []}
+}`
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{
+			"name":    "Mock Layout",
+			"content": content,
+		}
+		r := tf.resource("xsoar_layout")
+		if acc != "" {
+			config["account"] = acc
+		}
+		r.mustApply(config)
+		id := r.attrString("id")
+		importId := id
+		if acc != "" {
+			importId = acc + "." + id
+		}
+		layout := m.object(acc, "layouts", id)
+		if layout == nil {
+			t.Fatalf("layout %s was not created in account %q", id, acc)
+		}
+		if layout["group"] != "incident" || r.attrString("group") != "incident" {
+			t.Fatalf("expected the incident group, got %v", layout["group"])
+		}
+		if mockObject(layout["detailsV2"])["tabs"] == nil {
+			t.Fatalf("layout sections were not sent, got %v", layout)
+		}
+
+		// reformatting the content is not a change
+		config["content"] = `{"quickView":{"sections":[]},"detailsV2":{"tabs":[{"type":"summary","name":"Summary","id":"summary"}]}}`
+		r.mustApply(config)
+		if m.object(acc, "layouts", id)["version"] != float64(1) {
+			t.Fatal("layout was saved when only the formatting changed")
+		}
+
+		// compact JSON with sorted keys is the form import produces
+		config["content"] = `{"close":{"sections":[]},"detailsV2":{"tabs":[]}}`
+		config["propagation_labels"] = []interface{}{"mock"}
+		r.mustApply(config)
+		if r.attrString("id") != id {
+			t.Fatal("layout was replaced when it should have been updated")
+		}
+		layout = m.object(acc, "layouts", id)
+		if layout["close"] == nil || layout["quickView"] != nil || len(mockSlice(layout["propagationLabels"])) != 1 {
+			t.Fatalf("layout was not updated, got %v", layout)
+		}
+
+		r.mustImport(importId)
+		r.mustDestroy()
+		if m.object(acc, "layouts", id) != nil {
+			t.Fatal("found layout when none was expected")
+		}
+	}
+
+	r := tf.resource("xsoar_layout")
+	if err := r.apply(map[string]interface{}{"name": "Mock", "content": "{"}); err == nil {
+		t.Fatal("expected an error for invalid layout JSON")
+	}
+	if err := r.apply(map[string]interface{}{"name": "Mock", "content": "{}", "group": "case"}); err == nil {
+		t.Fatal("expected an error for an unknown group")
+	}
+}
+
+func TestLayout_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{"name": "Mock Layout", "content": `{"detailsV2":{"tabs":[]}}`}
+
+	m.fail("POST", "layouts/save", http.StatusInternalServerError)
+	r := tf.resource("xsoar_layout")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Could not create layout") {
+		t.Fatalf("expected the server error to fail the create, got %v", err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("a layout that failed to create was stored in state")
+	}
+	m.fail("POST", "layouts/save", 0)
+
+	r.mustApply(config)
+	id := r.attrString("id")
+	m.fail("GET", "layouts", http.StatusServiceUnavailable)
+	if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get layout") {
+		t.Fatalf("expected the server error to fail the read, got %v", err)
+	}
+	m.fail("GET", "layouts", 0)
+
+	// a layout deleted outside of terraform is removed from state and cannot be imported
+	m.removeObject("", "layouts", id)
+	if err = r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the removed layout to be removed from state")
+	}
+	if err = r.importState(id); err == nil || !strings.Contains(err.Error(), "Could not find layout") {
+		t.Fatalf("expected importing a missing layout to fail, got %v", err)
+	}
+
+	if err = r.apply(map[string]interface{}{"name": "Mock Layout"}); err == nil || !strings.Contains(err.Error(), "Missing layout content") {
+		t.Fatalf("expected an error for missing layout content, got %v", err)
+	}
+}
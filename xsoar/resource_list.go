@@ -0,0 +1,411 @@
+package xsoar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var listTypes = []string{"plain_text", "json", "markdown", "html", "css", "csv"}
+
+// listFromAPI maps a list returned by XSOAR onto the resource, keeping the prior data when it
+// is the same JSON document
+func listFromAPI(list map[string]interface{}, prior List) List {
+	name, _ := list["name"].(string)
+	id, _ := list["id"].(string)
+	listType, _ := list["type"].(string)
+	data, _ := list["data"].(string)
+	result := List{
+		Name:    types.String{Value: name},
+		Id:      types.String{Value: id},
+		Type:    types.String{Value: listType},
+		Data:    types.String{Value: data},
+		Tags:    stringSet(interfaceSlice(list["tags"])),
+		Account: prior.Account,
+	}
+	if listType == "json" && !prior.Data.Null && !prior.Data.Unknown && equalJSON(data, prior.Data.Value) {
+		result.Data = prior.Data
+	}
+	return result
+}
+
+// getList returns the list with the given name, or nil if there is none
+func getList(ctx context.Context, p provider, account types.String, name string) (map[string]interface{}, error) {
+	var lists []map[string]interface{}
+	_, err := p.doRequest(ctx, "GET", accountPath(account, "/lists"), nil, &lists)
+	if err != nil {
+		return nil, err
+	}
+	for _, list := range lists {
+		if list["id"] == name || list["name"] == name {
+			return list, nil
+		}
+	}
+	return nil, nil
+}
+
+type resourceListType struct{}
+
+// GetSchema Resource schema
+func (r resourceListType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:          types.StringType,
+				Required:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+			"id": {
+				Type:          types.StringType,
+				Computed:      true,
+				Optional:      false,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"type": {
+				Type:          types.StringType,
+				Optional:      true,
+				Computed:      true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+				Validators:    []tfsdk.AttributeValidator{isOneOf{values: listTypes}},
+			},
+			"data": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"tags": {
+				Type:          types.SetType{ElemType: types.StringType},
+				Optional:      true,
+				Computed:      true,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"account": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourceListType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceList{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type resourceList struct {
+	p provider
+}
+
+func (r resourceList) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config List
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Type.Value != "json" || config.Data.Null || config.Data.Unknown {
+		return
+	}
+	var data interface{}
+	if err := json.Unmarshal([]byte(config.Data.Value), &data); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("data"),
+			"Invalid list JSON",
+			"Lists of type json must hold valid JSON: "+err.Error(),
+		)
+	}
+}
+
+// ModifyPlan keeps the prior data of a json list when the planned data is the same JSON document, which Terraform
+// accepts in place of the configured value
+func (r resourceList) ModifyPlan(ctx context.Context, req tfsdk.ModifyResourcePlanRequest, resp *tfsdk.ModifyResourcePlanResponse) {
+	// nothing to do on create or destroy
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan List
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	var state List
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Type.Value != "json" || !plan.Type.Equal(state.Type) || plan.Data.Unknown || plan.Data.Null {
+		return
+	}
+	if !plan.Data.Equal(state.Data) && equalJSON(plan.Data.Value, state.Data.Value) {
+		plan.Data = state.Data
+		diags = resp.Plan.Set(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+	}
+}
+
+// saveList creates or overwrites the list
+func (r resourceList) saveList(ctx context.Context, plan List) (map[string]interface{}, error) {
+	body := map[string]interface{}{
+		"id":   plan.Name.Value,
+		"name": plan.Name.Value,
+		"data": plan.Data.Value,
+		"type": "plain_text",
+		// -1 overwrites whatever version is stored on the server
+		"version": -1,
+	}
+	if !plan.Type.Null && !plan.Type.Unknown {
+		body["type"] = plan.Type.Value
+	}
+	if !plan.Tags.Null && !plan.Tags.Unknown {
+		var tags []string
+		plan.Tags.ElementsAs(ctx, &tags, true)
+		body["tags"] = tags
+	}
+	var list map[string]interface{}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/lists/save"), body, &list)
+	if err != nil {
+		if httpResponse != nil {
+			log.Println(httpResponse.Status)
+		}
+		return nil, err
+	}
+	return list, nil
+}
+
+// Create a new resource
+func (r resourceList) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan List
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// lists are saved by name, so make sure not to overwrite one that is not managed here
+	existing, err := getList(ctx, r.p, plan.Account, plan.Name.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error creating list",
+			"Could not get lists: "+err.Error(),
+		)
+		return
+	}
+	if existing != nil {
+		resp.Diagnostics.AddError(
+			"Error creating list",
+			fmt.Sprintf("A list named %s already exists, import it to manage it with Terraform.", plan.Name.Value),
+		)
+		return
+	}
+
+	// Create
+	list, err := r.saveList(ctx, plan)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error creating list",
+			"Could not create list: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := listFromAPI(list, plan)
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourceList) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state List
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	list, err := getList(ctx, r.p, state.Account, state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting list",
+			"Could not get list: "+err.Error(),
+		)
+		return
+	}
+	if list == nil {
+		log.Println("List not found")
+		// Remove resource from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := listFromAPI(list, state)
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourceList) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan List
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update
+	list, err := r.saveList(ctx, plan)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error updating list",
+			"Could not update list: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := listFromAPI(list, plan)
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourceList) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state List
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete
+	_, err := r.p.doRequest(ctx, "POST", accountPath(state.Account, "/lists/delete"), map[string]interface{}{"id": state.Id.Value}, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error deleting list",
+			"Could not delete list: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourceList) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	accname := strings.SplitN(req.ID, ".", 2)
+	var name string
+	account := types.String{Null: true}
+	if len(accname) == 1 {
+		name = req.ID
+	} else {
+		account = types.String{Value: accname[0]}
+		name = accname[1]
+	}
+	list, err := getList(ctx, r.p, account, name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing list",
+			"Could not import list: "+err.Error(),
+		)
+		return
+	}
+	if list == nil {
+		resp.Diagnostics.AddError(
+			"List not found",
+			fmt.Sprintf("Could not find list: %s", name),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := listFromAPI(list, List{Data: types.String{Null: true}, Account: account})
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
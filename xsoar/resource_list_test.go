@@ -0,0 +1,174 @@
+package xsoar
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestList_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{
+			"name": "MockAllowlist",
+			"data": "10.0.0.1\n10.0.0.2",
+			"tags": []interface{}{"mock"},
+		}
+		importId := "MockAllowlist"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".MockAllowlist"
+		}
+		r := tf.resource("xsoar_list")
+		r.mustApply(config)
+		id := r.attrString("id")
+		list := m.object(acc, "lists", id)
+		if list == nil {
+			t.Fatalf("list %s was not created in account %q", id, acc)
+		}
+		if r.attrString("type") != "plain_text" {
+			t.Fatalf("expected the plain_text type, got %v", r.attr("type"))
+		}
+
+		// changes made in the UI are detected
+		list["data"] = "10.0.0.3"
+		if err := r.refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if r.attrString("data") != "10.0.0.3" {
+			t.Fatalf("drift was not detected, got %v", r.attr("data"))
+		}
+		r.mustApply(config)
+		if m.object(acc, "lists", id)["data"] != "10.0.0.1\n10.0.0.2" {
+			t.Fatal("list data was not restored")
+		}
+
+		r.mustImport(importId)
+		r.mustDestroy()
+		if m.object(acc, "lists", id) != nil {
+			t.Fatal("found list when none was expected")
+		}
+	}
+}
+
+func TestList_mockJSON(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	config := map[string]interface{}{
+		"name": "MockRouting",
+		"type": "json",
+		"data": `{"phishing":"soc-team","malware":"ir-team"}`,
+	}
+	r := tf.resource("xsoar_list")
+	r.mustApply(config)
+	if r.attrString("data") != config["data"] {
+		t.Fatalf("server formatting was not ignored, got %v", r.attr("data"))
+	}
+
+	m.object("", "lists", "MockRouting")["data"] = `{"phishing": "soc-team"}`
+	if err := r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if r.attrString("data") != `{"phishing": "soc-team"}` {
+		t.Fatalf("drift was not detected, got %v", r.attr("data"))
+	}
+	r.mustApply(config)
+
+	// reformatting the data is not a change
+	stored := m.object("", "lists", "MockRouting")["data"]
+	m.object("", "lists", "MockRouting")["version"] = float64(7)
+	config["data"] = `{
+  "malware": "ir-team",
+  "phishing": "soc-team"
+}`
+	r.mustApply(config)
+	if list := m.object("", "lists", "MockRouting"); list["data"] != stored || list["version"] != float64(7) {
+		t.Fatalf("list was saved when only the formatting changed, got %v", list)
+	}
+	config["data"] = `{"phishing": "soc-team", "malware": "ir-team", "spam": "soc-team"}`
+	r.mustApply(config)
+	if list := m.object("", "lists", "MockRouting"); list["data"] == stored {
+		t.Fatalf("list was not updated, got %v", list)
+	}
+
+	config["data"] = "{"
+	if err := r.apply(config); err == nil {
+		t.Fatal("expected an error for invalid JSON data")
+	}
+	delete(config, "data")
+	if err := r.apply(config); err == nil || !strings.Contains(err.Error(), "Missing Configuration for Required Attribute") {
+		t.Fatalf("expected an error for missing data, got %v", err)
+	}
+
+	// lists that already exist are not overwritten
+	other := tf.resource("xsoar_list")
+	if err := other.apply(map[string]interface{}{"name": "MockRouting", "data": "x"}); err == nil {
+		t.Fatal("expected an error for an existing list")
+	}
+}
+
+func TestList_mockCSV(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	config := map[string]interface{}{
+		"name": "MockAssets",
+		"type": "csv",
+		"data": "hostname,owner\nweb01,soc-team",
+	}
+	r := tf.resource("xsoar_list")
+	r.mustApply(config)
+	if list := m.object("", "lists", "MockAssets"); list["type"] != "csv" || list["data"] != config["data"] {
+		t.Fatalf("csv list was not created, got %v", list)
+	}
+	r.mustImport("MockAssets")
+	r.mustDestroy()
+}
+
+func TestList_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{"name": "MockAllowlist", "data": "10.0.0.1"}
+
+	m.fail("POST", "lists/save", http.StatusInternalServerError)
+	r := tf.resource("xsoar_list")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Could not create list") {
+		t.Fatalf("expected the server error to fail the create, got %v", err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("a list that failed to create was stored in state")
+	}
+	m.fail("POST", "lists/save", 0)
+
+	r.mustApply(config)
+	m.fail("GET", "lists", http.StatusUnauthorized)
+	if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get list") {
+		t.Fatalf("expected the server error to fail the read, got %v", err)
+	}
+	m.fail("GET", "lists", 0)
+
+	// a list deleted outside of terraform is removed from state and cannot be imported
+	m.removeObject("", "lists", "MockAllowlist")
+	if err = r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the removed list to be removed from state")
+	}
+	if err = r.importState("MockAllowlist"); err == nil || !strings.Contains(err.Error(), "Could not find list") {
+		t.Fatalf("expected importing a missing list to fail, got %v", err)
+	}
+
+	if err = r.apply(map[string]interface{}{"name": "MockAllowlist", "type": "xml", "data": "<a/>"}); err == nil {
+		t.Fatal("expected an error for an unsupported list type")
+	}
+}
@@ -80,6 +80,9 @@ func (r resourceMapperType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Dia
 				Validators: []tfsdk.AttributeValidator{isValidDirection{}},
 			},
 		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
 	}, nil
 }
 
@@ -96,6 +99,13 @@ type resourceMapper struct {
 
 // Create a new resource
 func (r resourceMapper) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	if !r.p.configured {
 		resp.Diagnostics.AddError(
 			"Provider not configured",
@@ -106,7 +116,7 @@ func (r resourceMapper) Create(ctx context.Context, req tfsdk.CreateResourceRequ
 
 	// Retrieve values from plan
 	var plan Mapper
-	diags := req.Plan.Get(ctx, &plan)
+	diags = req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -131,7 +141,7 @@ func (r resourceMapper) Create(ctx context.Context, req tfsdk.CreateResourceRequ
 	}
 	if !plan.PropagationLabels.Unknown {
 		var props []string
-		plan.PropagationLabels.ElementsAs(ctx, props, true)
+		plan.PropagationLabels.ElementsAs(ctx, &props, true)
 		mapperRequest.SetPropagationLabels(props)
 	}
 	var mapper openapi.InstanceClassifier
@@ -183,6 +193,7 @@ func (r resourceMapper) Create(ctx context.Context, req tfsdk.CreateResourceRequ
 	}
 
 	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -192,9 +203,16 @@ func (r resourceMapper) Create(ctx context.Context, req tfsdk.CreateResourceRequ
 
 // Read resource information
 func (r resourceMapper) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get current state
 	var state Mapper
-	diags := req.State.Get(ctx, &state)
+	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -257,6 +275,7 @@ func (r resourceMapper) Read(ctx context.Context, req tfsdk.ReadResourceRequest,
 	}
 
 	// Generate resource state struct
+	result.Timeouts = state.Timeouts
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -266,9 +285,16 @@ func (r resourceMapper) Read(ctx context.Context, req tfsdk.ReadResourceRequest,
 
 // Update resource
 func (r resourceMapper) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get plan values
 	var plan Mapper
-	diags := req.Plan.Get(ctx, &plan)
+	diags = req.Plan.Get(ctx, &plan)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -302,7 +328,7 @@ func (r resourceMapper) Update(ctx context.Context, req tfsdk.UpdateResourceRequ
 	}
 	if !plan.PropagationLabels.Unknown {
 		var props []string
-		plan.PropagationLabels.ElementsAs(ctx, props, true)
+		plan.PropagationLabels.ElementsAs(ctx, &props, true)
 		mapperRequest.SetPropagationLabels(props)
 	}
 	var mapper openapi.InstanceClassifier
@@ -354,6 +380,7 @@ func (r resourceMapper) Update(ctx context.Context, req tfsdk.UpdateResourceRequ
 	}
 
 	// Set state
+	result.Timeouts = plan.Timeouts
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -363,9 +390,16 @@ func (r resourceMapper) Update(ctx context.Context, req tfsdk.UpdateResourceRequ
 
 // Delete resource
 func (r resourceMapper) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Get state
 	var state Mapper
-	diags := req.State.Get(ctx, &state)
+	diags = req.State.Get(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
 		return
@@ -459,6 +493,7 @@ func (r resourceMapper) ImportState(ctx context.Context, req tfsdk.ImportResourc
 	}
 
 	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
 	diags = resp.State.Set(ctx, result)
 	resp.Diagnostics.Append(diags...)
 	if resp.Diagnostics.HasError() {
@@ -3,7 +3,7 @@ package xsoar
 import (
 	"context"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -18,7 +18,7 @@ func TestAccMapper_basic(t *testing.T) {
 		PreCheck: func() { testAccMapperResourcePreCheck(t) },
 		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
 			"xsoar": func() (tfprotov6.ProviderServer, error) {
-				return tfsdk.NewProtocol6Server(New()), nil
+				return providerserver.NewProtocol6WithError(New()())()
 			},
 		},
 		CheckDestroy: testAccCheckMapperResourceDestroy(rName),
@@ -37,6 +37,48 @@ func TestAccMapper_basic(t *testing.T) {
 	})
 }
 
+func TestMapper_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{"name": "mockmapper", "direction": "incoming"}
+		importId := "mockmapper"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".mockmapper"
+		}
+		r := tf.resource("xsoar_mapper")
+		r.mustApply(config)
+		id := r.attrString("id")
+		if got := m.object(acc, "classifiers", id)["type"]; got != "mapping-incoming" {
+			t.Fatalf("expected an incoming mapper in account %q, got %v", acc, got)
+		}
+
+		config["direction"] = "outgoing"
+		config["mapping"] = `{"dbot_classification_incident_type_all":{"dontMapEventToLabels":true,"internalMapping":{}}}`
+		r.mustApply(config)
+		if r.attrString("id") != id {
+			t.Fatal("mapper was replaced when it should have been updated")
+		}
+		if got := m.object(acc, "classifiers", id)["type"]; got != "mapping-outgoing" {
+			t.Fatalf("mapper direction was not updated, got %v", got)
+		}
+
+		r.mustImport(importId)
+		r.mustDestroy()
+		if m.object(acc, "classifiers", id) != nil {
+			t.Fatal("found mapper when none was expected")
+		}
+	}
+
+	if err := tf.resource("xsoar_mapper").apply(map[string]interface{}{"name": "mockmapper", "direction": "sideways"}); err == nil {
+		t.Fatal("expected an invalid direction to be rejected")
+	}
+}
+
 func testAccMapperResourcePreCheck(t *testing.T) {}
 
 func testAccCheckMapperResourceExists(r string) resource.TestCheckFunc {
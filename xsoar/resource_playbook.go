@@ -0,0 +1,527 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"gopkg.in/yaml.v3"
+)
+
+// playbookServerKeys are set by XSOAR on every save or export and are left out when comparing and importing
+// playbooks
+var playbookServerKeys = []string{"id", "version", "fromversion", "sourceplaybookid", "modified", "created", "packID", "packName", "itemVersion"}
+
+// parsePlaybookYAML decodes a playbook and returns it in a canonical form along with its name
+func parsePlaybookYAML(content string) (map[string]interface{}, string, error) {
+	var playbook map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &playbook); err != nil {
+		return nil, "", err
+	}
+	if playbook == nil {
+		return nil, "", fmt.Errorf("playbook is empty")
+	}
+	name, ok := playbook["name"].(string)
+	if !ok || len(name) == 0 {
+		return nil, "", fmt.Errorf("playbook has no name")
+	}
+	return playbook, name, nil
+}
+
+// normalizePlaybookYAML re-encodes a playbook with sorted keys and without server managed keys so
+// that formatting and server side bookkeeping do not show up as changes
+func normalizePlaybookYAML(content string) (string, error) {
+	playbook, _, err := parsePlaybookYAML(content)
+	if err != nil {
+		return "", err
+	}
+	for _, key := range playbookServerKeys {
+		delete(playbook, key)
+	}
+	b, err := yaml.Marshal(playbook)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// equalPlaybookYAML reports whether two playbooks only differ in formatting or server managed keys
+func equalPlaybookYAML(a, b string) bool {
+	normalizedA, err := normalizePlaybookYAML(a)
+	if err != nil {
+		return false
+	}
+	normalizedB, err := normalizePlaybookYAML(b)
+	if err != nil {
+		return false
+	}
+	return normalizedA == normalizedB
+}
+
+type resourcePlaybookType struct{}
+
+// GetSchema Resource schema
+func (r resourcePlaybookType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:     types.StringType,
+				Computed: true,
+			},
+			"id": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"content": {
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"path": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"account": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourcePlaybookType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourcePlaybook{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type resourcePlaybook struct {
+	p provider
+}
+
+func (r resourcePlaybook) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config Playbook
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Content.Unknown || config.Path.Unknown {
+		return
+	}
+	if config.Content.Null == config.Path.Null {
+		resp.Diagnostics.AddError(
+			"Invalid playbook source",
+			"Exactly one of content or path must be set.",
+		)
+		return
+	}
+	if !config.Content.Null {
+		if _, _, err := parsePlaybookYAML(config.Content.Value); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("content"),
+				"Invalid playbook YAML",
+				"Could not parse playbook: "+err.Error(),
+			)
+		}
+	}
+}
+
+// ModifyPlan reads the playbook file and keeps the prior content when the YAML is unchanged apart
+// from formatting, so that only real changes to the playbook are planned
+func (r resourcePlaybook) ModifyPlan(ctx context.Context, req tfsdk.ModifyResourcePlanRequest, resp *tfsdk.ModifyResourcePlanResponse) {
+	// nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config Playbook
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	var plan Playbook
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	content := config.Content
+	if !config.Path.Null {
+		if config.Path.Unknown {
+			content = types.String{Unknown: true}
+		} else {
+			b, err := os.ReadFile(config.Path.Value)
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(
+					path.Root("path"),
+					"Error reading playbook",
+					"Could not read playbook file: "+err.Error(),
+				)
+				return
+			}
+			content = types.String{Value: string(b)}
+		}
+	}
+
+	if content.Unknown || content.Null {
+		plan.Content = types.String{Unknown: true}
+		plan.Name = types.String{Unknown: true}
+	} else {
+		_, name, err := parsePlaybookYAML(content.Value)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid playbook YAML",
+				"Could not parse playbook: "+err.Error(),
+			)
+			return
+		}
+		plan.Content = content
+		plan.Name = types.String{Value: name}
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state Playbook
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !plan.Content.Unknown && equalPlaybookYAML(plan.Content.Value, state.Content.Value) {
+			plan.Content = state.Content
+		}
+		// saving over the existing id keeps the playbook's identity
+		plan.Id = state.Id
+	}
+
+	diags = resp.Plan.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// savePlaybook uploads the playbook YAML, overwriting the playbook with the given id if set
+func (r resourcePlaybook) savePlaybook(ctx context.Context, account types.String, id string, content string) (map[string]interface{}, error) {
+	body := []byte(content)
+	if len(id) > 0 {
+		playbook, _, err := parsePlaybookYAML(content)
+		if err != nil {
+			return nil, err
+		}
+		playbook["id"] = id
+		body, err = yaml.Marshal(playbook)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var playbook map[string]interface{}
+	httpResponse, err := r.p.doUpload(ctx, accountPath(account, "/playbook/save/yaml"), "file", "playbook.yml", body, &playbook)
+	if err != nil {
+		if httpResponse != nil {
+			log.Println(httpResponse.Status)
+		}
+		return nil, err
+	}
+	return playbook, nil
+}
+
+// getPlaybookYAML exports the playbook with the given id
+func (r resourcePlaybook) getPlaybookYAML(ctx context.Context, account types.String, id string) (string, error) {
+	var b []byte
+	_, err := r.p.doRequest(ctx, "GET", accountPath(account, "/playbook/"+url.PathEscape(id)+"/yaml"), nil, &b)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Create a new resource
+func (r resourcePlaybook) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan Playbook
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create
+	playbook, err := r.savePlaybook(ctx, plan.Account, "", plan.Content.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error creating playbook",
+			"Could not create playbook: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	id, _ := playbook["id"].(string)
+	result := Playbook{
+		Name:    plan.Name,
+		Id:      types.String{Value: id},
+		Content: plan.Content,
+		Path:    plan.Path,
+		Account: plan.Account,
+	}
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourcePlaybook) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Playbook
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	content, err := r.getPlaybookYAML(ctx, state.Account, state.Id.Value)
+	if err != nil {
+		if isNotFound(err) {
+			log.Println("Playbook not found")
+			// Remove resource from state
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting playbook",
+			"Could not get playbook: "+err.Error(),
+		)
+		return
+	}
+	_, name, err := parsePlaybookYAML(content)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error parsing playbook",
+			"Could not parse playbook returned by the server: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := Playbook{
+		Name:    types.String{Value: name},
+		Id:      state.Id,
+		Content: types.String{Value: content},
+		Path:    state.Path,
+		Account: state.Account,
+	}
+	if equalPlaybookYAML(content, state.Content.Value) {
+		result.Content = state.Content
+	}
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourcePlaybook) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan Playbook
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Playbook
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update
+	_, err := r.savePlaybook(ctx, plan.Account, state.Id.Value, plan.Content.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error updating playbook",
+			"Could not update playbook: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := Playbook{
+		Name:    plan.Name,
+		Id:      state.Id,
+		Content: plan.Content,
+		Path:    plan.Path,
+		Account: plan.Account,
+	}
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourcePlaybook) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state Playbook
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete
+	_, err := r.p.doRequest(ctx, "POST", accountPath(state.Account, "/playbook/delete"), map[string]interface{}{"id": state.Id.Value}, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error deleting playbook",
+			"Could not delete playbook: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourcePlaybook) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	accname := strings.SplitN(req.ID, ".", 2)
+	var name string
+	account := types.String{Null: true}
+	if len(accname) == 1 {
+		name = req.ID
+	} else {
+		account = types.String{Value: accname[0]}
+		name = accname[1]
+	}
+
+	// Find the playbook id by name
+	var search struct {
+		Playbooks []map[string]interface{} `json:"playbooks"`
+	}
+	query := map[string]interface{}{"query": fmt.Sprintf("name:%q", name)}
+	_, err := r.p.doRequest(ctx, "POST", accountPath(account, "/playbook/search"), query, &search)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing playbook",
+			"Could not search playbooks: "+err.Error(),
+		)
+		return
+	}
+	var id string
+	for _, playbook := range search.Playbooks {
+		if playbook["name"] == name {
+			id, _ = playbook["id"].(string)
+			break
+		}
+	}
+	if len(id) == 0 {
+		resp.Diagnostics.AddError(
+			"Error importing playbook",
+			"Could not find playbook "+name,
+		)
+		return
+	}
+	content, err := r.getPlaybookYAML(ctx, account, id)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing playbook",
+			"Could not import playbook: "+err.Error(),
+		)
+		return
+	}
+	// the playbook is imported as content saving it again would not change
+	content, err = normalizePlaybookYAML(content)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing playbook",
+			"Could not parse playbook returned by the server: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := Playbook{
+		Name:    types.String{Value: name},
+		Id:      types.String{Value: id},
+		Content: types.String{Value: content},
+		Path:    types.String{Null: true},
+		Account: account,
+	}
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,136 @@
+package xsoar
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const mockPlaybookYAML = `name: mockplaybook
+description: created by the mock test
+starttaskid: "0"
+tasks:
+  "0":
+    id: "0"
+    type: start
+    nexttasks:
+      '#none#':
+      - "1"
+  "1":
+    id: "1"
+    type: regular
+    task:
+      name: Print
+      script: Print
+`
+
+func TestPlaybook_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	// content in the form playbooks are imported in, so that the import matches it exactly
+	canonical, err := normalizePlaybookYAML(mockPlaybookYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{"content": canonical}
+		importId := "mockplaybook"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".mockplaybook"
+		}
+		r := tf.resource("xsoar_playbook")
+		r.mustApply(config)
+		id := r.attrString("id")
+		if m.object(acc, "playbooks", id) == nil {
+			t.Fatalf("playbook %s was not created in account %q", id, acc)
+		}
+		if r.attrString("name") != "mockplaybook" {
+			t.Fatalf("expected name mockplaybook, got %s", r.attrString("name"))
+		}
+
+		// reformatting the YAML is not a change
+		config["content"] = strings.Replace(mockPlaybookYAML, "'#none#':\n      - \"1\"", "'#none#': [\"1\"]", 1)
+		r.mustApply(config)
+		if v := m.object(acc, "playbooks", id)["version"]; v != float64(1) {
+			t.Fatalf("playbook was saved again for a formatting change, version %v", v)
+		}
+
+		config["content"] = strings.Replace(canonical, "created by", "updated by", 1)
+		r.mustApply(config)
+		if r.attrString("id") != id {
+			t.Fatal("playbook was replaced when it should have been updated")
+		}
+		if !strings.Contains(m.object(acc, "playbooks", id)["yaml"].(string), "updated by") {
+			t.Fatal("playbook was not updated")
+		}
+
+		// a change made on the server is detected and reverted
+		m.object(acc, "playbooks", id)["yaml"] = mockPlaybookYAML
+		if err := r.refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if r.attrString("content") != mockPlaybookYAML {
+			t.Fatal("drift on the server was not detected")
+		}
+		r.mustApply(config)
+		if !strings.Contains(m.object(acc, "playbooks", id)["yaml"].(string), "updated by") {
+			t.Fatal("drift on the server was not reverted")
+		}
+
+		r.mustImport(importId)
+		r.mustDestroy()
+		if m.object(acc, "playbooks", id) != nil {
+			t.Fatal("found playbook when none was expected")
+		}
+	}
+
+	// only the first period of the import ID separates the account from the name
+	dotted := strings.Replace(canonical, "name: mockplaybook", "name: mock.playbook", 1)
+	r := tf.resource("xsoar_playbook")
+	r.mustApply(map[string]interface{}{"content": dotted, "account": "mockacc"})
+	r.mustImport("mockacc.mock.playbook")
+	if err := r.importState("mockacc.missing"); err == nil || !strings.Contains(err.Error(), "Could not find playbook missing") {
+		t.Fatalf("expected importing a missing playbook to fail, got %v", err)
+	}
+	r.mustDestroy()
+}
+
+func TestPlaybook_mockPath(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+
+	file := filepath.Join(t.TempDir(), "playbook.yml")
+	if err := os.WriteFile(file, []byte(mockPlaybookYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r := tf.resource("xsoar_playbook")
+	r.mustApply(map[string]interface{}{"path": file})
+	id := r.attrString("id")
+	if r.attrString("content") != mockPlaybookYAML {
+		t.Fatal("playbook content was not read from path")
+	}
+
+	if err := os.WriteFile(file, []byte(strings.Replace(mockPlaybookYAML, "created by", "updated by", 1)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r.mustApply(map[string]interface{}{"path": file})
+	if !strings.Contains(m.object("", "playbooks", id)["yaml"].(string), "updated by") {
+		t.Fatal("playbook was not updated after the file changed")
+	}
+	r.mustDestroy()
+
+	r = tf.resource("xsoar_playbook")
+	if err := r.apply(map[string]interface{}{"path": file, "content": mockPlaybookYAML}); err == nil {
+		t.Fatal("expected an error when both content and path are set")
+	}
+	if err := r.apply(map[string]interface{}{"content": "description: no name\n"}); err == nil {
+		t.Fatal("expected an error for a playbook without a name")
+	}
+}
@@ -0,0 +1,546 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// preprocessActions maps the action names of the resource to the ones used by XSOAR
+var preprocessActions = map[string]string{
+	"drop":            "drop",
+	"drop_and_update": "dropAndUpdate",
+	"link":            "link",
+	"link_and_update": "linkAndUpdate",
+	"run_script":      "script",
+}
+
+// preprocessFilters converts conditions into XSOAR filters, where every condition must match
+func preprocessFilters(conditions []PreprocessCondition) []interface{} {
+	filters := []interface{}{}
+	for _, c := range conditions {
+		filter := map[string]interface{}{
+			"left": map[string]interface{}{
+				"value":     map[string]interface{}{"simple": c.Field.Value},
+				"isContext": true,
+			},
+			"operator": c.Operator.Value,
+		}
+		if !c.Value.Null && !c.Value.Unknown {
+			filter["right"] = map[string]interface{}{
+				"value": map[string]interface{}{"simple": c.Value.Value},
+			}
+		}
+		filters = append(filters, []interface{}{filter})
+	}
+	return filters
+}
+
+// preprocessConditions converts XSOAR filters back into conditions
+func preprocessConditions(filters interface{}) []PreprocessCondition {
+	conditions := []PreprocessCondition{}
+	for _, group := range interfaceSlice(filters) {
+		for _, item := range interfaceSlice(group) {
+			filter, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			left, _ := filter["left"].(map[string]interface{})
+			leftValue, _ := left["value"].(map[string]interface{})
+			field, _ := leftValue["simple"].(string)
+			operator, _ := filter["operator"].(string)
+			condition := PreprocessCondition{
+				Field:    types.String{Value: field},
+				Operator: types.String{Value: operator},
+				Value:    types.String{Null: true},
+			}
+			if right, ok := filter["right"].(map[string]interface{}); ok {
+				rightValue, _ := right["value"].(map[string]interface{})
+				if value, ok := rightValue["simple"].(string); ok {
+					condition.Value = types.String{Value: value}
+				}
+			}
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions
+}
+
+// preprocessRuleRequest builds the rule sent to XSOAR from the plan
+func preprocessRuleRequest(plan PreprocessRule, id string) map[string]interface{} {
+	rule := map[string]interface{}{
+		"name":                  plan.Name.Value,
+		"action":                preprocessActions[plan.Action.Value],
+		"enabled":               true,
+		"newEventFilters":       preprocessFilters(plan.Conditions),
+		"existingEventsFilters": preprocessFilters(plan.ExistingConditions),
+	}
+	if len(id) > 0 {
+		rule["id"] = id
+		// -1 overwrites whatever version is stored on the server
+		rule["version"] = -1
+	}
+	if !plan.Enabled.Null && !plan.Enabled.Unknown {
+		rule["enabled"] = plan.Enabled.Value
+	}
+	if !plan.ScriptName.Null && !plan.ScriptName.Unknown {
+		rule["scriptName"] = plan.ScriptName.Value
+	}
+	return rule
+}
+
+// preprocessRuleFromAPI maps a rule returned by XSOAR onto the resource
+func preprocessRuleFromAPI(rule map[string]interface{}, index int64, prior PreprocessRule) PreprocessRule {
+	name, _ := rule["name"].(string)
+	id, _ := rule["id"].(string)
+	enabled, _ := rule["enabled"].(bool)
+	action, _ := rule["action"].(string)
+	scriptName, _ := rule["scriptName"].(string)
+	result := PreprocessRule{
+		Name:               types.String{Value: name},
+		Id:                 types.String{Value: id},
+		Enabled:            types.Bool{Value: enabled},
+		Action:             types.String{Value: action},
+		ScriptName:         optionalString(scriptName, prior.ScriptName),
+		Index:              types.Int64{Value: index},
+		Conditions:         preprocessConditions(rule["newEventFilters"]),
+		ExistingConditions: preprocessConditions(rule["existingEventsFilters"]),
+		Account:            prior.Account,
+	}
+	for k, v := range preprocessActions {
+		if v == action {
+			result.Action = types.String{Value: k}
+		}
+	}
+	return result
+}
+
+type resourcePreprocessRuleType struct{}
+
+// GetSchema Resource schema
+func (r resourcePreprocessRuleType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	var actions []string
+	for action := range preprocessActions {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	conditionAttributes := map[string]tfsdk.Attribute{
+		"field": {
+			Type:     types.StringType,
+			Required: true,
+		},
+		"operator": {
+			Type:     types.StringType,
+			Required: true,
+		},
+		"value": {
+			Type:     types.StringType,
+			Optional: true,
+		},
+	}
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"id": {
+				Type:          types.StringType,
+				Computed:      true,
+				Optional:      false,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"enabled": {
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+			},
+			"action": {
+				Type:       types.StringType,
+				Required:   true,
+				Validators: []tfsdk.AttributeValidator{isOneOf{values: actions}},
+			},
+			"script_name": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"index": {
+				Type:     types.Int64Type,
+				Required: true,
+			},
+			"account": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+			"condition": {
+				NestingMode: tfsdk.BlockNestingModeList,
+				Attributes:  conditionAttributes,
+			},
+			"existing_condition": {
+				NestingMode: tfsdk.BlockNestingModeList,
+				Attributes:  conditionAttributes,
+			},
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourcePreprocessRuleType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourcePreprocessRule{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type resourcePreprocessRule struct {
+	p provider
+}
+
+func (r resourcePreprocessRule) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config PreprocessRule
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Index.Unknown && !config.Index.Null && config.Index.Value < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("index"),
+			"Invalid rule index",
+			"The index of the rule starts at 1.",
+		)
+	}
+	if config.Action.Unknown {
+		return
+	}
+	if config.Action.Value == "run_script" && config.ScriptName.Null {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("script_name"),
+			"Missing script name",
+			"Rules with the run_script action require a script_name.",
+		)
+	}
+	if config.Action.Value != "run_script" && !config.ScriptName.Null {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("script_name"),
+			"Invalid Attribute Combination",
+			"A script_name can only be set on rules with the run_script action.",
+		)
+	}
+	if (config.Action.Value == "drop" || config.Action.Value == "run_script") && len(config.ExistingConditions) > 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("existing_condition"),
+			"Invalid Attribute Combination",
+			"Existing incident conditions can only be set on rules with the drop_and_update, link or link_and_update actions.",
+		)
+	}
+}
+
+// listPreprocessRules returns the rules of an account in the order XSOAR applies them
+func (r resourcePreprocessRule) listPreprocessRules(ctx context.Context, account types.String) ([]map[string]interface{}, error) {
+	var rules []map[string]interface{}
+	_, err := r.p.doRequest(ctx, "GET", accountPath(account, "/preprocess/rules"), nil, &rules)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		a, _ := rules[i]["index"].(float64)
+		b, _ := rules[j]["index"].(float64)
+		return a < b
+	})
+	return rules, nil
+}
+
+// getPreprocessRule returns the rule with the given field value along with its 1-based index, or nil if there is none
+func (r resourcePreprocessRule) getPreprocessRule(ctx context.Context, account types.String, field string, value string) (map[string]interface{}, int64, error) {
+	rules, err := r.listPreprocessRules(ctx, account)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, rule := range rules {
+		if rule[field] == value {
+			return rule, int64(i + 1), nil
+		}
+	}
+	return nil, 0, nil
+}
+
+// moveRule reorders the rules of an account so that the rule with the given id is at the given 1-based index,
+// or last when there are fewer rules
+func (r resourcePreprocessRule) moveRule(ctx context.Context, account types.String, id string, index int64) error {
+	rules, err := r.listPreprocessRules(ctx, account)
+	if err != nil {
+		return err
+	}
+	var current []string
+	var others []string
+	for _, rule := range rules {
+		ruleId, _ := rule["id"].(string)
+		current = append(current, ruleId)
+		if ruleId != id {
+			others = append(others, ruleId)
+		}
+	}
+	position := int(index - 1)
+	if position > len(others) {
+		position = len(others)
+	}
+	order := append([]string{}, others[:position]...)
+	order = append(order, id)
+	order = append(order, others[position:]...)
+	if equalSliceString(order, current) {
+		return nil
+	}
+	log.Printf("moving pre-process rule %s to index %d", id, index)
+	_, err = r.p.doRequest(ctx, "POST", accountPath(account, "/preprocess/rules/order"), map[string]interface{}{"rulesOrder": order}, nil)
+	return err
+}
+
+// saveRule creates or updates the rule and moves it to the planned index
+func (r resourcePreprocessRule) saveRule(ctx context.Context, plan PreprocessRule, id string) (PreprocessRule, error) {
+	var rule map[string]interface{}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/preprocess/rule"), preprocessRuleRequest(plan, id), &rule)
+	if err != nil {
+		if httpResponse != nil {
+			log.Println(httpResponse.Status)
+		}
+		return PreprocessRule{}, err
+	}
+	ruleId, _ := rule["id"].(string)
+	if err = r.moveRule(ctx, plan.Account, ruleId, plan.Index.Value); err != nil {
+		return PreprocessRule{}, fmt.Errorf("could not reorder rules: %w", err)
+	}
+	return preprocessRuleFromAPI(rule, plan.Index.Value, plan), nil
+}
+
+// Create a new resource
+func (r resourcePreprocessRule) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan PreprocessRule
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create
+	result, err := r.saveRule(ctx, plan, "")
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error creating pre-process rule",
+			"Could not create pre-process rule: "+err.Error(),
+		)
+		return
+	}
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourcePreprocessRule) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state PreprocessRule
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	rules, err := r.listPreprocessRules(ctx, state.Account)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting pre-process rule",
+			"Could not get pre-process rule: "+err.Error(),
+		)
+		return
+	}
+	var rule map[string]interface{}
+	var index int64
+	for i, r := range rules {
+		if r["id"] == state.Id.Value {
+			rule, index = r, int64(i+1)
+			break
+		}
+	}
+	if rule == nil {
+		log.Println("Pre-process rule not found")
+		// Remove resource from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := preprocessRuleFromAPI(rule, index, state)
+	// a rule asked to be beyond the last one is where it should be
+	if index == int64(len(rules)) && index < state.Index.Value {
+		result.Index = state.Index
+	}
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourcePreprocessRule) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan PreprocessRule
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state PreprocessRule
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update
+	result, err := r.saveRule(ctx, plan, state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error updating pre-process rule",
+			"Could not update pre-process rule: "+err.Error(),
+		)
+		return
+	}
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourcePreprocessRule) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state PreprocessRule
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete
+	_, err := r.p.doRequest(ctx, "DELETE", accountPath(state.Account, "/preprocess/rule/"+url.PathEscape(state.Id.Value)), nil, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error deleting pre-process rule",
+			"Could not delete pre-process rule: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourcePreprocessRule) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	accname := strings.Split(req.ID, ".")
+	var name string
+	account := types.String{Null: true}
+	if len(accname) == 1 {
+		name = req.ID
+	} else {
+		account = types.String{Value: accname[0]}
+		name = accname[1]
+	}
+	rule, index, err := r.getPreprocessRule(ctx, account, "name", name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing pre-process rule",
+			"Could not import pre-process rule: "+err.Error(),
+		)
+		return
+	}
+	if rule == nil {
+		resp.Diagnostics.AddError(
+			"Pre-process rule not found",
+			fmt.Sprintf("Could not find pre-process rule: %s", name),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := preprocessRuleFromAPI(rule, index, PreprocessRule{ScriptName: types.String{Null: true}, Account: account})
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,149 @@
+package xsoar
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPreprocessRule_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		// a rule created in the UI
+		unmanaged := m.putObject(acc, "preprocessrules", map[string]interface{}{"name": "Manual", "action": "drop", "index": float64(0)})
+
+		config := map[string]interface{}{
+			"name":   "MockDedup",
+			"action": "link",
+			"index":  1,
+			"condition": []interface{}{
+				map[string]interface{}{"field": "type", "operator": "isEqualString", "value": "Phishing"},
+			},
+			"existing_condition": []interface{}{
+				map[string]interface{}{"field": "emailfrom", "operator": "isNotEmpty"},
+			},
+		}
+		importId := "MockDedup"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".MockDedup"
+		}
+		r := tf.resource("xsoar_preprocess_rule")
+		r.mustApply(config)
+		id := r.attrString("id")
+		rule := m.object(acc, "preprocessrules", id)
+		if rule == nil {
+			t.Fatalf("rule %s was not created in account %q", id, acc)
+		}
+		if rule["action"] != "link" || rule["index"] != float64(0) || m.object(acc, "preprocessrules", unmanaged)["index"] != float64(1) {
+			t.Fatalf("rule was not moved first, got %v", rule)
+		}
+		filter := mockObject(mockSlice(mockSlice(rule["newEventFilters"])[0])[0])
+		if filter["operator"] != "isEqualString" {
+			t.Fatalf("unexpected filter %v", filter)
+		}
+
+		script := tf.resource("xsoar_preprocess_rule")
+		scriptConfig := map[string]interface{}{
+			"name":        "MockScript",
+			"action":      "run_script",
+			"script_name": "MockPreprocess",
+			"index":       10,
+		}
+		if acc != "" {
+			scriptConfig["account"] = acc
+		}
+		script.mustApply(scriptConfig)
+		if m.object(acc, "preprocessrules", script.attrString("id"))["index"] != float64(2) {
+			t.Fatal("rule was not added last")
+		}
+
+		// moving the rule in the UI is drift
+		m.object(acc, "preprocessrules", id)["index"] = float64(5)
+		if err := r.refresh(); err != nil {
+			t.Fatal(err)
+		}
+		if r.attr("index") != float64(3) {
+			t.Fatalf("drift was not detected, got %v", r.attr("index"))
+		}
+		r.mustApply(config)
+		if m.object(acc, "preprocessrules", id)["index"] != float64(0) {
+			t.Fatal("rule was not moved back first")
+		}
+
+		config["index"] = 2
+		config["enabled"] = false
+		config["action"] = "link_and_update"
+		r.mustApply(config)
+		if r.attrString("id") != id {
+			t.Fatal("rule was replaced when it should have been updated")
+		}
+		rule = m.object(acc, "preprocessrules", id)
+		if rule["index"] != float64(1) || rule["enabled"] != false || rule["action"] != "linkAndUpdate" {
+			t.Fatalf("rule was not updated, got %v", rule)
+		}
+
+		r.mustImport(importId)
+		script.mustDestroy()
+		r.mustDestroy()
+		if m.object(acc, "preprocessrules", id) != nil {
+			t.Fatal("found rule when none was expected")
+		}
+	}
+
+	invalid := []map[string]interface{}{
+		{"name": "Mock", "action": "run_script", "index": 1},
+		{"name": "Mock", "action": "drop", "script_name": "MockPreprocess", "index": 1},
+		{"name": "Mock", "action": "drop", "index": 0},
+		{"name": "Mock", "action": "delete", "index": 1},
+		{"name": "Mock", "action": "drop", "index": 1, "existing_condition": []interface{}{
+			map[string]interface{}{"field": "type", "operator": "isNotEmpty"},
+		}},
+	}
+	for _, config := range invalid {
+		if err := tf.resource("xsoar_preprocess_rule").apply(config); err == nil {
+			t.Fatalf("expected an error for %v", config)
+		}
+	}
+}
+
+func TestPreprocessRule_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{"name": "MockDrop", "action": "drop", "index": 1}
+
+	m.fail("POST", "preprocess/rule", http.StatusInternalServerError)
+	r := tf.resource("xsoar_preprocess_rule")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Could not create pre-process rule") {
+		t.Fatalf("expected the server error to fail the create, got %v", err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("a rule that failed to create was stored in state")
+	}
+	m.fail("POST", "preprocess/rule", 0)
+
+	r.mustApply(config)
+	m.fail("GET", "preprocess/rules", http.StatusInternalServerError)
+	if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get pre-process rule") {
+		t.Fatalf("expected the server error to fail the read, got %v", err)
+	}
+	m.fail("GET", "preprocess/rules", 0)
+
+	// a rule deleted outside of terraform is removed from state and cannot be imported
+	m.removeObject("", "preprocessrules", r.attrString("id"))
+	if err = r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the removed rule to be removed from state")
+	}
+	if err = r.importState("MockDrop"); err == nil || !strings.Contains(err.Error(), "Could not find pre-process rule") {
+		t.Fatalf("expected importing a missing rule to fail, got %v", err)
+	}
+}
@@ -0,0 +1,430 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var permissionLevels = []string{"none", "read", "read_write"}
+
+// rolePermissions converts the permissions map into the permissions XSOAR stores for the tenant
+func rolePermissions(ctx context.Context, permissions types.Map) []interface{} {
+	levels := map[string]string{}
+	if !permissions.Null && !permissions.Unknown {
+		permissions.ElementsAs(ctx, &levels, true)
+	}
+	result := []interface{}{}
+	for id, level := range levels {
+		result = append(result, map[string]interface{}{
+			"id":   id,
+			"read": level == "read" || level == "read_write",
+			"edit": level == "read_write",
+		})
+	}
+	return result
+}
+
+// roleFromAPI maps a role returned by XSOAR onto the resource
+func roleFromAPI(role map[string]interface{}, prior Role) Role {
+	name, _ := role["name"].(string)
+	id, _ := role["id"].(string)
+	permissions := map[string]attr.Value{}
+	var priorPermissions map[string]attr.Value
+	if !prior.Permissions.Null && !prior.Permissions.Unknown {
+		priorPermissions = prior.Permissions.Elems
+	}
+	permissionsByTenant, _ := role["permissions"].(map[string]interface{})
+	for _, item := range interfaceSlice(permissionsByTenant["demisto"]) {
+		permission, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		permissionId, _ := permission["id"].(string)
+		read, _ := permission["read"].(bool)
+		edit, _ := permission["edit"].(bool)
+		level := "none"
+		if edit {
+			level = "read_write"
+		} else if read {
+			level = "read"
+		}
+		// XSOAR lists every permission, so only keep those that grant access or were configured
+		if _, ok := priorPermissions[permissionId]; level == "none" && !ok {
+			continue
+		}
+		permissions[permissionId] = types.String{Value: level}
+	}
+	result := Role{
+		Name:              types.String{Value: name},
+		Id:                types.String{Value: id},
+		Permissions:       types.Map{Elems: permissions, ElemType: types.StringType},
+		PageAccess:        stringSet(interfaceSlice(role["pageAccess"])),
+		PropagationLabels: stringSet(interfaceSlice(role["propagationLabels"])),
+		Account:           prior.Account,
+	}
+	if len(permissions) == 0 && (prior.Permissions.Null || prior.Permissions.Unknown) {
+		result.Permissions = types.Map{Null: true, ElemType: types.StringType}
+	}
+	return result
+}
+
+type resourceRoleType struct{}
+
+// GetSchema Resource schema
+func (r resourceRoleType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:          types.StringType,
+				Required:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+			"id": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"permissions": {
+				Type:       types.MapType{ElemType: types.StringType},
+				Optional:   true,
+				Validators: []tfsdk.AttributeValidator{permissionLevelsValidator{}},
+			},
+			"page_access": {
+				Type:     types.SetType{ElemType: types.StringType},
+				Optional: true,
+				Computed: true,
+			},
+			"propagation_labels": {
+				Type:     types.SetType{ElemType: types.StringType},
+				Optional: true,
+				Computed: true,
+			},
+			"account": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourceRoleType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceRole{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+// permissionLevelsValidator validates that every permission is given one of the permission levels
+type permissionLevelsValidator struct{}
+
+func (v permissionLevelsValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("Permission levels must be one of %s", strings.Join(permissionLevels, ", "))
+}
+
+func (v permissionLevelsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v permissionLevelsValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+	var permissions types.Map
+	diags := tfsdk.ValueAs(ctx, req.AttributeConfig, &permissions)
+	resp.Diagnostics.Append(diags...)
+	if diags.HasError() || permissions.Null || permissions.Unknown {
+		return
+	}
+	for id, value := range permissions.Elems {
+		level, ok := value.(types.String)
+		if !ok || level.Unknown {
+			continue
+		}
+		valid := false
+		for _, l := range permissionLevels {
+			valid = valid || l == level.Value
+		}
+		if !valid {
+			resp.Diagnostics.AddAttributeError(
+				req.AttributePath,
+				"Invalid Value",
+				fmt.Sprintf("Permission %s must be one of %s, got: %s.", id, strings.Join(permissionLevels, ", "), level.Value),
+			)
+		}
+	}
+}
+
+type resourceRole struct {
+	p provider
+}
+
+// getRole returns the role with the given field value, or nil if there is none
+func (r resourceRole) getRole(ctx context.Context, account types.String, field string, value string) (map[string]interface{}, error) {
+	var roles []map[string]interface{}
+	_, err := r.p.doRequest(ctx, "GET", accountPath(account, "/roles"), nil, &roles)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role[field] == value {
+			return role, nil
+		}
+	}
+	return nil, nil
+}
+
+// saveRole creates the role, or updates it when id is set
+func (r resourceRole) saveRole(ctx context.Context, plan Role, id string) (map[string]interface{}, error) {
+	body := map[string]interface{}{
+		"name": plan.Name.Value,
+		"permissions": map[string]interface{}{
+			"demisto": rolePermissions(ctx, plan.Permissions),
+		},
+	}
+	if len(id) > 0 {
+		body["id"] = id
+	}
+	sets := map[string]types.Set{
+		"pageAccess":        plan.PageAccess,
+		"propagationLabels": plan.PropagationLabels,
+	}
+	for key, value := range sets {
+		if !value.Null && !value.Unknown {
+			var elems []string
+			value.ElementsAs(ctx, &elems, true)
+			body[key] = elems
+		}
+	}
+	var role map[string]interface{}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/roles/update"), body, &role)
+	if err != nil {
+		if httpResponse != nil {
+			log.Println(httpResponse.Status)
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+// Create a new resource
+func (r resourceRole) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan Role
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create
+	role, err := r.saveRole(ctx, plan, "")
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error creating role",
+			"Could not create role: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := roleFromAPI(role, plan)
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourceRole) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Role
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	role, err := r.getRole(ctx, state.Account, "id", state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting role",
+			"Could not get role: "+err.Error(),
+		)
+		return
+	}
+	if role == nil {
+		log.Println("Role not found")
+		// Remove resource from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := roleFromAPI(role, state)
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourceRole) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan Role
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Role
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update
+	role, err := r.saveRole(ctx, plan, state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error updating role",
+			"Could not update role: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := roleFromAPI(role, plan)
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourceRole) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state Role
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete
+	_, err := r.p.doRequest(ctx, "DELETE", accountPath(state.Account, "/roles/"+url.PathEscape(state.Id.Value)), nil, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error deleting role",
+			"Could not delete role: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourceRole) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	accname := strings.SplitN(req.ID, ".", 2)
+	var name string
+	account := types.String{Null: true}
+	if len(accname) == 1 {
+		name = req.ID
+	} else {
+		account = types.String{Value: accname[0]}
+		name = accname[1]
+	}
+	role, err := r.getRole(ctx, account, "name", name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing role",
+			"Could not import role: "+err.Error(),
+		)
+		return
+	}
+	if role == nil {
+		resp.Diagnostics.AddError(
+			"Role not found",
+			fmt.Sprintf("Could not find role: %s", name),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := roleFromAPI(role, Role{Permissions: types.Map{Null: true, ElemType: types.StringType}, Account: account})
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
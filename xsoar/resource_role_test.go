@@ -0,0 +1,107 @@
+package xsoar
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRole_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{
+			"name": "MockAnalyst",
+			"permissions": map[string]interface{}{
+				"incidents":  "read_write",
+				"indicators": "read",
+			},
+			"page_access": []interface{}{"incidents", "dashboards"},
+		}
+		importId := "MockAnalyst"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".MockAnalyst"
+		}
+		r := tf.resource("xsoar_role")
+		r.mustApply(config)
+		id := r.attrString("id")
+		role := m.object(acc, "roles", id)
+		if role == nil {
+			t.Fatalf("role %s was not created in account %q", id, acc)
+		}
+		permission := mockObject(mockSlice(mockObject(role["permissions"])["demisto"])[0])
+		if permission["id"] != "incidents" || permission["edit"] != true {
+			t.Fatalf("unexpected permission %v", permission)
+		}
+
+		config["permissions"] = map[string]interface{}{
+			"incidents":  "read",
+			"indicators": "none",
+			"playbooks":  "read_write",
+		}
+		config["propagation_labels"] = []interface{}{"mock"}
+		r.mustApply(config)
+		if r.attrString("id") != id {
+			t.Fatal("role was replaced when it should have been updated")
+		}
+		permission = mockObject(mockSlice(mockObject(m.object(acc, "roles", id)["permissions"])["demisto"])[2])
+		if permission["id"] != "playbooks" || permission["edit"] != true {
+			t.Fatalf("role was not updated, got %v", permission)
+		}
+
+		// import only reads the permissions that grant access
+		delete(mockObject(config["permissions"]), "indicators")
+		r.mustApply(config)
+		r.mustImport(importId)
+		r.mustDestroy()
+		if m.object(acc, "roles", id) != nil {
+			t.Fatal("found role when none was expected")
+		}
+	}
+
+	r := tf.resource("xsoar_role")
+	if err := r.apply(map[string]interface{}{"name": "Mock", "permissions": map[string]interface{}{"incidents": "write"}}); err == nil {
+		t.Fatal("expected an error for an unknown permission level")
+	}
+}
+
+func TestRole_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{"name": "MockAnalyst", "permissions": map[string]interface{}{"incidents": "read"}}
+
+	m.fail("POST", "roles/update", http.StatusInternalServerError)
+	r := tf.resource("xsoar_role")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Could not create role") {
+		t.Fatalf("expected the server error to fail the create, got %v", err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("a role that failed to create was stored in state")
+	}
+	m.fail("POST", "roles/update", 0)
+
+	r.mustApply(config)
+	m.fail("GET", "roles", http.StatusForbidden)
+	if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get role") {
+		t.Fatalf("expected the server error to fail the read, got %v", err)
+	}
+	m.fail("GET", "roles", 0)
+
+	// a role deleted outside of terraform is removed from state and cannot be imported
+	m.removeObject("", "roles", r.attrString("id"))
+	if err = r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the removed role to be removed from state")
+	}
+	if err = r.importState("MockAnalyst"); err == nil || !strings.Contains(err.Error(), "Could not find role") {
+		t.Fatalf("expected importing a missing role to fail, got %v", err)
+	}
+}
@@ -0,0 +1,568 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// normalizeScript ignores line endings and trailing whitespace, which XSOAR does not preserve
+func normalizeScript(script string) string {
+	script = strings.ReplaceAll(script, "\r\n", "\n")
+	lines := strings.Split(script, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// scriptRequest builds the automation object sent to XSOAR from the plan
+func scriptRequest(ctx context.Context, plan Script, id string) map[string]interface{} {
+	script := map[string]interface{}{
+		"name":    plan.Name.Value,
+		"type":    plan.Type.Value,
+		"script":  plan.Script.Value,
+		"enabled": true,
+		// -1 overwrites whatever version is stored on the server
+		"version": -1,
+	}
+	if len(id) > 0 {
+		script["id"] = id
+	}
+	if plan.Type.Value == "python" {
+		script["subtype"] = "python3"
+	}
+	if !plan.DockerImage.Null && !plan.DockerImage.Unknown {
+		script["dockerImage"] = plan.DockerImage.Value
+	}
+	if !plan.RunAs.Null && !plan.RunAs.Unknown {
+		script["runAs"] = plan.RunAs.Value
+	}
+	if !plan.Tags.Null && !plan.Tags.Unknown {
+		var tags []string
+		plan.Tags.ElementsAs(ctx, &tags, true)
+		script["tags"] = tags
+	}
+	if !plan.PropagationLabels.Null && !plan.PropagationLabels.Unknown {
+		var props []string
+		plan.PropagationLabels.ElementsAs(ctx, &props, true)
+		script["propagationLabels"] = props
+	}
+
+	args := []map[string]interface{}{}
+	for _, a := range plan.Args {
+		arg := map[string]interface{}{
+			"name":         a.Name.Value,
+			"description":  a.Description.Value,
+			"required":     a.Required.Value,
+			"default":      a.Default.Value,
+			"isArray":      a.IsArray.Value,
+			"secret":       a.Secret.Value,
+			"defaultValue": a.DefaultValue.Value,
+		}
+		if !a.Predefined.Null && !a.Predefined.Unknown {
+			var predefined []string
+			a.Predefined.ElementsAs(ctx, &predefined, true)
+			arg["auto"] = "PREDEFINED"
+			arg["predefined"] = predefined
+		}
+		args = append(args, arg)
+	}
+	script["arguments"] = args
+
+	outputs := []map[string]interface{}{}
+	for _, o := range plan.Outputs {
+		outputs = append(outputs, map[string]interface{}{
+			"contextPath": o.ContextPath.Value,
+			"description": o.Description.Value,
+			"type":        o.Type.Value,
+		})
+	}
+	script["outputs"] = outputs
+
+	return script
+}
+
+// scriptFromAPI maps an automation returned by XSOAR onto the resource, keeping the prior values
+// of attributes that are only formatted differently by the server
+func scriptFromAPI(script map[string]interface{}, prior Script) Script {
+	result := Script{
+		Name:              types.String{Value: fmt.Sprint(script["name"])},
+		Id:                types.String{Value: fmt.Sprint(script["id"])},
+		Type:              types.String{Value: fmt.Sprint(script["type"])},
+		Script:            types.String{Null: true},
+		DockerImage:       types.String{Null: true},
+		RunAs:             types.String{Null: true},
+		Tags:              stringSet(interfaceSlice(script["tags"])),
+		PropagationLabels: stringSet(interfaceSlice(script["propagationLabels"])),
+		Args:              []ScriptArgument{},
+		Outputs:           []ScriptOutput{},
+		Account:           prior.Account,
+	}
+	if body, ok := script["script"].(string); ok {
+		result.Script = types.String{Value: body}
+		if !prior.Script.Null && !prior.Script.Unknown && normalizeScript(prior.Script.Value) == normalizeScript(body) {
+			result.Script = prior.Script
+		}
+	}
+	if dockerImage, ok := script["dockerImage"].(string); ok && len(dockerImage) > 0 {
+		result.DockerImage = types.String{Value: dockerImage}
+	}
+	if runAs, ok := script["runAs"].(string); ok && len(runAs) > 0 {
+		result.RunAs = types.String{Value: runAs}
+	}
+
+	for i, item := range interfaceSlice(script["arguments"]) {
+		a, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		priorArg := ScriptArgument{
+			Description:  types.String{Null: true},
+			Required:     types.Bool{Null: true},
+			Default:      types.Bool{Null: true},
+			IsArray:      types.Bool{Null: true},
+			Secret:       types.Bool{Null: true},
+			DefaultValue: types.String{Null: true},
+		}
+		if i < len(prior.Args) {
+			priorArg = prior.Args[i]
+		}
+		description, _ := a["description"].(string)
+		defaultValue, _ := a["defaultValue"].(string)
+		required, _ := a["required"].(bool)
+		isDefault, _ := a["default"].(bool)
+		isArray, _ := a["isArray"].(bool)
+		secret, _ := a["secret"].(bool)
+		arg := ScriptArgument{
+			Name:         types.String{Value: fmt.Sprint(a["name"])},
+			Description:  optionalString(description, priorArg.Description),
+			Required:     optionalBool(required, priorArg.Required),
+			Default:      optionalBool(isDefault, priorArg.Default),
+			IsArray:      optionalBool(isArray, priorArg.IsArray),
+			Secret:       optionalBool(secret, priorArg.Secret),
+			DefaultValue: optionalString(defaultValue, priorArg.DefaultValue),
+			Predefined:   types.List{Null: true, ElemType: types.StringType},
+		}
+		if predefined := interfaceSlice(a["predefined"]); len(predefined) > 0 {
+			var elems []attr.Value
+			for _, p := range predefined {
+				elems = append(elems, types.String{Value: fmt.Sprint(p)})
+			}
+			arg.Predefined = types.List{Elems: elems, ElemType: types.StringType}
+		}
+		result.Args = append(result.Args, arg)
+	}
+
+	for i, item := range interfaceSlice(script["outputs"]) {
+		o, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		priorOutput := ScriptOutput{Description: types.String{Null: true}, Type: types.String{Null: true}}
+		if i < len(prior.Outputs) {
+			priorOutput = prior.Outputs[i]
+		}
+		description, _ := o["description"].(string)
+		outputType, _ := o["type"].(string)
+		result.Outputs = append(result.Outputs, ScriptOutput{
+			ContextPath: types.String{Value: fmt.Sprint(o["contextPath"])},
+			Description: optionalString(description, priorOutput.Description),
+			Type:        optionalString(outputType, priorOutput.Type),
+		})
+	}
+
+	return result
+}
+
+type resourceScriptType struct{}
+
+// GetSchema Resource schema
+func (r resourceScriptType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"name": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"id": {
+				Type:     types.StringType,
+				Computed: true,
+				Optional: false,
+			},
+			"type": {
+				Type:       types.StringType,
+				Required:   true,
+				Validators: []tfsdk.AttributeValidator{isOneOf{values: []string{"python", "powershell", "javascript"}}},
+			},
+			"script": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"docker_image": {
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"run_as": {
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"tags": {
+				Type:     types.SetType{ElemType: types.StringType},
+				Optional: true,
+				Computed: true,
+			},
+			"propagation_labels": {
+				Type:     types.SetType{ElemType: types.StringType},
+				Optional: true,
+				Computed: true,
+			},
+			"account": {
+				Type:          types.StringType,
+				Optional:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+			"args": {
+				NestingMode: tfsdk.BlockNestingModeList,
+				Attributes: map[string]tfsdk.Attribute{
+					"name": {
+						Type:     types.StringType,
+						Required: true,
+					},
+					"description": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"required": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"default": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"is_array": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"secret": {
+						Type:     types.BoolType,
+						Optional: true,
+					},
+					"default_value": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"predefined": {
+						Type:     types.ListType{ElemType: types.StringType},
+						Optional: true,
+					},
+				},
+			},
+			"outputs": {
+				NestingMode: tfsdk.BlockNestingModeList,
+				Attributes: map[string]tfsdk.Attribute{
+					"context_path": {
+						Type:     types.StringType,
+						Required: true,
+					},
+					"description": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"type": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourceScriptType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceScript{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type resourceScript struct {
+	p provider
+}
+
+// searchScript returns the automation matching a search query, or nil if there is none
+func (r resourceScript) searchScript(ctx context.Context, account types.String, field string, value string) (map[string]interface{}, *http.Response, error) {
+	var search struct {
+		Scripts []map[string]interface{} `json:"scripts"`
+	}
+	query := map[string]interface{}{"query": fmt.Sprintf("%s:%q", field, value)}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(account, "/automation/search"), query, &search)
+	if err != nil {
+		return nil, httpResponse, err
+	}
+	for _, script := range search.Scripts {
+		if script[field] == value {
+			return script, httpResponse, nil
+		}
+	}
+	return nil, httpResponse, nil
+}
+
+// Create a new resource
+func (r resourceScript) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan Script
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create
+	var script map[string]interface{}
+	body := map[string]interface{}{"script": scriptRequest(ctx, plan, "")}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/automation"), body, &script)
+	if err != nil {
+		log.Println(err.Error())
+		if httpResponse != nil {
+			b, _ := io.ReadAll(httpResponse.Body)
+			log.Println(string(b))
+		}
+		resp.Diagnostics.AddError(
+			"Error creating script",
+			"Could not create script: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := scriptFromAPI(script, plan)
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourceScript) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Script
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An account that no longer exists takes its scripts with it
+	if !state.Account.Null && len(state.Account.Value) > 0 {
+		account, _, err := r.p.client.DefaultApi.GetAccount(ctx, "acc_"+state.Account.Value).Execute()
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error getting script",
+				"Could not verify account existence: "+err.Error(),
+			)
+			return
+		}
+		if account == nil {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+	}
+
+	// Get resource from API
+	script, _, err := r.searchScript(ctx, state.Account, "id", state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting script",
+			"Could not get script: "+err.Error(),
+		)
+		return
+	}
+	if script == nil {
+		log.Println("Script not found")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := scriptFromAPI(script, state)
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourceScript) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan Script
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state Script
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update
+	var script map[string]interface{}
+	body := map[string]interface{}{"script": scriptRequest(ctx, plan, state.Id.Value)}
+	httpResponse, err := r.p.doRequest(ctx, "POST", accountPath(plan.Account, "/automation"), body, &script)
+	if err != nil {
+		log.Println(err.Error())
+		if httpResponse != nil {
+			b, _ := io.ReadAll(httpResponse.Body)
+			log.Println(string(b))
+		}
+		resp.Diagnostics.AddError(
+			"Error updating script",
+			"Could not update script: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := scriptFromAPI(script, plan)
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourceScript) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state Script
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete
+	body := map[string]interface{}{"script": map[string]interface{}{"id": state.Id.Value}}
+	_, err := r.p.doRequest(ctx, "POST", accountPath(state.Account, "/automation/delete"), body, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error deleting script",
+			"Could not delete script: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourceScript) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	accname := strings.SplitN(req.ID, ".", 2)
+	var name string
+	account := types.String{Null: true}
+	if len(accname) == 1 {
+		name = req.ID
+	} else {
+		account = types.String{Value: accname[0]}
+		name = accname[1]
+	}
+	script, _, err := r.searchScript(ctx, account, "name", name)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing script",
+			"Could not import script: "+err.Error(),
+		)
+		return
+	}
+	if script == nil {
+		resp.Diagnostics.AddError(
+			"Script not found",
+			"Could not find script: "+name,
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := scriptFromAPI(script, Script{Account: account})
+	if !result.Script.Null {
+		result.Script = types.String{Value: normalizeScript(result.Script.Value)}
+	}
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
@@ -0,0 +1,130 @@
+package xsoar
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestScript_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	for _, acc := range []string{"", "mockacc"} {
+		config := map[string]interface{}{
+			"name":   "MockScript",
+			"type":   "python",
+			"script": "def main():\n    return_results('hello')\n\n\nmain()",
+			"tags":   []interface{}{"mock"},
+			"args": []interface{}{
+				map[string]interface{}{"name": "value", "description": "The value to print", "required": true, "default": true},
+				map[string]interface{}{"name": "mode", "predefined": []interface{}{"plain", "markdown"}},
+			},
+			"outputs": []interface{}{
+				map[string]interface{}{"context_path": "Mock.Value", "type": "String"},
+			},
+		}
+		importId := "MockScript"
+		if acc != "" {
+			config["account"] = acc
+			importId = acc + ".MockScript"
+		}
+		r := tf.resource("xsoar_script")
+		r.mustApply(config)
+		id := r.attrString("id")
+		script := m.object(acc, "scripts", id)
+		if script == nil {
+			t.Fatalf("script %s was not created in account %q", id, acc)
+		}
+		if script["subtype"] != "python3" {
+			t.Fatalf("expected python3 subtype, got %v", script["subtype"])
+		}
+		if r.attrString("docker_image") != "demisto/python3:3.10.4.29342" || r.attrString("run_as") != "DBotWeakRole" {
+			t.Fatalf("server defaults were not read back: %v %v", r.attr("docker_image"), r.attr("run_as"))
+		}
+		arg := mockObject(mockSlice(script["arguments"])[1])
+		if arg["auto"] != "PREDEFINED" {
+			t.Fatalf("predefined argument was not sent, got %v", arg)
+		}
+
+		config["script"] = "def main():\n    return_results('goodbye')\n\n\nmain()"
+		config["docker_image"] = "demisto/python3:3.10.5.31928"
+		config["run_as"] = "DBotRole"
+		config["args"] = []interface{}{
+			map[string]interface{}{"name": "value", "description": "The value to print", "required": false},
+		}
+		r.mustApply(config)
+		if r.attrString("id") != id {
+			t.Fatal("script was replaced when it should have been updated")
+		}
+		script = m.object(acc, "scripts", id)
+		if script["script"] != "def main():\n    return_results('goodbye')\n\n\nmain()\n" || script["dockerImage"] != "demisto/python3:3.10.5.31928" {
+			t.Fatalf("script was not updated, got %v", script)
+		}
+		if len(mockSlice(script["arguments"])) != 1 {
+			t.Fatalf("expected 1 argument, got %v", script["arguments"])
+		}
+
+		if required := mockObject(mockSlice(r.attr("args"))[0])["required"]; required != false {
+			t.Fatalf("explicit false argument was not kept, got %v", required)
+		}
+
+		// import leaves false and empty argument settings unset
+		config["args"] = []interface{}{
+			map[string]interface{}{"name": "value", "description": "The value to print"},
+		}
+		r.mustApply(config)
+		r.mustImport(importId)
+		r.mustDestroy()
+		if m.object(acc, "scripts", id) != nil {
+			t.Fatal("found script when none was expected")
+		}
+	}
+
+	r := tf.resource("xsoar_script")
+	if err := r.apply(map[string]interface{}{"name": "MockScript", "type": "bash", "script": "echo"}); err == nil {
+		t.Fatal("expected an error for an unsupported script type")
+	}
+	if err := r.apply(map[string]interface{}{"name": "MockScript", "type": "python"}); err == nil {
+		t.Fatal("expected an error for a missing script body")
+	}
+}
+
+func TestScript_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{"name": "MockScript", "type": "python", "script": "main()"}
+
+	m.fail("POST", "automation", http.StatusInternalServerError)
+	r := tf.resource("xsoar_script")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Could not create script") {
+		t.Fatalf("expected the server error to fail the create, got %v", err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("a script that failed to create was stored in state")
+	}
+	m.fail("POST", "automation", 0)
+
+	r.mustApply(config)
+	m.fail("POST", "automation/search", http.StatusBadRequest)
+	if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get script") {
+		t.Fatalf("expected the server error to fail the read, got %v", err)
+	}
+	m.fail("POST", "automation/search", 0)
+
+	// a script deleted outside of terraform is removed from state and cannot be imported
+	m.removeObject("", "scripts", r.attrString("id"))
+	if err = r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the removed script to be removed from state")
+	}
+	if err = r.importState("MockScript"); err == nil || !strings.Contains(err.Error(), "Could not find script") {
+		t.Fatalf("expected importing a missing script to fail, got %v", err)
+	}
+}
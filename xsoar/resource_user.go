@@ -0,0 +1,431 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// mainTenantRolesKey holds the roles of a user in the main tenant, the roles in accounts are held
+// under the acc_ prefixed account name
+const mainTenantRolesKey = "demisto"
+
+// userRolesKey returns the key of the roles of an account, the main tenant if the account is null
+func userRolesKey(account types.String) string {
+	if account.Null || len(account.Value) == 0 {
+		return mainTenantRolesKey
+	}
+	return "acc_" + account.Value
+}
+
+// userRequest builds the user sent to XSOAR from the plan
+func userRequest(ctx context.Context, plan User, id string, password bool) map[string]interface{} {
+	roles := map[string]interface{}{}
+	for _, r := range plan.Roles {
+		var names []string
+		r.Names.ElementsAs(ctx, &names, true)
+		roles[userRolesKey(r.Account)] = names
+	}
+	user := map[string]interface{}{
+		"username": plan.Username.Value,
+		"email":    plan.Email.Value,
+		"roles":    map[string]interface{}{"roles": roles},
+	}
+	if len(id) > 0 {
+		user["id"] = id
+	}
+	if !plan.Name.Null && !plan.Name.Unknown {
+		user["name"] = plan.Name.Value
+	}
+	if !plan.Phone.Null && !plan.Phone.Unknown {
+		user["phone"] = plan.Phone.Value
+	}
+	if !plan.DefaultAdmin.Null && !plan.DefaultAdmin.Unknown {
+		user["defaultAdmin"] = plan.DefaultAdmin.Value
+	}
+	if password && !plan.Password.Null && !plan.Password.Unknown {
+		user["password"] = plan.Password.Value
+	}
+	return user
+}
+
+// userFromAPI maps a user returned by XSOAR onto the resource, the password is never returned
+func userFromAPI(user map[string]interface{}, prior User) User {
+	str := func(key string) string {
+		v, _ := user[key].(string)
+		return v
+	}
+	defaultAdmin, _ := user["defaultAdmin"].(bool)
+	result := User{
+		Username:     types.String{Value: str("username")},
+		Id:           types.String{Value: str("id")},
+		Name:         optionalString(str("name"), prior.Name),
+		Email:        types.String{Value: str("email")},
+		Phone:        optionalString(str("phone"), prior.Phone),
+		Password:     prior.Password,
+		DefaultAdmin: types.Bool{Value: defaultAdmin},
+		Roles:        []UserRoles{},
+	}
+	roles, _ := user["roles"].(map[string]interface{})
+	byTenant, _ := roles["roles"].(map[string]interface{})
+	// keep the order of the configured blocks, and add the others in a stable order
+	var keys []string
+	seen := map[string]bool{}
+	for _, r := range prior.Roles {
+		key := userRolesKey(r.Account)
+		if _, ok := byTenant[key]; ok && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+	var others []string
+	for key := range byTenant {
+		if !seen[key] {
+			others = append(others, key)
+		}
+	}
+	sort.Strings(others)
+	for _, key := range append(keys, others...) {
+		names := interfaceSlice(byTenant[key])
+		if len(names) == 0 {
+			continue
+		}
+		account := types.String{Null: true}
+		if key != mainTenantRolesKey {
+			account = types.String{Value: strings.TrimPrefix(key, "acc_")}
+		}
+		result.Roles = append(result.Roles, UserRoles{
+			Account: account,
+			Names:   stringSet(names),
+		})
+	}
+	return result
+}
+
+type resourceUserType struct{}
+
+// GetSchema Resource schema
+func (r resourceUserType) GetSchema(_ context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	var planModifiers []tfsdk.AttributePlanModifier
+	return tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"username": {
+				Type:          types.StringType,
+				Required:      true,
+				PlanModifiers: append(planModifiers, tfsdk.RequiresReplace()),
+			},
+			"id": {
+				Type:          types.StringType,
+				Computed:      true,
+				Optional:      false,
+				PlanModifiers: append(planModifiers, tfsdk.UseStateForUnknown()),
+			},
+			"name": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"email": {
+				Type:     types.StringType,
+				Required: true,
+			},
+			"phone": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"password": {
+				Type:      types.StringType,
+				Optional:  true,
+				Sensitive: true,
+			},
+			"default_admin": {
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+			},
+		},
+		Blocks: map[string]tfsdk.Block{
+			"timeouts": timeoutsBlock(),
+			"roles": {
+				NestingMode: tfsdk.BlockNestingModeList,
+				Attributes: map[string]tfsdk.Attribute{
+					"account": {
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"names": {
+						Type:     types.SetType{ElemType: types.StringType},
+						Required: true,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+// NewResource instance
+func (r resourceUserType) NewResource(_ context.Context, p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	return resourceUser{
+		p: *(p.(*provider)),
+	}, nil
+}
+
+type resourceUser struct {
+	p provider
+}
+
+func (r resourceUser) ValidateConfig(ctx context.Context, req tfsdk.ValidateResourceConfigRequest, resp *tfsdk.ValidateResourceConfigResponse) {
+	var config User
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	seen := map[string]bool{}
+	for _, r := range config.Roles {
+		if r.Account.Unknown {
+			continue
+		}
+		key := userRolesKey(r.Account)
+		if seen[key] {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("roles"),
+				"Duplicate roles",
+				fmt.Sprintf("The roles of %s are set more than once.", key),
+			)
+		}
+		seen[key] = true
+	}
+}
+
+// getUser returns the user with the given field value, or nil if there is none
+func (r resourceUser) getUser(ctx context.Context, field string, value string) (map[string]interface{}, error) {
+	var users []map[string]interface{}
+	_, err := r.p.doRequest(ctx, "GET", "/users", nil, &users)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if user[field] == value {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+// Create a new resource
+func (r resourceUser) Create(ctx context.Context, req tfsdk.CreateResourceRequest, resp *tfsdk.CreateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "create", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.p.configured {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider hasn't been configured before apply, likely because it depends on an unknown value from another resource. This leads to weird stuff happening, so we'd prefer if you didn't do that. Thanks!",
+		)
+		return
+	}
+
+	// Retrieve values from plan
+	var plan User
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Create
+	var user map[string]interface{}
+	_, err := r.p.doRequest(ctx, "POST", "/users/update", userRequest(ctx, plan, "", true), &user)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error creating user",
+			"Could not create user: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := userFromAPI(user, plan)
+
+	// Generate resource state struct
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Read resource information
+func (r resourceUser) Read(ctx context.Context, req tfsdk.ReadResourceRequest, resp *tfsdk.ReadResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "read", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state User
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get resource from API
+	user, err := r.getUser(ctx, "id", state.Id.Value)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error getting user",
+			"Could not get user: "+err.Error(),
+		)
+		return
+	}
+	if user == nil {
+		log.Println("User not found")
+		// Remove resource from state
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := userFromAPI(user, state)
+
+	// Generate resource state struct
+	result.Timeouts = state.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Update resource
+func (r resourceUser) Update(ctx context.Context, req tfsdk.UpdateResourceRequest, resp *tfsdk.UpdateResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.Plan, "update", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get plan values
+	var plan User
+	diags = req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get current state
+	var state User
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Update, only sending the password when it changed
+	var user map[string]interface{}
+	_, err := r.p.doRequest(ctx, "POST", "/users/update", userRequest(ctx, plan, state.Id.Value, !plan.Password.Equal(state.Password)), &user)
+	if err != nil {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error updating user",
+			"Could not update user: "+err.Error(),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := userFromAPI(user, plan)
+
+	// Set state
+	result.Timeouts = plan.Timeouts
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// Delete resource
+func (r resourceUser) Delete(ctx context.Context, req tfsdk.DeleteResourceRequest, resp *tfsdk.DeleteResourceResponse) {
+	ctx, cancel, diags := withTimeout(ctx, req.State, "delete", defaultTimeout)
+	defer cancel()
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get state
+	var state User
+	diags = req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete
+	_, err := r.p.doRequest(ctx, "POST", "/users/delete", map[string]interface{}{"ids": []string{state.Id.Value}}, nil)
+	if err != nil && !isNotFound(err) {
+		log.Println(err.Error())
+		resp.Diagnostics.AddError(
+			"Error deleting user",
+			"Could not delete user: "+err.Error(),
+		)
+		return
+	}
+
+	// Remove resource from state
+	resp.State.RemoveResource(ctx)
+}
+
+func (r resourceUser) ImportState(ctx context.Context, req tfsdk.ImportResourceStateRequest, resp *tfsdk.ImportResourceStateResponse) {
+	var diags diag.Diagnostics
+	user, err := r.getUser(ctx, "username", req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error importing user",
+			"Could not import user: "+err.Error(),
+		)
+		return
+	}
+	if user == nil {
+		resp.Diagnostics.AddError(
+			"User not found",
+			fmt.Sprintf("Could not find user: %s", req.ID),
+		)
+		return
+	}
+
+	// Map response body to resource schema attribute
+	result := userFromAPI(user, User{
+		Name:     types.String{Null: true},
+		Phone:    types.String{Null: true},
+		Password: types.String{Null: true},
+	})
+
+	// Generate resource state struct
+	result.Timeouts = []Timeouts{}
+	diags = resp.State.Set(ctx, result)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
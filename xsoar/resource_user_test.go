@@ -0,0 +1,128 @@
+package xsoar
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUser_mock(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	m.addAccount("mockacc", "")
+	tf := newMockTerraform(t, m)
+
+	config := map[string]interface{}{
+		"username": "mockuser",
+		"email":    "mockuser@example.com",
+		"password": "hunter2",
+		"roles": []interface{}{
+			map[string]interface{}{"names": []interface{}{"Administrator"}},
+			map[string]interface{}{"account": "mockacc", "names": []interface{}{"Analyst", "Read-Only"}},
+		},
+	}
+	r := tf.resource("xsoar_user")
+	r.mustApply(config)
+	id := r.attrString("id")
+	user := m.object("", "users", id)
+	if user == nil {
+		t.Fatalf("user %s was not created", id)
+	}
+	roles := mockObject(mockObject(user["roles"])["roles"])
+	if len(mockSlice(roles["demisto"])) != 1 || len(mockSlice(roles["acc_mockacc"])) != 2 {
+		t.Fatalf("unexpected roles %v", roles)
+	}
+	if user["password"] != "hunter2" || r.attr("default_admin") != false {
+		t.Fatalf("unexpected user %v", user)
+	}
+
+	// the password is only sent when it changes
+	user["password"] = "changed-in-ui"
+	config["phone"] = "+1 555 0100"
+	config["name"] = "Mock User"
+	config["default_admin"] = true
+	config["roles"] = []interface{}{
+		map[string]interface{}{"account": "mockacc", "names": []interface{}{"Analyst"}},
+	}
+	r.mustApply(config)
+	if r.attrString("id") != id {
+		t.Fatal("user was replaced when it should have been updated")
+	}
+	user = m.object("", "users", id)
+	if user["phone"] != "+1 555 0100" || user["defaultAdmin"] != true || user["password"] != "changed-in-ui" {
+		t.Fatalf("user was not updated, got %v", user)
+	}
+	if roles := mockObject(mockObject(user["roles"])["roles"]); roles["demisto"] != nil {
+		t.Fatalf("main tenant roles were not removed, got %v", roles)
+	}
+
+	config["password"] = "hunter3"
+	r.mustApply(config)
+	if m.object("", "users", id)["password"] != "hunter3" {
+		t.Fatal("password was not updated")
+	}
+
+	r.mustImport("mockuser", "password")
+	r.mustDestroy()
+	if m.object("", "users", id) != nil {
+		t.Fatal("found user when none was expected")
+	}
+
+	if err := tf.resource("xsoar_user").apply(map[string]interface{}{
+		"username": "mockuser",
+		"email":    "mockuser@example.com",
+		"roles": []interface{}{
+			map[string]interface{}{"names": []interface{}{"Administrator"}},
+			map[string]interface{}{"names": []interface{}{"Analyst"}},
+		},
+	}); err == nil {
+		t.Fatal("expected an error for duplicate roles")
+	}
+}
+
+func TestUser_mockErrors(t *testing.T) {
+	t.Parallel()
+	m := newMockXSOAR(t)
+	tf := newMockTerraform(t, m)
+	config := map[string]interface{}{
+		"username": "mockuser",
+		"email":    "mockuser@example.com",
+		"roles": []interface{}{
+			map[string]interface{}{"names": []interface{}{"Analyst"}},
+		},
+	}
+
+	m.fail("POST", "users/update", http.StatusInternalServerError)
+	r := tf.resource("xsoar_user")
+	err := r.apply(config)
+	if err == nil || !strings.Contains(err.Error(), "Could not create user") {
+		t.Fatalf("expected the server error to fail the create, got %v", err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("a user that failed to create was stored in state")
+	}
+	m.fail("POST", "users/update", 0)
+
+	r.mustApply(config)
+	m.fail("GET", "users", http.StatusInternalServerError)
+	if err = r.refresh(); err == nil || !strings.Contains(err.Error(), "Could not get user") {
+		t.Fatalf("expected the server error to fail the read, got %v", err)
+	}
+	m.fail("GET", "users", 0)
+
+	// a user deleted outside of terraform is removed from state and cannot be imported
+	m.removeObject("", "users", r.attrString("id"))
+	if err = r.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.state.IsNull() {
+		t.Fatal("expected the removed user to be removed from state")
+	}
+	if err = r.importState("mockuser"); err == nil || !strings.Contains(err.Error(), "Could not find user") {
+		t.Fatalf("expected importing a missing user to fail, got %v", err)
+	}
+
+	if err = r.apply(map[string]interface{}{"username": "mockuser"}); err == nil {
+		t.Fatal("expected an error for a missing email")
+	}
+}
@@ -0,0 +1,557 @@
+package xsoar
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// hostKeyCheckingModes are how the key a machine offers over ssh is verified. strict only accepts a key that
+// host_key, host_key_fingerprint or known_hosts_file verifies, tofu trusts the key offered on first use and
+// only accepts that key afterwards.
+var hostKeyCheckingModes = []string{"strict", "tofu"}
+
+// hostKeyVerifier verifies the key a machine offers when connecting to it over ssh, and records the key offered
+type hostKeyVerifier struct {
+	// hostKey is a public key in authorized_keys format the machine must offer
+	hostKey string
+	// fingerprint is the SHA256 fingerprint of the key the machine must offer, as printed by ssh-keygen -l
+	fingerprint string
+	// knownHostsFile is a known_hosts file listing the machine
+	knownHostsFile string
+	// tofu trusts the key offered when no key was recorded yet
+	tofu bool
+	// recorded is the key offered on a previous connection, in authorized_keys format
+	recorded string
+
+	// observed is the key offered on the last connection, in authorized_keys format
+	observed string
+	// err is why the key offered on the last connection was rejected
+	err error
+}
+
+// newHostKeyVerifier returns the verifier of the host key settings of a resource
+func newHostKeyVerifier(hostKey types.String, fingerprint types.String, knownHostsFile types.String, mode types.String, recorded types.String) *hostKeyVerifier {
+	return &hostKeyVerifier{
+		hostKey:        hostKey.Value,
+		fingerprint:    fingerprint.Value,
+		knownHostsFile: knownHostsFile.Value,
+		tofu:           mode.Value == "tofu",
+		recorded:       recorded.Value,
+	}
+}
+
+// defaultKnownHostsFile returns the known_hosts file of the user running Terraform, if there is one
+func defaultKnownHostsFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	file := filepath.Join(home, ".ssh", "known_hosts")
+	if _, err = os.Stat(file); err != nil {
+		return ""
+	}
+	return file
+}
+
+// expandHome expands a leading ~ of a path to the home directory of the user running Terraform
+func expandHome(file string) string {
+	if file != "~" && !strings.HasPrefix(file, "~/") {
+		return file
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return file
+	}
+	return filepath.Join(home, file[1:])
+}
+
+// callback returns the host key callback of the verifier
+func (v *hostKeyVerifier) callback() (ssh.HostKeyCallback, error) {
+	var pinned ssh.PublicKey
+	if len(v.hostKey) > 0 {
+		var err error
+		pinned, _, _, _, err = ssh.ParseAuthorizedKey([]byte(v.hostKey))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse host_key: %w", err)
+		}
+	}
+	knownHostsFile := expandHome(v.knownHostsFile)
+	if len(knownHostsFile) == 0 && len(v.hostKey) == 0 && len(v.fingerprint) == 0 && !v.tofu {
+		knownHostsFile = defaultKnownHostsFile()
+	}
+	var knownHosts ssh.HostKeyCallback
+	if len(knownHostsFile) > 0 {
+		var err error
+		knownHosts, err = knownhosts.New(knownHostsFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not read known_hosts_file: %w", err)
+		}
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		v.observed = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key)))
+		v.err = v.verify(hostname, remote, key, pinned, knownHosts)
+		return v.err
+	}, nil
+}
+
+func (v *hostKeyVerifier) verify(hostname string, remote net.Addr, key ssh.PublicKey, pinned ssh.PublicKey, knownHosts ssh.HostKeyCallback) error {
+	fingerprint := ssh.FingerprintSHA256(key)
+	verified := false
+	if pinned != nil {
+		if !bytes.Equal(pinned.Marshal(), key.Marshal()) {
+			return fmt.Errorf("%s offered the host key %s, which is not host_key", hostname, fingerprint)
+		}
+		verified = true
+	}
+	if len(v.fingerprint) > 0 {
+		if fingerprint != v.fingerprint {
+			return fmt.Errorf("%s offered the host key %s, expected %s", hostname, fingerprint, v.fingerprint)
+		}
+		verified = true
+	}
+	if knownHosts != nil {
+		if err := knownHosts(hostname, remote, key); err != nil {
+			return fmt.Errorf("%s offered the host key %s, which known_hosts does not accept: %w", hostname, fingerprint, err)
+		}
+		verified = true
+	}
+	if v.tofu && len(v.recorded) > 0 {
+		if v.observed != v.recorded {
+			recorded, _, _, _, err := ssh.ParseAuthorizedKey([]byte(v.recorded))
+			if err == nil {
+				return fmt.Errorf("%s offered the host key %s, but %s was recorded on first use", hostname, fingerprint, ssh.FingerprintSHA256(recorded))
+			}
+			return fmt.Errorf("%s offered the host key %s, which is not the key recorded on first use", hostname, fingerprint)
+		}
+		verified = true
+	}
+	if verified || v.tofu {
+		return nil
+	}
+	return fmt.Errorf("%s offered the host key %s, which nothing verifies: set host_key, host_key_fingerprint or known_hosts_file, or trust it on first use with host_key_checking = \"tofu\"", hostname, fingerprint)
+}
+
+// sshTarget is a machine to connect to over ssh and how to authenticate to it
+type sshTarget struct {
+	address string
+	user    string
+	// key is a PEM encoded private key, encrypted with passphrase if it is set
+	key        string
+	passphrase string
+	// certificate is a certificate signing key, in authorized_keys format as ssh-keygen -s writes it
+	certificate string
+	// agent authenticates with the keys of the ssh agent listening on SSH_AUTH_SOCK
+	agent bool
+	// forwardAgent forwards the ssh agent to the commands run on the machine
+	forwardAgent bool
+	// hostKeys verifies the host key of the machine, which is not verified when it is nil
+	hostKeys *hostKeyVerifier
+}
+
+// parseCertificate parses an ssh certificate in authorized_keys format
+func parseCertificate(value string) (*ssh.Certificate, error) {
+	key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(value))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse ssh certificate: %w", err)
+	}
+	certificate, ok := key.(*ssh.Certificate)
+	if !ok {
+		return nil, fmt.Errorf("could not parse ssh certificate: %s is a public key, not a certificate", key.Type())
+	}
+	return certificate, nil
+}
+
+// signer returns the signer of the private key of the target, presenting its certificate if it has one
+func (t sshTarget) signer() (ssh.Signer, error) {
+	var signer ssh.Signer
+	var err error
+	if len(t.passphrase) > 0 {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase([]byte(t.key), []byte(t.passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey([]byte(t.key))
+	}
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		return nil, fmt.Errorf("could not parse ssh key: the key is encrypted and no passphrase is set")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse ssh key: %w", err)
+	}
+	if len(t.certificate) == 0 {
+		return signer, nil
+	}
+	certificate, err := parseCertificate(t.certificate)
+	if err != nil {
+		return nil, err
+	}
+	signer, err = ssh.NewCertSigner(certificate, signer)
+	if err != nil {
+		return nil, fmt.Errorf("could not use ssh certificate: %w", err)
+	}
+	return signer, nil
+}
+
+// clientConfig returns the config authenticating to the target with its key and the keys of the agent
+func (t sshTarget) clientConfig(agentClient agent.Agent) (*ssh.ClientConfig, error) {
+	var auth []ssh.AuthMethod
+	if len(t.key) > 0 {
+		signer, err := t.signer()
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if t.agent && agentClient != nil {
+		auth = append(auth, ssh.PublicKeysCallback(agentClient.Signers))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("no ssh key or agent to authenticate to %s with", t.address)
+	}
+	hostKeyCallback := ssh.InsecureIgnoreHostKey()
+	if t.hostKeys != nil {
+		var err error
+		hostKeyCallback, err = t.hostKeys.callback()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &ssh.ClientConfig{
+		User:            t.user,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+	}, nil
+}
+
+// rejected returns why the host key of the target was rejected on the last connection, if it was
+func (t *sshTarget) rejected() error {
+	if t == nil || t.hostKeys == nil {
+		return nil
+	}
+	return t.hostKeys.err
+}
+
+// forwardedAgents holds the connections the ssh agent is forwarded to, whose sessions request agent forwarding
+var forwardedAgents sync.Map
+
+// dialAgent connects to the ssh agent listening on SSH_AUTH_SOCK
+func dialAgent() (net.Conn, error) {
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if len(socket) == 0 {
+		return nil, fmt.Errorf("could not connect to the ssh agent: SSH_AUTH_SOCK is not set")
+	}
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to the ssh agent: %w", err)
+	}
+	return conn, nil
+}
+
+// dialThroughBastion connects to a machine over ssh through a tunnel the bastion opens to it
+func dialThroughBastion(bastionAddress string, bastionConfig *ssh.ClientConfig, address string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	bastionConn, err := ssh.Dial("tcp", bastionAddress, bastionConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to bastion %s: %w", bastionAddress, err)
+	}
+	tunnel, err := bastionConn.Dial("tcp", address)
+	if err != nil {
+		_ = bastionConn.Close()
+		return nil, fmt.Errorf("could not open a tunnel through bastion %s: %w", bastionAddress, err)
+	}
+	c, channels, requests, err := ssh.NewClientConn(tunnel, address, config)
+	if err != nil {
+		_ = tunnel.Close()
+		_ = bastionConn.Close()
+		return nil, err
+	}
+	conn := ssh.NewClient(c, channels, requests)
+	// the bastion connection is closed with the connection it tunnels
+	go func() {
+		_ = conn.Wait()
+		_ = bastionConn.Close()
+	}()
+	return conn, nil
+}
+
+// dialSSH connects to a machine over ssh, through the bastion if one is given, retrying until it accepts
+// connections
+func dialSSH(ctx context.Context, target sshTarget, bastion *sshTarget) (*ssh.Client, error) {
+	var agentClient agent.ExtendedAgent
+	var agentConn net.Conn
+	if target.agent || target.forwardAgent || (bastion != nil && bastion.agent) {
+		var err error
+		agentConn, err = dialAgent()
+		if err != nil {
+			return nil, err
+		}
+		agentClient = agent.NewClient(agentConn)
+	}
+	// the agent is kept open while it is forwarded to the connection
+	forwarding := false
+	defer func() {
+		if agentConn != nil && !forwarding {
+			_ = agentConn.Close()
+		}
+	}()
+	config, err := target.clientConfig(agentClient)
+	if err != nil {
+		return nil, err
+	}
+	var bastionConfig *ssh.ClientConfig
+	if bastion != nil {
+		bastionConfig, err = bastion.clientConfig(agentClient)
+		if err != nil {
+			return nil, fmt.Errorf("bastion: %w", err)
+		}
+	}
+	var conn *ssh.Client
+	err = resource.RetryContext(ctx, untilDeadline(ctx), func() *resource.RetryError {
+		var conErr error
+		if bastion == nil {
+			conn, conErr = ssh.Dial("tcp", target.address, config)
+		} else {
+			conn, conErr = dialThroughBastion(bastion.address, bastionConfig, target.address, config)
+		}
+		// a rejected host key does not change by retrying
+		if rejected := bastion.rejected(); rejected != nil {
+			return resource.NonRetryableError(fmt.Errorf("error verifying host key of bastion: %w", rejected))
+		}
+		if rejected := target.rejected(); rejected != nil {
+			return resource.NonRetryableError(fmt.Errorf("error verifying host key: %w", rejected))
+		}
+		if conErr != nil {
+			return resource.RetryableError(fmt.Errorf("error connecting to host over ssh: " + conErr.Error()))
+		}
+		return nil
+	})
+	if err != nil || !target.forwardAgent {
+		return conn, err
+	}
+	if err = agent.ForwardToAgent(conn, agentClient); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("could not forward the ssh agent: %w", err)
+	}
+	forwarding = true
+	forwardedAgents.Store(conn, true)
+	go func() {
+		_ = conn.Wait()
+		forwardedAgents.Delete(conn)
+		_ = agentConn.Close()
+	}()
+	return conn, nil
+}
+
+// runSSHCommand runs a command in a new session on the connection, interrupting it when ctx is done
+func runSSHCommand(ctx context.Context, conn *ssh.Client, cmd string) error {
+	return runSSHSession(ctx, conn, func(session *ssh.Session) error {
+		return session.Run(cmd)
+	})
+}
+
+// outputSSHCommand runs a command like runSSHCommand, returning what it writes to stdout
+func outputSSHCommand(ctx context.Context, conn *ssh.Client, cmd string) (string, error) {
+	var stdout bytes.Buffer
+	err := runSSHSession(ctx, conn, func(session *ssh.Session) error {
+		session.Stdout = &stdout
+		return session.Run(cmd)
+	})
+	return stdout.String(), err
+}
+
+// inputSSHCommand runs a command like runSSHCommand, giving it stdin. Secrets the command needs are passed this way
+// rather than as arguments, which every user of the machine can list.
+func inputSSHCommand(ctx context.Context, conn *ssh.Client, cmd string, stdin []byte) error {
+	return runSSHSession(ctx, conn, func(session *ssh.Session) error {
+		session.Stdin = bytes.NewReader(stdin)
+		return session.Run(cmd)
+	})
+}
+
+// uploadSSHFile writes content to a file on the machine, speaking the protocol of scp to the scp of the machine
+func uploadSSHFile(ctx context.Context, conn *ssh.Client, file string, mode os.FileMode, content []byte) error {
+	return runSSHSession(ctx, conn, func(session *ssh.Session) error {
+		stdin, err := session.StdinPipe()
+		if err != nil {
+			return err
+		}
+		stdout, err := session.StdoutPipe()
+		if err != nil {
+			return err
+		}
+		if err = session.Start("scp -qt " + shellQuote(file)); err != nil {
+			return err
+		}
+		replies := bufio.NewReader(stdout)
+		if err = scpReply(replies); err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintf(stdin, "C%04o %d %s\n", mode.Perm(), len(content), path.Base(file)); err != nil {
+			return err
+		}
+		if err = scpReply(replies); err != nil {
+			return err
+		}
+		if _, err = stdin.Write(content); err != nil {
+			return err
+		}
+		if _, err = stdin.Write([]byte{0}); err != nil {
+			return err
+		}
+		if err = scpReply(replies); err != nil {
+			return err
+		}
+		_ = stdin.Close()
+		return session.Wait()
+	})
+}
+
+// remoteInstaller is an installer put on a machine in a directory of its own that only root can enter, so that no
+// other user of the machine can replace it between its transfer and its run as root
+type remoteInstaller struct {
+	conn *ssh.Client
+	// dir is the directory of the installer, made by mktemp as root
+	dir  string
+	path string
+	// staging is the directory of the user of the connection an upload is written to before root takes it over
+	staging string
+	// checksum is the SHA-256 of an uploaded installer, checked again right before it runs
+	checksum string
+}
+
+// newRemoteInstaller makes the directory of the installer named name on the machine
+func newRemoteInstaller(ctx context.Context, conn *ssh.Client, name string) (*remoteInstaller, error) {
+	dir, err := remoteTempDir(ctx, conn, "sudo mktemp -d")
+	if err != nil {
+		return nil, fmt.Errorf("could not create installer directory: %w", err)
+	}
+	return &remoteInstaller{conn: conn, dir: dir, path: dir + "/" + name}, nil
+}
+
+// remoteTempDir runs a mktemp command making a directory, returning the directory
+func remoteTempDir(ctx context.Context, conn *ssh.Client, cmd string) (string, error) {
+	output, err := outputSSHCommand(ctx, conn, cmd)
+	if err != nil {
+		return "", err
+	}
+	dir := strings.TrimSpace(output)
+	if !path.IsAbs(dir) || strings.ContainsAny(dir, "\n") {
+		return "", fmt.Errorf("unexpected output of mktemp: %q", output)
+	}
+	return dir, nil
+}
+
+// upload uploads the installer over scp and gives it to root, checking the checksum of the copy root owns
+func (i *remoteInstaller) upload(ctx context.Context, installer []byte) error {
+	// scp runs as the user of the connection, which can not write to the directory of the installer
+	staging, err := remoteTempDir(ctx, i.conn, "mktemp -d")
+	if err != nil {
+		return fmt.Errorf("could not create upload directory: %w", err)
+	}
+	i.staging = staging
+	staged := staging + "/" + path.Base(i.path)
+	log.Printf("Uploading installer of %d bytes\n", len(installer))
+	err = uploadSSHFile(ctx, i.conn, staged, 0700, installer)
+	if err != nil {
+		return fmt.Errorf("could not upload installer: %w", err)
+	}
+	err = runSSHCommand(ctx, i.conn, fmt.Sprintf("sudo install -o root -g root -m 0700 %s %s", shellQuote(staged), shellQuote(i.path)))
+	if err != nil {
+		return fmt.Errorf("could not install uploaded installer: %w", err)
+	}
+	checksum := sha256.Sum256(installer)
+	i.checksum = hex.EncodeToString(checksum[:])
+	return i.verify(ctx)
+}
+
+// verify checks that an uploaded installer still has the checksum of the installer downloaded. Installers the
+// machine downloaded itself have no checksum to check.
+func (i *remoteInstaller) verify(ctx context.Context) error {
+	if len(i.checksum) == 0 {
+		return nil
+	}
+	output, err := outputSSHCommand(ctx, i.conn, "sudo sha256sum "+shellQuote(i.path))
+	if err != nil {
+		return fmt.Errorf("could not checksum uploaded installer: %w", err)
+	}
+	if fields := strings.Fields(output); len(fields) == 0 || fields[0] != i.checksum {
+		return fmt.Errorf("the checksum of the uploaded installer (%s) does not match the checksum of the installer downloaded (%s)", strings.TrimSpace(output), i.checksum)
+	}
+	return nil
+}
+
+// remove removes the installer and its directories once it has run, or failed to get there or to run. It runs even
+// when the install was interrupted, so it does not take the context of the install.
+func (i *remoteInstaller) remove() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	cmd := "sudo rm -rf " + shellQuote(i.dir)
+	if len(i.staging) > 0 {
+		cmd += " " + shellQuote(i.staging)
+	}
+	if err := runSSHCommand(ctx, i.conn, cmd); err != nil {
+		log.Println("could not remove installer: " + err.Error())
+	}
+}
+
+// scpReply reads the reply of scp to a message, a zero byte or an error message
+func scpReply(replies *bufio.Reader) error {
+	reply, err := replies.ReadByte()
+	if err != nil {
+		return fmt.Errorf("scp: %w", err)
+	}
+	if reply == 0 {
+		return nil
+	}
+	message, _ := replies.ReadString('\n')
+	return fmt.Errorf("scp: %s", strings.TrimSpace(message))
+}
+
+// runSSHSession calls f with a new session on the connection, interrupting the session when ctx is done
+func runSSHSession(ctx context.Context, conn *ssh.Client, f func(session *ssh.Session) error) error {
+	session, err := conn.NewSession()
+	if err != nil {
+		return fmt.Errorf("could not create ssh session: %w", err)
+	}
+	defer session.Close()
+	if _, ok := forwardedAgents.Load(conn); ok {
+		if err = agent.RequestAgentForwarding(session); err != nil {
+			return fmt.Errorf("could not forward the ssh agent, check that AllowAgentForwarding is enabled on the machine: %w", err)
+		}
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- f(session)
+	}()
+	select {
+	case err = <-done:
+		return err
+	case <-ctx.Done():
+		_ = session.Signal(ssh.SIGINT)
+		session.Close()
+		return ctx.Err()
+	}
+}
+
+// shellQuote quotes a value as a single word for the shell of the machine
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,142 @@
+package xsoar
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// defaultTimeout bounds an operation that the timeouts block of a resource does not set, and the reads of data
+// sources, which have no timeouts block
+const defaultTimeout = 20 * time.Minute
+
+// installTimeout bounds the creation of the resources installed over ssh or provisioned by the server, which take
+// longer than the API calls of the other resources
+const installTimeout = 30 * time.Minute
+
+// timeoutOperations are the operations a timeouts block sets
+var timeoutOperations = []string{"create", "read", "update", "delete"}
+
+// timeoutsBlock returns the timeouts block of every resource, setting how long each operation may take as a
+// duration such as "30m"
+func timeoutsBlock() tfsdk.Block {
+	attributes := map[string]tfsdk.Attribute{}
+	for _, operation := range timeoutOperations {
+		attributes[operation] = tfsdk.Attribute{
+			Type:       types.StringType,
+			Optional:   true,
+			Validators: []tfsdk.AttributeValidator{isDuration{}},
+		}
+	}
+	return tfsdk.Block{
+		NestingMode: tfsdk.BlockNestingModeList,
+		MaxItems:    1,
+		Attributes:  attributes,
+	}
+}
+
+// isDuration validates that a string attribute is a positive duration such as "30m"
+type isDuration struct{}
+
+func (v isDuration) Description(_ context.Context) string {
+	return "value must be a positive duration such as 30s, 10m or 1h"
+}
+
+func (v isDuration) MarkdownDescription(_ context.Context) string {
+	return "value must be a positive duration such as `30s`, `10m` or `1h`"
+}
+
+func (v isDuration) Validate(ctx context.Context, request tfsdk.ValidateAttributeRequest, response *tfsdk.ValidateAttributeResponse) {
+	var str types.String
+	diags := tfsdk.ValueAs(ctx, request.AttributeConfig, &str)
+	response.Diagnostics.Append(diags...)
+	if diags.HasError() || str.Null || str.Unknown {
+		return
+	}
+	if d, err := time.ParseDuration(str.Value); err != nil || d <= 0 {
+		response.Diagnostics.AddAttributeError(
+			request.AttributePath,
+			"Invalid Duration",
+			fmt.Sprintf("Value must be a positive duration such as 30s, 10m or 1h, got: %s.", str.Value),
+		)
+	}
+}
+
+// timeoutsGetter is a config, plan or state holding a timeouts block
+type timeoutsGetter interface {
+	GetAttribute(ctx context.Context, path path.Path, target interface{}) diag.Diagnostics
+}
+
+// withTimeout returns ctx bounded by the timeout the timeouts block of data sets for the operation, or by def when
+// it is not set, along with the diagnostics of reading the block
+func withTimeout(ctx context.Context, data timeoutsGetter, operation string, def time.Duration) (context.Context, context.CancelFunc, diag.Diagnostics) {
+	var timeouts []Timeouts
+	diags := data.GetAttribute(ctx, path.Root("timeouts"), &timeouts)
+	ctx, cancel := context.WithTimeout(ctx, operationTimeout(timeouts, operation, def))
+	return ctx, cancel, diags
+}
+
+// withCreateTimeout is withTimeout for the create of a resource that has a deprecated timeout attribute. When the
+// timeouts block does not set create, the deprecated attribute still bounds the wait it always bounded, so create
+// is given that long on top of def for the rest of its work.
+func withCreateTimeout(ctx context.Context, data timeoutsGetter, deprecated string, def time.Duration) (context.Context, context.CancelFunc, diag.Diagnostics) {
+	var timeouts []Timeouts
+	diags := data.GetAttribute(ctx, path.Root("timeouts"), &timeouts)
+	timeout := operationTimeout(timeouts, "create", 0)
+	if timeout == 0 {
+		timeout = def
+		var seconds types.Int64
+		diags.Append(data.GetAttribute(ctx, path.Root(deprecated), &seconds)...)
+		if !seconds.Null && !seconds.Unknown && seconds.Value > 0 {
+			timeout += time.Duration(seconds.Value) * time.Second
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, cancel, diags
+}
+
+// operationTimeout returns the timeout set for the operation, or def when it is not set
+func operationTimeout(timeouts []Timeouts, operation string, def time.Duration) time.Duration {
+	if len(timeouts) == 0 {
+		return def
+	}
+	value := map[string]types.String{
+		"create": timeouts[0].Create,
+		"read":   timeouts[0].Read,
+		"update": timeouts[0].Update,
+		"delete": timeouts[0].Delete,
+	}[operation]
+	if value.Null || value.Unknown {
+		return def
+	}
+	d, err := time.ParseDuration(value.Value)
+	if err != nil || d <= 0 {
+		return def
+	}
+	return d
+}
+
+// untilDeadline returns the time left before the deadline of ctx, for the retry loops that take a timeout
+func untilDeadline(ctx context.Context) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+	return defaultTimeout
+}
+
+// sleepContext waits for d, returning the error of ctx early when it is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
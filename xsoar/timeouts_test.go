@@ -0,0 +1,78 @@
+package xsoar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// mockTimeoutsPlan is a plan with a timeouts block and a deprecated timeout attribute
+type mockTimeoutsPlan struct {
+	timeouts []Timeouts
+	seconds  types.Int64
+	// invalid fails reading the attributes
+	invalid bool
+}
+
+func (p mockTimeoutsPlan) GetAttribute(_ context.Context, at path.Path, target interface{}) diag.Diagnostics {
+	if p.invalid {
+		var diags diag.Diagnostics
+		diags.AddAttributeError(at, "Value Conversion Error", "mock conversion error")
+		return diags
+	}
+	switch t := target.(type) {
+	case *[]Timeouts:
+		*t = p.timeouts
+	case *types.Int64:
+		*t = p.seconds
+	}
+	return nil
+}
+
+func TestWithCreateTimeout(t *testing.T) {
+	create := func(value string) []Timeouts {
+		return []Timeouts{{
+			Create: types.String{Value: value},
+			Read:   types.String{Null: true},
+			Update: types.String{Null: true},
+			Delete: types.String{Null: true},
+		}}
+	}
+	for _, c := range []struct {
+		name string
+		plan mockTimeoutsPlan
+		want time.Duration
+	}{
+		{"default", mockTimeoutsPlan{seconds: types.Int64{Null: true}}, installTimeout},
+		{"deprecated timeout", mockTimeoutsPlan{seconds: types.Int64{Value: 7200}}, installTimeout + 2*time.Hour},
+		{"create timeout", mockTimeoutsPlan{timeouts: create("10m"), seconds: types.Int64{Null: true}}, 10 * time.Minute},
+		{"create timeout and deprecated timeout", mockTimeoutsPlan{timeouts: create("10m"), seconds: types.Int64{Value: 7200}}, 10 * time.Minute},
+	} {
+		ctx, cancel, diags := withCreateTimeout(context.Background(), c.plan, "timeout", installTimeout)
+		deadline, _ := ctx.Deadline()
+		cancel()
+		if diags.HasError() {
+			t.Fatalf("%s: %v", c.name, diags)
+		}
+		if got := time.Until(deadline); got > c.want || got < c.want-time.Minute {
+			t.Fatalf("%s: expected a deadline in %s, got %s", c.name, c.want, got)
+		}
+	}
+}
+
+func TestWithTimeout_diagnostics(t *testing.T) {
+	_, cancel, diags := withTimeout(context.Background(), mockTimeoutsPlan{invalid: true}, "read", defaultTimeout)
+	cancel()
+	if !diags.HasError() {
+		t.Fatal("expected the error reading the timeouts block to be returned")
+	}
+	_, cancel, diags = withCreateTimeout(context.Background(), mockTimeoutsPlan{invalid: true}, "timeout", installTimeout)
+	cancel()
+	if len(diags) != 2 {
+		t.Fatalf("expected the errors reading both timeouts to be returned, got %v", diags)
+	}
+}
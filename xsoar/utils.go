@@ -1,5 +1,16 @@
 package xsoar
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
 func equalSliceString(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
@@ -11,3 +22,117 @@ func equalSliceString(a, b []string) bool {
 	}
 	return true
 }
+
+// isOneOf validates that a string attribute is one of the given values
+type isOneOf struct {
+	values []string
+}
+
+func (v isOneOf) Description(_ context.Context) string {
+	return fmt.Sprintf("value must be one of %s", strings.Join(v.values, ", "))
+}
+
+func (v isOneOf) MarkdownDescription(_ context.Context) string {
+	return fmt.Sprintf("value must be one of `%s`", strings.Join(v.values, "`, `"))
+}
+
+func (v isOneOf) Validate(ctx context.Context, request tfsdk.ValidateAttributeRequest, response *tfsdk.ValidateAttributeResponse) {
+	var str types.String
+	diags := tfsdk.ValueAs(ctx, request.AttributeConfig, &str)
+	response.Diagnostics.Append(diags...)
+	if diags.HasError() || str.Null || str.Unknown {
+		return
+	}
+	for _, value := range v.values {
+		if str.Value == value {
+			return
+		}
+	}
+	response.Diagnostics.AddAttributeError(
+		request.AttributePath,
+		"Invalid Value",
+		fmt.Sprintf("Value must be one of %s, got: %s.", strings.Join(v.values, ", "), str.Value),
+	)
+}
+
+// optionalString maps an empty API value to null unless the practitioner set it explicitly
+func optionalString(value string, prior types.String) types.String {
+	if len(value) > 0 || (!prior.Null && !prior.Unknown) {
+		return types.String{Value: value}
+	}
+	return types.String{Null: true}
+}
+
+// optionalBool maps a false API value to null unless the practitioner set it explicitly
+func optionalBool(value bool, prior types.Bool) types.Bool {
+	if value || (!prior.Null && !prior.Unknown) {
+		return types.Bool{Value: value}
+	}
+	return types.Bool{Null: true}
+}
+
+// stringSet converts a list of strings from the API into a set attribute
+func stringSet(values []interface{}) types.Set {
+	elems := []attr.Value{}
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			elems = append(elems, types.String{Value: s})
+		}
+	}
+	return types.Set{Elems: elems, ElemType: types.StringType}
+}
+
+// interfaceSlice returns v as a list, or an empty list when the API omitted it
+func interfaceSlice(v interface{}) []interface{} {
+	if list, ok := v.([]interface{}); ok {
+		return list
+	}
+	return []interface{}{}
+}
+
+// stringList converts a list of strings from the API into a list attribute, an empty list being
+// null unless the practitioner set it explicitly
+func stringList(values []interface{}, prior types.List) types.List {
+	if len(values) == 0 && (prior.Null || prior.Unknown) {
+		return types.List{Null: true, ElemType: types.StringType}
+	}
+	elems := []attr.Value{}
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			elems = append(elems, types.String{Value: s})
+		}
+	}
+	return types.List{Elems: elems, ElemType: types.StringType}
+}
+
+// normalizeJSON re-encodes a JSON object with sorted keys and without the given keys so that
+// formatting and server side bookkeeping do not show up as changes
+func normalizeJSON(content string, ignore ...string) (string, error) {
+	var object interface{}
+	if err := json.Unmarshal([]byte(content), &object); err != nil {
+		return "", err
+	}
+	if m, ok := object.(map[string]interface{}); ok {
+		for _, key := range ignore {
+			delete(m, key)
+		}
+	}
+	b, err := json.Marshal(object)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// equalJSON reports whether two JSON documents only differ in formatting or the given keys
+func equalJSON(a, b string, ignore ...string) bool {
+	normalizedA, err := normalizeJSON(a, ignore...)
+	if err != nil {
+		return false
+	}
+	normalizedB, err := normalizeJSON(b, ignore...)
+	if err != nil {
+		return false
+	}
+	return normalizedA == normalizedB
+}